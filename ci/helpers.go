@@ -0,0 +1,29 @@
+package ci
+
+import (
+	"regexp"
+	"strings"
+)
+
+var notIdentifier = regexp.MustCompile("[^0-9A-Za-z-]+")
+
+func env(name string) func(getenv func(string) string) string {
+	return func(getenv func(string) string) string {
+		return getenv(name)
+	}
+}
+
+// join builds an url from its parts, returning "" if any part is missing.
+func join(parts ...string) string {
+	for i, p := range parts {
+		if p == "" {
+			return ""
+		}
+		parts[i] = strings.Trim(p, "/")
+	}
+	return strings.Join(parts, "/")
+}
+
+func identifier(st string) string {
+	return strings.Trim(notIdentifier.ReplaceAllString(st, "-"), "-")
+}
@@ -0,0 +1,66 @@
+package ci
+
+var providers = []provider{
+	{
+		name: "github", detect: "GITHUB_ACTIONS", commit: "GITHUB_SHA", runID: "GITHUB_RUN_ID", branch: "GITHUB_REF_NAME",
+		url: func(getenv func(string) string) string {
+			return join(getenv("GITHUB_SERVER_URL"), getenv("GITHUB_REPOSITORY"), "actions/runs", getenv("GITHUB_RUN_ID"))
+		},
+	},
+	{
+		name: "gitlab", detect: "GITLAB_CI", commit: "CI_COMMIT_SHA", runID: "CI_PIPELINE_ID", branch: "CI_COMMIT_REF_NAME",
+		url: env("CI_PIPELINE_URL"),
+	},
+	{
+		name: "circleci", detect: "CIRCLECI", commit: "CIRCLE_SHA1", runID: "CIRCLE_BUILD_NUM", branch: "CIRCLE_BRANCH",
+		url: env("CIRCLE_BUILD_URL"),
+	},
+	{
+		name: "buildkite", detect: "BUILDKITE", commit: "BUILDKITE_COMMIT", runID: "BUILDKITE_BUILD_NUMBER", branch: "BUILDKITE_BRANCH",
+		url: env("BUILDKITE_BUILD_URL"),
+	},
+	{
+		name: "travis", detect: "TRAVIS", commit: "TRAVIS_COMMIT", runID: "TRAVIS_BUILD_NUMBER", branch: "TRAVIS_BRANCH",
+		url: env("TRAVIS_BUILD_WEB_URL"),
+	},
+	{
+		name: "azure", detect: "TF_BUILD", commit: "BUILD_SOURCEVERSION", runID: "BUILD_BUILDID", branch: "BUILD_SOURCEBRANCHNAME",
+		url: func(getenv func(string) string) string {
+			if getenv("SYSTEM_COLLECTIONURI") == "" {
+				return ""
+			}
+			return join(getenv("SYSTEM_COLLECTIONURI"), getenv("SYSTEM_TEAMPROJECT"), "_build/results?buildId="+getenv("BUILD_BUILDID"))
+		},
+	},
+	{
+		name: "bitbucket", detect: "BITBUCKET_BUILD_NUMBER", commit: "BITBUCKET_COMMIT", runID: "BITBUCKET_BUILD_NUMBER", branch: "BITBUCKET_BRANCH",
+		url: func(getenv func(string) string) string {
+			return join(getenv("BITBUCKET_GIT_HTTP_ORIGIN"), "addon/pipelines/home#!/results", getenv("BITBUCKET_BUILD_NUMBER"))
+		},
+	},
+	{
+		name: "jenkins", detect: "JENKINS_URL", commit: "GIT_COMMIT", runID: "BUILD_NUMBER", branch: "GIT_BRANCH",
+		url: env("BUILD_URL"),
+	},
+}
+
+// Detect identifies the CI provider from the environment. Outside CI it
+// returns nil; an unknown provider setting CI yields a "generic" result.
+func Detect(getenv func(string) string) *Class {
+	for _, p := range providers {
+		if getenv(p.detect) == "" {
+			continue
+		}
+		return &Class{
+			Provider: p.name,
+			Commit:   getenv(p.commit),
+			RunID:    getenv(p.runID),
+			BuildURL: p.url(getenv),
+			Branch:   getenv(p.branch),
+		}
+	}
+	if getenv("CI") != "" {
+		return &Class{Provider: "generic"}
+	}
+	return nil
+}
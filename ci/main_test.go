@@ -0,0 +1,51 @@
+package ci
+
+import (
+	"gov/version"
+	"testing"
+)
+
+func tEnv(vars map[string]string) func(string) string {
+	return func(k string) string {
+		return vars[k]
+	}
+}
+
+func TestDetect_none(t *testing.T) {
+	if Detect(tEnv(nil)) != nil {
+		t.Fail()
+	}
+}
+
+func TestDetect_github(t *testing.T) {
+	c := Detect(tEnv(map[string]string{
+		"CI":                "true",
+		"GITHUB_ACTIONS":    "true",
+		"GITHUB_SHA":        "0123456789abcdef",
+		"GITHUB_RUN_ID":     "42",
+		"GITHUB_SERVER_URL": "https://github.com",
+		"GITHUB_REPOSITORY": "acme/tool",
+		"GITHUB_REF_NAME":   "main",
+	}))
+	if c == nil || c.Provider != "github" || c.Branch != "main" {
+		t.Fatal("github not detected")
+	}
+	if c.BuildURL != "https://github.com/acme/tool/actions/runs/42" {
+		t.Fail()
+	}
+	v := version.MustNew("1.2.0")
+	if err := c.Stamp(v); err != nil || v.String() != "1.2.0+sha.0123456.run.42" {
+		t.Fail()
+	}
+}
+
+func TestDetect_generic(t *testing.T) {
+	c := Detect(tEnv(map[string]string{"CI": "1"}))
+	if c == nil || c.Provider != "generic" || c.BuildURL != "" {
+		t.Fail()
+	}
+	v := version.MustNew("1.2.0+keep")
+	if err := c.Stamp(v); err != nil || v.Metadata != "keep" {
+		t.Fail()
+	}
+}
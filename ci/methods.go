@@ -0,0 +1,29 @@
+package ci
+
+import "gov/version"
+
+// Metadata returns the build metadata identifiers tracing a version back to
+// the CI run: sha.<short commit>.run.<run id>
+func (that *Class) Metadata() []string {
+	var ids []string
+	if sha := identifier(that.Commit); sha != "" {
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		ids = append(ids, "sha", sha)
+	}
+	if run := identifier(that.RunID); run != "" {
+		ids = append(ids, "run", run)
+	}
+	return ids
+}
+
+// Stamp sets the CI build metadata on v, keeping v's metadata when the
+// provider exposes no commit or run information.
+func (that *Class) Stamp(v *version.Class) error {
+	ids := that.Metadata()
+	if len(ids) == 0 {
+		return nil
+	}
+	return v.SetMetadata(ids...)
+}
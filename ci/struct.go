@@ -0,0 +1,18 @@
+package ci
+
+type Class struct {
+	Provider string
+	Commit   string
+	RunID    string
+	BuildURL string
+	Branch   string
+}
+
+type provider struct {
+	name   string
+	detect string
+	commit string
+	runID  string
+	url    func(getenv func(string) string) string
+	branch string
+}
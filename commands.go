@@ -0,0 +1,947 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"gov/inventory"
+	"gov/lib"
+	"gov/manifest"
+	"gov/pkginfo"
+	"gov/server"
+	"gov/version"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// watchDebounce is how long gopi readme -watch waits for the saves to settle.
+const watchDebounce = 100 * time.Millisecond
+
+type command func(ctx context.Context, gopi *lib.Class, root string, args []string) error
+
+var commands = map[string]command{
+	"init":      initCmd,
+	"readme":    readmeCmd,
+	"generate":  generateCmd,
+	"sync":      syncCmd,
+	"ci-detect": ciDetectCmd,
+	"bump":      bumpCmd,
+	"validate":  validateCmd,
+	"restore":   restoreCmd,
+	"fetch":     fetchCmd,
+	"verify":    verifyCmd,
+	"semver":    semverCmd,
+	"show":      showCmd,
+	"audit":     auditCmd,
+	"set":       setCmd,
+	"license":   licenseCmd,
+	"deps":      depsCmd,
+	"build":     buildCmd,
+	"tag":       tagCmd,
+	"check":     checkCmd,
+	"release":   releaseCmd,
+	"promote":   promoteCmd,
+	"sbom":      sbomCmd,
+	"export":    exportCmd,
+	"import":    importCmd,
+	"lint":      lintCmd,
+	"list":      listCmd,
+	"inventory": inventoryCmd,
+	"serve":     serveCmd,
+	"icon":      iconCmd,
+	"archive":   archiveCmd,
+}
+
+func initCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	force := fs.Bool("force", false, "Overwrite an existing package info file without asking")
+	noClobber := fs.Bool("no-clobber", false, "Fail instead of overwriting an existing package info file")
+	answersFile := fs.String("answers", "", "Read the answers from this yaml file, - for stdin, instead of prompting")
+	scaffold := fs.Bool("scaffold", false, "Also generate go.mod, main.go (or a library skeleton), .gitignore, LICENSE and a Makefile")
+	from := fs.String("from", "", "Project template, a directory or git url, bringing pkg.info defaults and files")
+	_ = fs.Parse(args)
+	if *force && *noClobber {
+		return errors.New("-force and -no-clobber can't be combined")
+	}
+
+	if *noClobber && gopi.HasPackage(root) {
+		return fmt.Errorf("%s already exists", gopi.PkgFile(root))
+	}
+	tplDir := ""
+	if *from != "" {
+		dir, cleanup, err := gopi.FetchProject(ctx, *from)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		if err = gopi.ProjectDefaults(dir); err != nil {
+			return err
+		}
+		tplDir = dir
+	}
+	// without a terminal the answers come from a file, stdin or GOPI_PKG_<FIELD>
+	interactive := lib.IsTerminal(os.Stdin)
+	var err error
+	if *answersFile == "" && interactive {
+		err = gopi.PromptPkg(ctx, root, *force)
+	} else {
+		err = initFromAnswers(ctx, gopi, root, *answersFile, *force)
+	}
+	if err == nil && tplDir != "" {
+		err = gopi.InstantiateProject(ctx, tplDir, root)
+	}
+	if err != nil || !*scaffold {
+		return err
+	}
+	return gopi.Scaffold(ctx, root)
+}
+
+// initFromAnswers runs init with the answers of answersFile, stdin when - or
+// empty.
+func initFromAnswers(ctx context.Context, gopi *lib.Class, root string, answersFile string, force bool) error {
+	var raw []byte
+	var err error
+	switch {
+	case answersFile == "-" || answersFile == "":
+		raw, err = io.ReadAll(os.Stdin)
+	default:
+		raw, err = os.ReadFile(answersFile)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read the answers: %w", err)
+	}
+	answers, err := lib.ParseAnswers(raw)
+	if err != nil {
+		return err
+	}
+	return gopi.InitPkg(ctx, root, answers, force)
+}
+
+func readmeCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("readme", flag.ExitOnError)
+	check := fs.Bool("check", false, "Only check that the README is up to date, fail when it is stale")
+	output := fs.String("output", "", "Write the README to this file, or to stdout with -, instead of the configured readmeFile")
+	watch := fs.Bool("watch", false, "Regenerate the README every time pkg.info or the template changes, until interrupted")
+	_ = fs.Parse(args)
+	if *check && *output != "" {
+		return errors.New("-check and -output can't be combined")
+	}
+	if *watch && (*check || *output != "") {
+		return errors.New("-watch can't be combined with -check or -output")
+	}
+
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	switch {
+	case *watch:
+		return gopi.WatchReadme(ctx, root, watchDebounce)
+	case *output == "-":
+		raw, err := gopi.RenderReadme(ctx, root)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(raw)
+		return err
+	case *output != "":
+		pth, err := filepath.Abs(*output)
+		if err != nil {
+			return err
+		}
+		if err = os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+			return err
+		}
+		return gopi.CreateReadmeAt(ctx, root, pth, !lib.IsTerminal(os.Stdin))
+	case !*check:
+		return gopi.CreateReadme(ctx, root, !lib.IsTerminal(os.Stdin))
+	}
+	stale, err := gopi.ReadmeStale(ctx, root)
+	if err != nil {
+		return err
+	}
+	if stale {
+		return errors.New("the README is stale, run gopi readme to regenerate it")
+	}
+	fmt.Println("The README is up to date.")
+	return nil
+}
+
+func generateCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	force := fs.Bool("force", false, "Overwrite the generated file if it already exists")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("usage: gopi generate [-force] makefile|justfile|version")
+	}
+
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	return gopi.Generate(ctx, root, fs.Arg(0), *force)
+}
+
+func syncCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	changed, err := gopi.SyncRepo(ctx, root)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		fmt.Println("Repository url is already in sync with the git remote.")
+		return nil
+	}
+	fmt.Printf("Repository url updated to %s\n", gopi.Repo)
+	return gopi.CreatePkg(root)
+}
+
+func ciDetectCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("ci-detect", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	c := gopi.CI()
+	if c == nil {
+		fmt.Println("provider=none")
+		return errors.New("no CI environment detected")
+	}
+	fmt.Printf("provider=%s\ncommit=%s\nrun_id=%s\nbuild_url=%s\nbranch=%s\n",
+		c.Provider, c.Commit, c.RunID, c.BuildURL, c.Branch)
+
+	if !gopi.HasPackage(root) {
+		return nil
+	}
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	v, err := gopi.BuildVersion()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("version=%s\n", v)
+	return nil
+}
+
+func bumpCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("bump", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "Print the new version without rewriting the package info file")
+	keepPre := fs.Bool("keep-pre", false, "Keep the prerelease tag (major, minor, patch)")
+	keepMeta := fs.Bool("keep-meta", false, "Keep the build metadata")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("usage: gopi bump [-dry-run] [-keep-pre] [-keep-meta] major|minor|patch|pre")
+	}
+
+	var opts []version.IncOption
+	if *keepPre {
+		opts = append(opts, version.KeepPrerelease)
+	}
+	if *keepMeta {
+		opts = append(opts, version.KeepMetadata)
+	}
+
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	if !*dryRun {
+		if err := gopi.Guard(ctx, root); err != nil {
+			return err
+		}
+	}
+	old := gopi.Version
+	v, err := gopi.Bump(fs.Arg(0), opts...)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s -> %s\n", old, v)
+	if *dryRun {
+		return nil
+	}
+	return gopi.CreatePkg(root)
+}
+
+func validateCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Print the diagnostics as JSON")
+	_ = fs.Parse(args)
+
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	diags := gopi.Validate(ctx)
+	if *asJSON {
+		if diags == nil {
+			diags = []lib.Diagnostic{}
+		}
+		raw, err := json.MarshalIndent(diags, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(raw))
+	} else {
+		for _, d := range diags {
+			fmt.Printf("%s: %s\n", gopi.PkgFile(root), d.String())
+		}
+	}
+	if len(diags) > 0 {
+		return fmt.Errorf("%d problem(s) found", len(diags))
+	}
+	if !*asJSON {
+		fmt.Printf("%s is valid.\n", gopi.PkgFile(root))
+	}
+	return nil
+}
+
+func restoreCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	restored, err := gopi.Restore(root, fs.Args()...)
+	for _, pth := range restored {
+		fmt.Printf("%s restored from backup\n", pth)
+	}
+	return err
+}
+
+func fetchCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("fetch", flag.ExitOnError)
+	out := fs.String("o", "", "Save the package info to this file instead of printing it")
+	asJSON := fs.Bool("json", false, "Print the package info as JSON")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("usage: gopi fetch [-json] [-o file] tenant/name[@version]")
+	}
+
+	info, err := gopi.Fetch(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *out != "" {
+		if err = pkginfo.Save(*out, info); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "%s saved to %s\n", fs.Arg(0), *out)
+		return nil
+	}
+	format := "yaml"
+	if *asJSON {
+		format = "json"
+	}
+	raw, err := pkginfo.Marshal(info, format)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(raw))
+	return nil
+}
+
+func verifyCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	_ = fs.Parse(args)
+	switch {
+	case fs.Arg(0) == "checksums" && fs.NArg() <= 2:
+		return verifyChecksums(gopi, root, fs.Arg(1))
+	case fs.Arg(0) != "toolchain" || fs.NArg() != 1:
+		return errors.New("usage: gopi verify toolchain|checksums [dir]")
+	}
+
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	failed := 0
+	for _, c := range gopi.VerifyToolchain(ctx, root) {
+		if c.OK {
+			fmt.Printf("ok   %-14s %-7s %s\n", c.Target, c.Tool, c.Detail)
+			continue
+		}
+		failed++
+		fmt.Printf("FAIL %-14s %-7s %s\n", c.Target, c.Tool, c.Hint)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d toolchain prerequisite(s) missing", failed)
+	}
+	return nil
+}
+
+func verifyChecksums(gopi *lib.Class, root string, dir string) error {
+	if dir == "" {
+		dir = gopi.BuildDir(root)
+	} else if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	results, err := gopi.VerifyChecksums(dir)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, r := range results {
+		if r.OK {
+			fmt.Printf("ok   %-7s %s\n", r.Algorithm, r.File)
+			continue
+		}
+		failed++
+		fmt.Printf("FAIL %-7s %s: %s\n", r.Algorithm, r.File, r.Err.Error())
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checksum(s) failed", failed, len(results))
+	}
+	return nil
+}
+
+func semverCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("semver", flag.ExitOnError)
+	explain := fs.Bool("explain", false, "Explain the outcome of the comparison")
+	if len(args) == 0 || args[0] != "compare" {
+		return errors.New("usage: gopi semver compare [-explain] <version> <version>")
+	}
+	_ = fs.Parse(args[1:])
+	if fs.NArg() != 2 {
+		return errors.New("usage: gopi semver compare [-explain] <version> <version>")
+	}
+
+	a, err := version.New(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	b, err := version.New(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	c, why := a.Explain(b)
+	symbol := map[int]string{-1: "<", 0: "=", 1: ">"}[c]
+	if *explain {
+		fmt.Printf("%s %s %s: %s\n", a, symbol, b, why)
+		return nil
+	}
+	fmt.Printf("%s %s %s\n", a, symbol, b)
+	return nil
+}
+
+func showCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("show", flag.ExitOnError)
+	get := fs.String("get", "", "Print only this field, e.g. version, .tenant or .arch[0]")
+	asJSON := fs.Bool("json", false, "Print the package info as JSON")
+	_ = fs.Parse(args)
+
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	if *get != "" {
+		v, err := pkginfo.Field(&gopi.Info, *get)
+		if err != nil {
+			return err
+		}
+		fmt.Println(v)
+		return nil
+	}
+	format := "yaml"
+	if *asJSON {
+		format = "json"
+	}
+	raw, err := pkginfo.Marshal(&gopi.Info, format)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(raw))
+	return nil
+}
+
+func auditCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("audit", flag.ExitOnError)
+	fix := fs.Bool("fix", false, "Correct the problems found")
+	_ = fs.Parse(args)
+
+	findings, err := gopi.Audit(root, *fix)
+	if err != nil {
+		return err
+	}
+	open := 0
+	for _, f := range findings {
+		fmt.Println(f.String())
+		if !f.Fixed {
+			open++
+		}
+	}
+	if open > 0 {
+		return fmt.Errorf("%d problem(s) found in the generated files", open)
+	}
+	if len(findings) == 0 {
+		fmt.Println("Generated files pass the audit.")
+	}
+	return nil
+}
+
+func setCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("set", flag.ExitOnError)
+	_ = fs.Parse(args)
+	if fs.NArg() != 2 {
+		return errors.New("usage: gopi set <field> <value>")
+	}
+
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	if err := gopi.Set(fs.Arg(0), fs.Arg(1)); err != nil {
+		return err
+	}
+	return gopi.CreatePkg(root)
+}
+
+func licenseCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("license", flag.ExitOnError)
+	force := fs.Bool("force", false, "Overwrite the LICENSE file if it already exists")
+	_ = fs.Parse(args)
+
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	return gopi.CreateLicense(ctx, root, *force)
+}
+
+func depsCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("deps", flag.ExitOnError)
+	resolve := fs.Bool("resolve", false, "Use the versions selected by go list -m all instead of the go.mod ones")
+	dryRun := fs.Bool("dry-run", false, "Print the dependencies without rewriting the package info file")
+	_ = fs.Parse(args)
+
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	deps, err := gopi.Deps(ctx, root, *resolve)
+	if err != nil {
+		return err
+	}
+	for _, d := range deps {
+		fmt.Printf("%s %s\n", d.Path, d.Version)
+	}
+	if *dryRun {
+		return nil
+	}
+	gopi.Dependencies = deps
+	return gopi.CreatePkg(root)
+}
+
+func buildCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	parallel := fs.Int("parallel", runtime.NumCPU(), "Number of targets compiled at the same time")
+	_ = fs.Parse(args)
+
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	if err := gopi.Gate(ctx, root); err != nil {
+		return err
+	}
+	started := time.Now()
+	results := gopi.Build(ctx, root, *parallel)
+	var failed, binaries []string
+	for _, r := range results {
+		if r.Log != "" {
+			fmt.Printf("==> %s\n%s\n", r.Target, r.Log)
+		}
+	}
+	for _, r := range results {
+		if r.Err == nil {
+			binaries = append(binaries, r.Binary)
+			fmt.Printf("ok   %-16s %s\n", r.Target, r.Binary)
+			continue
+		}
+		failed = append(failed, r.Target)
+		fmt.Printf("FAIL %-16s %s\n", r.Target, r.Err.Error())
+	}
+	for _, c := range gopi.Compress(ctx, results) {
+		fmt.Printf("upx  %-16s %s\n", c.Target, c.String())
+	}
+	sboms, err := gopi.WriteBinarySBOMs(root, binaries)
+	if err != nil {
+		return err
+	}
+	sums, err := gopi.WriteChecksums(binaries)
+	if err != nil {
+		return err
+	}
+	for _, pth := range sums {
+		fmt.Printf("Checksums written to %s\n", pth)
+	}
+	signed := append(append(binaries, sboms...), sums...)
+	prov, err := gopi.WriteProvenance(ctx, root, binaries, started)
+	if err != nil {
+		return err
+	}
+	if prov != "" {
+		fmt.Printf("Provenance written to %s\n", prov)
+		signed = append(signed, prov)
+	}
+	sigs, err := gopi.Sign(ctx, root, signed)
+	if err != nil {
+		return err
+	}
+	if len(sigs) > 0 {
+		fmt.Printf("%d signature file(s) written\n", len(sigs))
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d target(s) failed: %s", len(failed), len(results), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func tagCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("tag", flag.ExitOnError)
+	force := fs.Bool("force", false, "Move the tag if it already exists")
+	_ = fs.Parse(args)
+
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	if err := gopi.Guard(ctx, root); err != nil {
+		return err
+	}
+	tag, err := gopi.Tag(ctx, root, *force)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Tagged %s\n", tag)
+	return nil
+}
+
+func checkCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	drift, err := gopi.Check(ctx, root)
+	if err != nil {
+		return err
+	}
+	for _, d := range drift {
+		fmt.Printf("%s: %s\n", gopi.PkgFile(root), d.String())
+	}
+	if len(drift) > 0 {
+		return fmt.Errorf("%d problem(s) found", len(drift))
+	}
+	fmt.Printf("%s is consistent with the git tags, go.mod and the README.\n", gopi.PkgFile(root))
+	return nil
+}
+
+func releaseCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("release", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "Print the release plan without changing anything")
+	push := fs.Bool("push", false, "Push the release commit and tag")
+	keepPre := fs.Bool("keep-pre", false, "Keep the prerelease tag (major, minor, patch)")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("usage: gopi [-all] release [-dry-run] [-push] [-keep-pre] major|minor|patch|pre")
+	}
+
+	var opts []version.IncOption
+	if *keepPre {
+		opts = append(opts, version.KeepPrerelease)
+	}
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	return gopi.Release(ctx, root, fs.Arg(0), *push, *dryRun, opts...)
+}
+
+func promoteCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("promote", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "Print the new version without rewriting the package info file")
+	_ = fs.Parse(args)
+	if fs.NArg() > 1 {
+		return errors.New("usage: gopi promote [-dry-run] [channel]")
+	}
+
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	old := gopi.Version
+	v, err := gopi.Promote(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s -> %s (%s)\n", old, v, gopi.Channel)
+	if *dryRun {
+		return nil
+	}
+	return gopi.CreatePkg(root)
+}
+
+func sbomCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("sbom", flag.ExitOnError)
+	format := fs.String("format", "cyclonedx", "SBOM format: cyclonedx or spdx")
+	out := fs.String("o", "", "Save the SBOM to this file instead of printing it")
+	_ = fs.Parse(args)
+	if fs.NArg() > 1 {
+		return errors.New("usage: gopi sbom [-format cyclonedx|spdx] [-o file] [binary]")
+	}
+
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	var raw []byte
+	var err error
+	if fs.NArg() == 1 {
+		raw, err = gopi.BinarySBOM(root, fs.Arg(0), *format)
+	} else {
+		raw, err = gopi.SBOM(ctx, root, *format)
+	}
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		fmt.Print(string(raw))
+		return nil
+	}
+	return gopi.WriteOutput(*out, raw)
+}
+
+func exportCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	to := fs.String("to", "", "Manifest format: "+strings.Join(manifest.Formats, ", "))
+	out := fs.String("o", "", "Save the manifest to this file instead of printing it")
+	_ = fs.Parse(args)
+	if *to == "" || fs.NArg() > 0 {
+		return errors.New("usage: gopi export -to package.json|pyproject|cargo [-o file]")
+	}
+
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	raw, err := gopi.Export(*to)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		fmt.Print(string(raw))
+		return nil
+	}
+	return gopi.WriteOutput(*out, raw)
+}
+
+func importCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	force := fs.Bool("force", false, "Overwrite an existing package info file without asking")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("usage: gopi import [-force] package.json|pyproject.toml|Cargo.toml")
+	}
+
+	pth, err := filepath.Abs(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return gopi.ImportPkg(ctx, root, pth, *force)
+}
+
+func lintCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	enable := fs.String("enable", "", "Comma separated rules to run even when disabled in the configuration")
+	disable := fs.String("disable", "", "Comma separated rules to skip")
+	list := fs.Bool("list", false, "List the lint rules")
+	_ = fs.Parse(args)
+
+	if *list {
+		for _, r := range lib.LintRules {
+			fmt.Printf("%-20s %s\n", r.Name, r.Summary)
+		}
+		return nil
+	}
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	findings, err := gopi.Lint(ctx, root, pkginfo.SplitList(*enable), pkginfo.SplitList(*disable))
+	if err != nil {
+		return err
+	}
+	for _, f := range findings {
+		fmt.Printf("%s: %s\n", gopi.PkgFile(root), f.String())
+	}
+	if len(findings) > 0 {
+		return fmt.Errorf("%d lint problem(s) found", len(findings))
+	}
+	fmt.Printf("%s passes the lint rules.\n", gopi.PkgFile(root))
+	return nil
+}
+
+func listCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	format := fs.String("format", "table", "Output format: "+strings.Join(lib.ReportFormats, ", "))
+	columns := fs.String("columns", "", "Comma separated columns to print: name, version, tenant, path, error")
+	asJSON := fs.Bool("json", false, "Print the packages as JSON, same as -format json")
+	_ = fs.Parse(args)
+	if fs.NArg() > 1 {
+		return errors.New("usage: gopi list [-format table|csv|json] [-columns list] [dir]")
+	}
+	if *asJSON {
+		*format = "json"
+	}
+
+	// the tree under the current directory, not the package it belongs to
+	dir, _ := os.Getwd()
+	if fs.NArg() == 1 {
+		dir = fs.Arg(0)
+	}
+	found, err := gopi.List(dir)
+	if err != nil {
+		return err
+	}
+	for _, l := range found {
+		if l.Error != "" {
+			fmt.Fprintf(os.Stderr, "Unable to read %s: %s\n", l.Path, l.Error)
+		}
+	}
+	return lib.ListingReport(found).Write(os.Stdout, *format, pkginfo.SplitList(*columns))
+}
+
+func inventoryCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	usage := errors.New("usage: gopi inventory refresh [dir...] | query [-tenant t] [-name glob] [-version constraint] [-format table|csv|json] [-columns list] | history [-json] [name]")
+	if len(args) == 0 {
+		return usage
+	}
+	fs := flag.NewFlagSet("inventory "+args[0], flag.ExitOnError)
+	tenant := fs.String("tenant", "", "Only the packages of this tenant")
+	name := fs.String("name", "", "Only the packages whose name matches this glob, e.g. api-*")
+	constraint := fs.String("version", "", "Only the packages whose version satisfies this constraint, e.g. ^1.2.0")
+	asJSON := fs.Bool("json", false, "Print the result as JSON")
+	format := fs.String("format", "table", "Output format of query: "+strings.Join(lib.ReportFormats, ", "))
+	columns := fs.String("columns", "", "Comma separated columns query prints: name, version, tenant, path, scanned")
+	_ = fs.Parse(args[1:])
+	if *asJSON {
+		*format = "json"
+	}
+
+	inv, err := gopi.Inventory()
+	if err != nil {
+		return err
+	}
+	var result interface{}
+	switch args[0] {
+	case "refresh":
+		dirs := fs.Args()
+		if len(dirs) == 0 {
+			dir, _ := os.Getwd()
+			dirs = []string{dir}
+		}
+		for _, dir := range dirs {
+			changes, broken, err := gopi.RefreshInventory(inv, dir)
+			if err != nil {
+				return err
+			}
+			for _, l := range broken {
+				fmt.Fprintf(os.Stderr, "Skipping %s: %s\n", filepath.Join(dir, l.Path), l.Error)
+			}
+			for _, c := range changes {
+				fmt.Println(c.String())
+			}
+		}
+		if err = inv.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("%d package(s) in the inventory.\n", len(inv.Packages))
+		return nil
+	case "query":
+		found, err := inv.Query(inventory.Query{Tenant: *tenant, Name: *name, Constraint: *constraint})
+		if err != nil {
+			return err
+		}
+		return lib.InventoryReport(found).Write(os.Stdout, *format, pkginfo.SplitList(*columns))
+	case "history":
+		if fs.NArg() > 1 {
+			return usage
+		}
+		changes := inv.Changes(fs.Arg(0))
+		if !*asJSON {
+			for _, c := range changes {
+				fmt.Println(c.String())
+			}
+			return nil
+		}
+		result = changes
+	default:
+		return usage
+	}
+	raw, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(raw))
+	return nil
+}
+
+func serveCmd(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:8080", "Address to listen on, a non loopback one needs a token")
+	token := fs.String("token", os.Getenv("GOPI_SERVE_TOKEN"), "Bearer token the requests must carry (defaults to $GOPI_SERVE_TOKEN), none when empty")
+	_ = fs.Parse(args)
+	if *token == "" && !server.Loopback(*addr) {
+		return fmt.Errorf("serving on %s without a token exposes the inventory to the network, set -token or $GOPI_SERVE_TOKEN", *addr)
+	}
+
+	srv := &http.Server{Addr: *addr, Handler: server.New(gopi, *token), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdown)
+	}()
+	fmt.Fprintf(os.Stderr, "Serving the inventory on %s\n", *addr)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func iconCmd(_ context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("icon", flag.ExitOnError)
+	bundled := fs.Bool("default", false, "Copy the icon bundled with gopi to the configured iconPath when it is missing")
+	_ = fs.Parse(args)
+	if fs.NArg() > 1 {
+		return errors.New("usage: gopi icon [-default] [icon]")
+	}
+	if *bundled {
+		if err := os.MkdirAll(filepath.Dir(gopi.IconFile(root)), 0755); err != nil {
+			return err
+		}
+		if _, err := gopi.CopyIcon(root); err != nil {
+			return err
+		}
+	}
+	raw, err := gopi.CheckIcon(root, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("The icon is a valid %s image of %d bytes.\n", http.DetectContentType(raw), len(raw))
+	return nil
+}
+
+func archiveCmd(_ context.Context, gopi *lib.Class, root string, args []string) error {
+	fs := flag.NewFlagSet("archive", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	if err := gopi.GetPackage(root); err != nil {
+		return err
+	}
+	archives, manifestPath, err := gopi.Archives(root)
+	if err != nil {
+		return err
+	}
+	for _, a := range archives {
+		fmt.Printf("%-16s %s\n", a.Target, a.File)
+	}
+	if manifestPath != "" {
+		fmt.Printf("Release manifest written to %s\n", manifestPath)
+	}
+	return nil
+}
@@ -1,18 +1,38 @@
 package config
 
 import (
+	"fmt"
 	"gopkg.in/yaml.v3"
-	"log"
+	"io/fs"
+	"os"
+	"path"
 )
 
-func New(rawConfig []byte, rawTpl []byte) *Class {
+const projectFile = ".gopi.yaml"
+
+func New(rawConfig []byte, rawTpl []byte, templates fs.FS) (*Class, error) {
 	this := Class{}
 
 	err := yaml.Unmarshal(rawConfig, &this)
 	if err != nil {
-		log.Fatalln("Unable to parse configuration file.")
+		return nil, fmt.Errorf("unable to parse configuration file: %w", err)
 	}
 
 	this.Tpl = string(rawTpl)
-	return &this
+	this.Templates = templates
+	return &this, nil
+}
+
+// UserFile returns the location of the per-user configuration file.
+func UserFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return path.Join(dir, "gopi", "config.yaml")
+}
+
+// ProjectFile returns the location of the per-project configuration file.
+func ProjectFile(root string) string {
+	return path.Join(root, projectFile)
 }
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func (that *FileMode) UnmarshalYAML(value *yaml.Node) error {
+	n, err := strconv.ParseUint(strings.TrimPrefix(value.Value, "0o"), 8, 32)
+	if err != nil || n > 0777 {
+		return fmt.Errorf("line %d: invalid file mode %q, expected an octal permission like \"0644\"", value.Line, value.Value)
+	}
+	*that = FileMode(n)
+	return nil
+}
+
+// Overlay merges the given configuration files, in order, over the embedded
+// defaults. Only the keys present in a file are overridden; missing files are skipped.
+func (that *Class) Overlay(paths ...string) error {
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		raw, err := os.ReadFile(p)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("unable to read configuration file %s: %w", p, err)
+		}
+		err = yaml.Unmarshal(raw, that)
+		if err != nil {
+			return fmt.Errorf("unable to parse configuration file %s: %w", p, err)
+		}
+	}
+	return nil
+}
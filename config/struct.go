@@ -1,9 +1,139 @@
 package config
 
+import (
+	"io/fs"
+	"os"
+)
+
 type Class struct {
-	PkgInfoFile string   `yaml:"pkgInfoFile"`
-	IconPath    string   `yaml:"iconPath"`
-	ArchList    []string `yaml:"archList"`
-	ReadmeFile  string   `yaml:"readmeFile"`
-	Tpl         string
+	PkgInfoFile         string            `yaml:"pkgInfoFile"`
+	PkgInfoFormat       string            `yaml:"pkgInfoFormat"`
+	IconPath            string            `yaml:"iconPath"`
+	IconEmbed           int               `yaml:"iconEmbed"`
+	ArchList            []string          `yaml:"archList"`
+	RecordLocalArch     bool              `yaml:"recordLocalArch"`
+	ReadmeFile          string            `yaml:"readmeFile"`
+	ReadmeTemplate      string            `yaml:"readmeTemplate"`
+	Badges              []Badge           `yaml:"badges"`
+	Tenants             []string          `yaml:"tenants"`
+	TenantsURL          string            `yaml:"tenantsURL"`
+	FileMode            FileMode          `yaml:"fileMode"`
+	SummaryLength       int               `yaml:"summaryLength"`
+	AutoAccept          bool              `yaml:"autoAccept"`
+	AssumeYes           bool              `yaml:"assumeYes"`
+	SnippetsDir         string            `yaml:"snippetsDir"`
+	ArchitectureDiagram bool              `yaml:"architectureDiagram"`
+	APIDoc              bool              `yaml:"apiDoc"`
+	Registry            string            `yaml:"registry"`
+	LicenseTextURL      string            `yaml:"licenseTextURL"`
+	BinaryName          string            `yaml:"binaryName"`
+	InstallDir          string            `yaml:"installDir"`
+	DownloadURL         string            `yaml:"downloadURL"`
+	BuildDir            string            `yaml:"buildDir"`
+	ArtifactName        string            `yaml:"artifactName"`
+	ReleaseManifest     string            `yaml:"releaseManifest"`
+	VersionPackage      string            `yaml:"versionPackage"`
+	VersionFile         VersionFile       `yaml:"versionFile"`
+	Tag                 Tag               `yaml:"tag"`
+	ChangelogFile       string            `yaml:"changelogFile"`
+	Channels            []string          `yaml:"channels"`
+	Guards              Guards            `yaml:"guards"`
+	Gates               Gates             `yaml:"gates"`
+	Checksums           Checksums         `yaml:"checksums"`
+	Signing             map[string]Signer `yaml:"signing"`
+	UPX                 UPX               `yaml:"upx"`
+	BuildSBOM           string            `yaml:"buildSBOM"`
+	Provenance          Provenance        `yaml:"provenance"`
+	Metrics             Metrics           `yaml:"metrics"`
+	Limits              Limits            `yaml:"limits"`
+	Backup              Backup            `yaml:"backup"`
+	Hooks               map[string]string `yaml:"hooks"`
+	Lint                Lint              `yaml:"lint"`
+	InventoryFile       string            `yaml:"inventoryFile"`
+	Scaffold            map[string]string `yaml:"scaffold"`
+	Tpl                 string
+	Templates           fs.FS
+}
+
+// FileMode is a permission written as an octal string in the configuration, e.g. "0644".
+type FileMode os.FileMode
+
+type Backup struct {
+	Enabled bool   `yaml:"enabled"`
+	Dir     string `yaml:"dir"`
+}
+
+// Badge is a README badge, its image and link are templates rendered with the
+// package info.
+type Badge struct {
+	Label string `yaml:"label"`
+	Image string `yaml:"image"`
+	Link  string `yaml:"link"`
+}
+
+// Tag is how gopi tag names and annotates release tags.
+type Tag struct {
+	Prefix  string `yaml:"prefix"`
+	Message string `yaml:"message"`
+}
+
+// Guards are checked before bump, tag and release.
+type Guards struct {
+	CleanTree bool     `yaml:"cleanTree"`
+	Branches  []string `yaml:"branches"`
+}
+
+// Gates are the checks gopi build and gopi release run first.
+type Gates struct {
+	Vet       bool     `yaml:"vet"`
+	Test      bool     `yaml:"test"`
+	TestFlags []string `yaml:"testFlags"`
+}
+
+// Checksums is the checksum file gopi build writes next to the binaries.
+type Checksums struct {
+	File   string `yaml:"file"`
+	SHA512 bool   `yaml:"sha512"`
+}
+
+// UPX is the compression of the binaries of some targets after gopi build.
+type UPX struct {
+	Targets []string `yaml:"targets"`
+	Flags   []string `yaml:"flags"`
+}
+
+// Signer is how the build artifacts of a tenant are signed: method gpg with
+// an optional key id, or cosign with a key file (keyless when empty).
+type Signer struct {
+	Method string `yaml:"method"`
+	Key    string `yaml:"key"`
+}
+
+// Provenance is the SLSA provenance gopi build writes next to the binaries.
+type Provenance struct {
+	File      string `yaml:"file"`
+	BuilderID string `yaml:"builderID"`
+}
+
+// VersionFile is where gopi generate version writes the package info constants.
+type VersionFile struct {
+	Path    string `yaml:"path"`
+	Package string `yaml:"package"`
+}
+
+// Lint is the configuration of gopi lint.
+type Lint struct {
+	Disable        []string `yaml:"disable"`
+	MaxDescription int      `yaml:"maxDescription"`
+}
+
+type Limits struct {
+	MaxFileSize int64 `yaml:"maxFileSize"`
+	MaxFiles    int   `yaml:"maxFiles"`
+}
+
+type Metrics struct {
+	StatsD      string `yaml:"statsd"`
+	Pushgateway string `yaml:"pushgateway"`
+	Prefix      string `yaml:"prefix"`
 }
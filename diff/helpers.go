@@ -0,0 +1,132 @@
+package diff
+
+import "strings"
+
+const context = 3
+
+// splitLines keeps the line terminators so that joining the lines gives back
+// the original content byte for byte.
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	return strings.SplitAfter(string(data), "\n")
+}
+
+func trimLast(lines []string) []string {
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// lcsOps computes a minimal line edit script using the classic longest
+// common subsequence table. Inputs are README/pkg.info sized, so O(n*m) is fine.
+func lcsOps(a, b []string) []Op {
+	n, m := len(a), len(b)
+	table := make([][]int, n+1)
+	for i := range table {
+		table[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				table[i][j] = table[i+1][j+1] + 1
+			} else if table[i+1][j] >= table[i][j+1] {
+				table[i][j] = table[i+1][j]
+			} else {
+				table[i][j] = table[i][j+1]
+			}
+		}
+	}
+
+	var ops []Op
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, Op{Equal, a[i]})
+			i++
+			j++
+		case table[i+1][j] >= table[i][j+1]:
+			ops = append(ops, Op{Delete, a[i]})
+			i++
+		default:
+			ops = append(ops, Op{Insert, b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, Op{Delete, a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, Op{Insert, b[j]})
+	}
+	return ops
+}
+
+func buildHunks(ops []Op) []*Hunk {
+	var hunks []*Hunk
+	var cur *Hunk
+	lastChange := -1
+
+	for i, op := range ops {
+		if op.Kind == Equal {
+			continue
+		}
+		if cur != nil && i-lastChange > 2*context {
+			cur.end = min(lastChange+context+1, len(ops))
+			hunks = append(hunks, cur)
+			cur = nil
+		}
+		if cur == nil {
+			cur = &Hunk{start: max(i-context, 0)}
+		}
+		lastChange = i
+	}
+	if cur != nil {
+		cur.end = min(lastChange+context+1, len(ops))
+		hunks = append(hunks, cur)
+	}
+
+	oldLine, newLine := 1, 1
+	next := 0
+	for i, op := range ops {
+		if next < len(hunks) && hunks[next].start == i {
+			hunks[next].OldStart, hunks[next].NewStart = oldLine, newLine
+		}
+		if next < len(hunks) && i >= hunks[next].start && i < hunks[next].end {
+			h := hunks[next]
+			if op.Kind != Insert {
+				h.OldLines++
+			}
+			if op.Kind != Delete {
+				h.NewLines++
+			}
+			if i == h.end-1 {
+				next++
+			}
+		}
+		if op.Kind != Insert {
+			oldLine++
+		}
+		if op.Kind != Delete {
+			newLine++
+		}
+	}
+	return hunks
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,9 @@
+package diff
+
+func New(old []byte, new []byte) *Class {
+	ops := lcsOps(trimLast(splitLines(old)), trimLast(splitLines(new)))
+	return &Class{
+		ops:   ops,
+		Hunks: buildHunks(ops),
+	}
+}
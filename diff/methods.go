@@ -0,0 +1,83 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	colorReset = "\033[0m"
+	colorRed   = "\033[31m"
+	colorGreen = "\033[32m"
+	colorCyan  = "\033[36m"
+)
+
+func (that *Class) Changed() bool {
+	return len(that.Hunks) > 0
+}
+
+// Lines returns the hunk's new side, context included, as used for editing.
+func (that *Class) Lines(h *Hunk) []string {
+	var res []string
+	for _, op := range that.ops[h.start:h.end] {
+		if op.Kind != Delete {
+			res = append(res, op.Text)
+		}
+	}
+	return res
+}
+
+// Apply rebuilds the content keeping the changes of the accepted (or edited)
+// hunks and the original lines of the rejected ones.
+func (that *Class) Apply() []byte {
+	var sb strings.Builder
+	h := 0
+	for i := 0; i < len(that.ops); i++ {
+		if h < len(that.Hunks) && i == that.Hunks[h].start {
+			hunk := that.Hunks[h]
+			for j := hunk.start; j < hunk.end; j++ {
+				op := that.ops[j]
+				switch {
+				case hunk.Replacement != nil:
+				case op.Kind == Equal,
+					op.Kind == Insert && hunk.Accepted,
+					op.Kind == Delete && !hunk.Accepted:
+					sb.WriteString(op.Text)
+				}
+			}
+			for _, l := range hunk.Replacement {
+				sb.WriteString(l)
+			}
+			i = hunk.end - 1
+			h++
+			continue
+		}
+		sb.WriteString(that.ops[i].Text)
+	}
+	return []byte(sb.String())
+}
+
+// Render formats a hunk the unified diff way, colored when color is true.
+func (that *Class) Render(h *Hunk, color bool) string {
+	paint := func(c string, st string) string {
+		if !color {
+			return st
+		}
+		return c + st + colorReset
+	}
+
+	var sb strings.Builder
+	sb.WriteString(paint(colorCyan, fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OldStart, h.OldLines, h.NewStart, h.NewLines)))
+	sb.WriteString("\n")
+	for _, op := range that.ops[h.start:h.end] {
+		line := string(op.Kind) + strings.TrimSuffix(op.Text, "\n")
+		switch op.Kind {
+		case Delete:
+			line = paint(colorRed, line)
+		case Insert:
+			line = paint(colorGreen, line)
+		}
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,72 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+const tOld = "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+const tNew = "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n"
+
+func TestNew_unchanged(t *testing.T) {
+	if New([]byte(tOld), []byte(tOld)).Changed() {
+		t.Fail()
+	}
+}
+
+func TestNew_hunks(t *testing.T) {
+	d := New([]byte(tOld), []byte(tNew))
+	if len(d.Hunks) != 2 {
+		t.Fatalf("expected 2 hunks, got %d", len(d.Hunks))
+	}
+	h := d.Hunks[0]
+	if h.OldStart != 1 || h.OldLines != 5 || h.NewStart != 1 || h.NewLines != 5 {
+		t.Fail()
+	}
+	if !strings.HasPrefix(d.Render(h, false), "@@ -1,5 +1,5 @@\n a\n-b\n+B\n") {
+		t.Fail()
+	}
+}
+
+func TestApply_all(t *testing.T) {
+	d := New([]byte(tOld), []byte(tNew))
+	for _, h := range d.Hunks {
+		h.Accepted = true
+	}
+	if string(d.Apply()) != tNew {
+		t.Fail()
+	}
+}
+
+func TestApply_none(t *testing.T) {
+	d := New([]byte(tOld), []byte(tNew))
+	if string(d.Apply()) != tOld {
+		t.Fail()
+	}
+}
+
+func TestApply_partial(t *testing.T) {
+	d := New([]byte(tOld), []byte(tNew))
+	d.Hunks[1].Accepted = true
+	if string(d.Apply()) != tOld+"m\n" {
+		t.Fail()
+	}
+}
+
+func TestApply_replacement(t *testing.T) {
+	d := New([]byte(tOld), []byte(tNew))
+	lines := d.Lines(d.Hunks[0])
+	lines[1] = "edited\n"
+	d.Hunks[0].Replacement = lines
+	if !strings.HasPrefix(string(d.Apply()), "a\nedited\nc\n") {
+		t.Fail()
+	}
+}
+
+func TestNew_no_trailing_newline(t *testing.T) {
+	d := New([]byte("a\nb"), []byte("a\nc"))
+	d.Hunks[0].Accepted = true
+	if string(d.Apply()) != "a\nc" {
+		t.Fail()
+	}
+}
@@ -0,0 +1,30 @@
+package diff
+
+const (
+	Equal  = ' '
+	Delete = '-'
+	Insert = '+'
+)
+
+type Op struct {
+	Kind byte
+	Text string
+}
+
+type Hunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Accepted bool
+	// Replacement, when set, is used verbatim instead of the hunk's new lines
+	// (context included). It is filled by interactive hunk editing.
+	Replacement []string
+	start       int
+	end         int
+}
+
+type Class struct {
+	Hunks []*Hunk
+	ops   []Op
+}
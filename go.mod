@@ -2,6 +2,14 @@ module gov
 
 go 1.19
 
-require gopkg.in/yaml.v3 v3.0.1
+require (
+	github.com/fsnotify/fsnotify v1.7.0
+	go.etcd.io/bbolt v1.3.8
+	golang.org/x/mod v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
-require gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
+require (
+	golang.org/x/sys v0.4.0 // indirect
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
+)
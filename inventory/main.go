@@ -0,0 +1,84 @@
+package inventory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	bolt "go.etcd.io/bbolt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lockTimeout is how long Open and Save wait for another gopi to release the
+// catalog.
+const lockTimeout = 5 * time.Second
+
+var (
+	packagesBucket = []byte("packages")
+	historyBucket  = []byte("history")
+)
+
+// DefaultPath is the catalog of the user, in the user cache directory.
+func DefaultPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "gopi", "inventory.db")
+}
+
+// Open reads the catalog at pth, a missing one being empty. The catalog is
+// only locked while it is read.
+func Open(pth string) (*Class, error) {
+	if pth == "" {
+		return nil, errors.New("no inventory location, set inventoryFile in the configuration")
+	}
+	this := &Class{path: pth}
+	if _, err := os.Stat(pth); errors.Is(err, fs.ErrNotExist) {
+		return this, nil
+	}
+	db, err := bolt.Open(pth, 0644, &bolt.Options{Timeout: lockTimeout, ReadOnly: true})
+	if err == nil {
+		err = db.View(this.load)
+		if cerr := db.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the inventory %s: %w", pth, err)
+	}
+	return this, nil
+}
+
+// load reads the packages, by path, and the history, in the order it was
+// recorded.
+func (that *Class) load(tx *bolt.Tx) error {
+	if b := tx.Bucket(packagesBucket); b != nil {
+		if err := b.ForEach(func(_, v []byte) error {
+			var e Entry
+			if err := json.Unmarshal(v, &e); err != nil {
+				return err
+			}
+			that.Packages = append(that.Packages, e)
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	if b := tx.Bucket(historyBucket); b != nil {
+		if err := b.ForEach(func(_, v []byte) error {
+			var c Change
+			if err := json.Unmarshal(v, &c); err != nil {
+				return err
+			}
+			that.History = append(that.History, c)
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	that.stored = len(that.History)
+	return nil
+}
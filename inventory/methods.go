@@ -0,0 +1,170 @@
+package inventory
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	bolt "go.etcd.io/bbolt"
+	"gov/version"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Refresh replaces the catalog entries under root with found, the packages
+// scanned there at now, recording what was added, removed or changed
+// version. It returns the recorded changes.
+func (that *Class) Refresh(root string, found []Entry, now time.Time) []Change {
+	old := map[string]Entry{}
+	var kept []Entry
+	for _, e := range that.Packages {
+		if under(e.Path, root) {
+			old[e.Path] = e
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	var changes []Change
+	for _, e := range found {
+		e.Scanned = now
+		kept = append(kept, e)
+		prev, ok := old[e.Path]
+		delete(old, e.Path)
+		switch {
+		case !ok:
+			changes = append(changes, Change{At: now, Name: e.Name, Tenant: e.Tenant, Path: e.Path, To: e.Version})
+		case prev.Version != e.Version:
+			changes = append(changes, Change{At: now, Name: e.Name, Tenant: e.Tenant, Path: e.Path, From: prev.Version, To: e.Version})
+		}
+	}
+	for _, e := range old {
+		changes = append(changes, Change{At: now, Name: e.Name, Tenant: e.Tenant, Path: e.Path, From: e.Version})
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Path < kept[j].Path })
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	that.Packages = kept
+	that.History = append(that.History, changes...)
+	return changes
+}
+
+// under reports whether pth is root or a directory below it.
+func under(pth string, root string) bool {
+	rel, err := filepath.Rel(root, pth)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// Query returns the entries matching q, sorted by path. A version that is not
+// semver never matches a constraint.
+func (that *Class) Query(q Query) ([]Entry, error) {
+	var c *version.Constraint
+	if q.Constraint != "" {
+		var err error
+		if c, err = version.NewConstraint(q.Constraint); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := path.Match(q.Name, ""); err != nil {
+		return nil, fmt.Errorf("invalid name pattern %q: %w", q.Name, err)
+	}
+	res := []Entry{}
+	for _, e := range that.Packages {
+		if q.Tenant != "" && !strings.EqualFold(q.Tenant, e.Tenant) {
+			continue
+		}
+		if ok, _ := path.Match(q.Name, e.Name); q.Name != "" && !ok {
+			continue
+		}
+		if c != nil {
+			if v, err := version.New(e.Version); err != nil || !c.Check(v) {
+				continue
+			}
+		}
+		res = append(res, e)
+	}
+	return res, nil
+}
+
+// Changes is the history of the packages named name, all of it when empty,
+// oldest first.
+func (that *Class) Changes(name string) []Change {
+	res := []Change{}
+	for _, c := range that.History {
+		if name == "" || c.Name == name {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// Save writes the catalog in a single transaction, the packages replacing
+// the stored ones and the new changes appended to the history.
+func (that *Class) Save() error {
+	if err := os.MkdirAll(filepath.Dir(that.path), 0755); err != nil {
+		return err
+	}
+	db, err := bolt.Open(that.path, 0644, &bolt.Options{Timeout: lockTimeout})
+	if err != nil {
+		return fmt.Errorf("unable to open the inventory %s: %w", that.path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(packagesBucket) != nil {
+			if err := tx.DeleteBucket(packagesBucket); err != nil {
+				return err
+			}
+		}
+		packages, err := tx.CreateBucket(packagesBucket)
+		if err != nil {
+			return err
+		}
+		for _, e := range that.Packages {
+			raw, err := json.Marshal(e)
+			if err == nil {
+				err = packages.Put([]byte(e.Path), raw)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		history, err := tx.CreateBucketIfNotExists(historyBucket)
+		if err != nil {
+			return err
+		}
+		for _, c := range that.History[that.stored:] {
+			seq, err := history.NextSequence()
+			if err != nil {
+				return err
+			}
+			key := make([]byte, 8)
+			binary.BigEndian.PutUint64(key, seq)
+			raw, err := json.Marshal(c)
+			if err == nil {
+				err = history.Put(key, raw)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if cerr := db.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write the inventory %s: %w", that.path, err)
+	}
+	that.stored = len(that.History)
+	return nil
+}
+
+func (that Change) String() string {
+	switch {
+	case that.From == "":
+		return fmt.Sprintf("%s %s %s added at %s", that.At.Format(time.RFC3339), that.Name, that.To, that.Path)
+	case that.To == "":
+		return fmt.Sprintf("%s %s %s removed from %s", that.At.Format(time.RFC3339), that.Name, that.From, that.Path)
+	}
+	return fmt.Sprintf("%s %s %s -> %s at %s", that.At.Format(time.RFC3339), that.Name, that.From, that.To, that.Path)
+}
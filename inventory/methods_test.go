@@ -0,0 +1,84 @@
+package inventory
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRefresh(t *testing.T) {
+	inv, err := Open(filepath.Join(t.TempDir(), "gopi", "inventory.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	day1, day2 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	inv.Refresh("/src/acme", []Entry{{Name: "api", Version: "1.0.0", Tenant: "acme", Path: "/src/acme/api"},
+		{Name: "web", Version: "0.1.0", Tenant: "acme", Path: "/src/acme/web"}}, day1)
+	inv.Refresh("/src/globex", []Entry{{Name: "tool", Version: "2.0.0", Tenant: "globex", Path: "/src/globex/tool"}}, day1)
+
+	changes := inv.Refresh("/src/acme", []Entry{{Name: "api", Version: "1.1.0", Tenant: "acme", Path: "/src/acme/api"}}, day2)
+	if len(changes) != 2 || changes[0].From != "1.0.0" || changes[0].To != "1.1.0" || changes[1].Name != "web" || changes[1].To != "" {
+		t.Fatal(changes)
+	}
+	if len(inv.Packages) != 2 || inv.Packages[0].Name != "api" || inv.Packages[1].Name != "tool" {
+		t.Fatal(inv.Packages)
+	}
+	if h := inv.Changes("api"); len(h) != 2 || h[1].String() != "2026-01-02T00:00:00Z api 1.0.0 -> 1.1.0 at /src/acme/api" {
+		t.Fatal(h)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	inv := &Class{Packages: []Entry{{Name: "api", Version: "1.2.0", Tenant: "acme"}, {Name: "api-gw", Version: "2.0.0", Tenant: "acme"},
+		{Name: "tool", Version: "1.5.0", Tenant: "globex"}, {Name: "old", Version: "1.0", Tenant: "acme"}}}
+	names := func(q Query) string {
+		res, err := inv.Query(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var n []string
+		for _, e := range res {
+			n = append(n, e.Name)
+		}
+		return strings.Join(n, ",")
+	}
+	if names(Query{Tenant: "ACME"}) != "api,api-gw,old" || names(Query{Name: "api*"}) != "api,api-gw" || names(Query{Constraint: "^1.0.0"}) != "api,tool" {
+		t.Fail()
+	}
+	if _, err := inv.Query(Query{Constraint: ">>1"}); err == nil {
+		t.Fail()
+	}
+	if _, err := inv.Query(Query{Name: "[api"}); err == nil {
+		t.Fail()
+	}
+}
+
+func TestSave(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), "gopi", "inventory.db")
+	inv, _ := Open(pth)
+	inv.Refresh("/src", []Entry{{Name: "api", Version: "1.0.0", Path: "/src/api"}}, time.Now())
+	if err := inv.Save(); err != nil {
+		t.Fatal(err)
+	}
+	again, err := Open(pth)
+	if err != nil || len(again.Packages) != 1 || len(again.History) != 1 {
+		t.Fatal(again, err)
+	}
+
+	// only the new changes are appended to the history
+	again.Refresh("/src", []Entry{{Name: "api", Version: "1.1.0", Path: "/src/api"}, {Name: "web", Version: "0.1.0", Path: "/src/web"}}, time.Now())
+	if err = again.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err = again.Save(); err != nil {
+		t.Fatal(err)
+	}
+	again, err = Open(pth)
+	if err != nil || len(again.Packages) != 2 || len(again.History) != 3 || again.History[1].To != "1.1.0" || again.History[2].Name != "web" {
+		t.Fatal(again, err)
+	}
+	if _, err = Open(""); err == nil {
+		t.Fail()
+	}
+}
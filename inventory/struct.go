@@ -0,0 +1,40 @@
+package inventory
+
+import "time"
+
+// Class is the inventory catalog of the scanned packages and of how they
+// changed between scans, stored in the bolt database at path.
+type Class struct {
+	path     string
+	stored   int
+	Packages []Entry  `json:"packages"`
+	History  []Change `json:"history"`
+}
+
+// Entry is a package of the catalog, Path being its absolute directory.
+type Entry struct {
+	Name    string    `json:"name"`
+	Version string    `json:"version"`
+	Tenant  string    `json:"tenant"`
+	Path    string    `json:"path"`
+	Scanned time.Time `json:"scanned"`
+}
+
+// Change is a package added (From empty), removed (To empty) or whose
+// version changed between two scans.
+type Change struct {
+	At     time.Time `json:"at"`
+	Name   string    `json:"name"`
+	Tenant string    `json:"tenant"`
+	Path   string    `json:"path"`
+	From   string    `json:"from,omitempty"`
+	To     string    `json:"to,omitempty"`
+}
+
+// Query selects catalog entries, the empty fields matching everything: the
+// tenant, a name glob like api-* and a version constraint like ^1.2.0.
+type Query struct {
+	Tenant     string
+	Name       string
+	Constraint string
+}
@@ -0,0 +1,104 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"gov/pkginfo"
+	"sort"
+	"strings"
+)
+
+// answerFields are the package info fields init takes answers for, in the
+// order they are asked.
+var answerFields = []string{"name", "version", "channel", "description", "keywords", "tenant", "repo", "type", "license", "maintainers", "arch"}
+
+// ParseAnswers reads an answers file, a yaml (or json) map of package info
+// fields to their values. Lists are written as yaml lists or comma separated.
+func ParseAnswers(raw []byte) (map[string]string, error) {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("unable to parse the answers: %w", err)
+	}
+	res := map[string]string{}
+	for field, v := range doc {
+		if !contains(answerFields, field) {
+			return nil, fmt.Errorf("unknown answer %q, expected one of %s", field, strings.Join(answerFields, ", "))
+		}
+		switch t := v.(type) {
+		case nil:
+		case []interface{}:
+			items := make([]string, len(t))
+			for i, item := range t {
+				items[i] = fmt.Sprint(item)
+			}
+			res[field] = strings.Join(items, ", ")
+		case map[string]interface{}:
+			return nil, fmt.Errorf("answer %q must be a value or a list", field)
+		default:
+			res[field] = fmt.Sprint(t)
+		}
+	}
+	return res, nil
+}
+
+// InitPkg writes the package info of root without prompting: an existing
+// package info or the guessed defaults, updated with the GOPI_PKG_<FIELD>
+// variables and then with answers. It fails listing every missing or invalid
+// answer, and when the file exists unless force (or assumeYes) is set.
+func (that *Class) InitPkg(ctx context.Context, root string, answers map[string]string, force bool) error {
+	if that.checkPkgExists(root) && !force && !that.config.AssumeYes {
+		return fmt.Errorf("%s already exists, use -force to overwrite it", that.pkgPath(root))
+	}
+	if err := that.loadExisting(root); err != nil {
+		return err
+	}
+	modPath := that.goModule(root)
+	if that.Name == "" {
+		that.Name = moduleName(modPath)
+	}
+	if that.Repo == "" {
+		if that.Repo = normalizeRepo(that.gitRemote(ctx, root)); that.Repo == "" {
+			that.Repo = moduleRepo(modPath)
+		}
+	}
+	if that.Type == "" {
+		that.Type = that.guessType(root)
+	}
+
+	var problems []string
+	for _, field := range answerFields {
+		v, ok := that.lookupEnv(envPrefix + strings.ToUpper(field))
+		if a, given := answers[field]; given {
+			v, ok = a, true
+		}
+		if !ok {
+			continue
+		}
+		if err := pkginfo.SetField(&that.Info, field, v, that.archList()...); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if strings.TrimSpace(that.Tenant) != "" {
+		that.loadTenants(ctx)
+		if err := that.validTenant(that.Tenant); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	var missing []string
+	for _, d := range pkginfo.Validate(&that.Info) {
+		if d.Code == "required" {
+			missing = append(missing, d.Field)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		problems = append(problems, fmt.Sprintf("missing %s, answer them in the answers file or with %s<FIELD>", strings.Join(missing, ", "), envPrefix))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("unable to initialize %s: %s", that.pkgPath(root), strings.Join(problems, "; "))
+	}
+	that.Repo = normalizeRepo(that.Repo)
+	that.defaultArch()
+	return that.CreatePkg(root)
+}
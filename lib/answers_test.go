@@ -0,0 +1,54 @@
+package lib
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestParseAnswers(t *testing.T) {
+	a, err := ParseAnswers([]byte("name: demo\nversion: 1.2.0\nkeywords: [cli, tooling]\narch: linux_amd64\nlicense:\n"))
+	if err != nil || a["name"] != "demo" || a["keywords"] != "cli, tooling" || a["arch"] != "linux_amd64" {
+		t.Fatal(a, err)
+	}
+	if _, ok := a["license"]; ok {
+		t.Fail()
+	}
+	if _, err = ParseAnswers([]byte("nmae: demo\n")); err == nil {
+		t.Fail()
+	}
+	if a, err = ParseAnswers(nil); err != nil || len(a) != 0 {
+		t.Fatal(a, err)
+	}
+}
+
+func TestInitPkg(t *testing.T) {
+	fsys := memFS{path.Join(tRoot, "go.mod"): []byte("module github.com/acme/demo\n")}
+	gopi, p := newTestClass(fsys)
+	env := map[string]string{"GOPI_PKG_TENANT": "acme", "GOPI_PKG_VERSION": "0.1.0"}
+	gopi.getenv = func(key string) string { return env[key] }
+	answers := map[string]string{"version": "1.2.0", "arch": "linux_amd64", "maintainers": "Jane <jane@acme.io>"}
+	if err := gopi.InitPkg(context.Background(), tRoot, answers, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.asked) != 0 || gopi.Name != "demo" || gopi.Version != "1.2.0" || gopi.Tenant != "acme" || gopi.Repo != "https://github.com/acme/demo" {
+		t.Fatal(p.asked, gopi.Info)
+	}
+	if !strings.Contains(string(fsys[path.Join(tRoot, "pkg.info")]), "tenant: acme") {
+		t.Fail()
+	}
+
+	// the file exists now
+	if err := gopi.InitPkg(context.Background(), tRoot, answers, false); err == nil || !strings.Contains(err.Error(), "-force") {
+		t.Fatal(err)
+	}
+}
+
+func TestInitPkg_missing(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	err := gopi.InitPkg(context.Background(), tRoot, map[string]string{"arch": "wioll"}, false)
+	if err == nil || !strings.Contains(err.Error(), "wioll") || !strings.Contains(err.Error(), "missing name, tenant, version") {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,169 @@
+package lib
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"gov/platform"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+)
+
+// archiveTime is the modification time of the archived files, fixed so that
+// the same binaries give the same archives (zip can not go before 1980).
+var archiveTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Archive is one archive written by gopi archive, as recorded in the release
+// manifest.
+type Archive struct {
+	Target string `json:"target"`
+	File   string `json:"file"`
+	SHA256 string `json:"sha256"`
+	Size   int    `json:"size"`
+}
+
+// ReleaseManifest lists the archives of a release.
+type ReleaseManifest struct {
+	Name     string    `json:"name"`
+	Version  string    `json:"version"`
+	Tenant   string    `json:"tenant,omitempty"`
+	Archives []Archive `json:"archives"`
+}
+
+// archiveEntry is a file stored in an archive.
+type archiveEntry struct {
+	name string
+	data []byte
+	mode int64
+}
+
+// archiveEntries are the binary of target, named after the package binary,
+// with the LICENSE and README of root when they exist.
+func (that *Class) archiveEntries(root string, target string, binary string) ([]archiveEntry, error) {
+	raw, err := that.fs.ReadFile(binary)
+	if err != nil {
+		return nil, fmt.Errorf("the %s binary %s is missing, run gopi build first", target, binary)
+	}
+	res := []archiveEntry{{name: that.binaryName() + that.artifactData(target)["Ext"], data: raw, mode: 0755}}
+	for _, name := range []string{"LICENSE", that.config.ReadmeFile} {
+		if raw, err = that.fs.ReadFile(path.Join(root, name)); err == nil {
+			res = append(res, archiveEntry{name: path.Base(name), data: raw, mode: 0644})
+		}
+	}
+	return res, nil
+}
+
+func tarGz(entries []archiveEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: e.mode, Size: int64(len(e.data)), ModTime: archiveTime, Format: tar.FormatPAX}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(e.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func zipped(entries []archiveEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := &zip.FileHeader{Name: e.name, Method: zip.Deflate, Modified: archiveTime}
+		hdr.SetMode(fs.FileMode(e.mode))
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = w.Write(e.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// archiveName is the artifact name of target without the executable
+// extension, with the archive one.
+func (that *Class) archiveName(target string) (string, error) {
+	name, err := that.artifactName(target)
+	if err != nil {
+		return "", err
+	}
+	name = strings.TrimSuffix(name, that.artifactData(target)["Ext"])
+	if goos, _ := platform.Split(target); goos == "windows" {
+		return name + ".zip", nil
+	}
+	return name + ".tar.gz", nil
+}
+
+// Archives packs the built binary of each target of the package in root, with
+// its LICENSE and README, into a tar.gz (a zip for windows) in the build
+// directory and records them in the release manifest. It returns the archives
+// and the path of the manifest.
+func (that *Class) Archives(root string) ([]Archive, string, error) {
+	dir := that.BuildDir(root)
+	var res []Archive
+	for _, target := range that.buildTargets() {
+		binary, err := that.artifactName(target)
+		if err != nil {
+			return nil, "", err
+		}
+		entries, err := that.archiveEntries(root, target, path.Join(dir, binary))
+		if err != nil {
+			return nil, "", err
+		}
+		name, err := that.archiveName(target)
+		if err != nil {
+			return nil, "", err
+		}
+		var raw []byte
+		if strings.HasSuffix(name, ".zip") {
+			raw, err = zipped(entries)
+		} else {
+			raw, err = tarGz(entries)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to archive %s: %w", name, err)
+		}
+		pth := path.Join(dir, name)
+		if err = that.fs.WriteFile(pth, raw, that.fileMode()); err != nil {
+			return nil, "", fmt.Errorf("unable to write %s: %w", pth, err)
+		}
+		sum := sha256.Sum256(raw)
+		res = append(res, Archive{Target: target, File: name, SHA256: hex.EncodeToString(sum[:]), Size: len(raw)})
+	}
+
+	file := that.config.ReleaseManifest
+	if file == "" {
+		return res, "", nil
+	}
+	raw, err := json.MarshalIndent(ReleaseManifest{Name: that.Name, Version: that.Version, Tenant: that.Tenant, Archives: res}, "", "  ")
+	if err != nil {
+		return nil, "", err
+	}
+	pth := path.Join(dir, file)
+	if err = that.fs.WriteFile(pth, append(raw, '\n'), that.fileMode()); err != nil {
+		return nil, "", fmt.Errorf("unable to write %s: %w", pth, err)
+	}
+	return res, pth, nil
+}
@@ -0,0 +1,73 @@
+package lib
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestArchives(t *testing.T) {
+	fsys := memFS{
+		"/project/dist/demo_linux_arm64":       []byte("elf"),
+		"/project/dist/demo_windows_amd64.exe": []byte("pe"),
+		path.Join(tRoot, "LICENSE"):            []byte("MIT"),
+		path.Join(tRoot, "README.md"):          []byte("# demo"),
+	}
+	gopi, _ := newTestClass(fsys)
+	gopi.config.BuildDir = "dist"
+	gopi.config.ReleaseManifest = "release.json"
+	gopi.Name, gopi.Version = "demo", "1.0.0"
+	gopi.Arch = []string{"linux/arm64", "windows/amd64"}
+	archives, manifest, err := gopi.Archives(tRoot)
+	if err != nil || len(archives) != 2 || manifest != "/project/dist/release.json" {
+		t.Fatal(archives, manifest, err)
+	}
+	if archives[0].File != "demo_linux_arm64.tar.gz" || archives[1].File != "demo_windows_amd64.zip" {
+		t.Fatal(archives)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(fsys["/project/dist/demo_linux_arm64.tar.gz"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, hdr.Name)
+	}
+	if strings.Join(names, " ") != "demo LICENSE README.md" {
+		t.Fatal(names)
+	}
+
+	raw := fsys["/project/dist/demo_windows_amd64.zip"]
+	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil || len(zr.File) != 3 || zr.File[0].Name != "demo.exe" {
+		t.Fatal(err)
+	}
+
+	var got ReleaseManifest
+	if err = json.Unmarshal(fsys[manifest], &got); err != nil || len(got.Archives) != 2 || got.Archives[1].SHA256 != archives[1].SHA256 {
+		t.Fatal(string(fsys[manifest]), err)
+	}
+}
+
+func TestArchives_not_built(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.Name = "demo"
+	if _, _, err := gopi.Archives(tRoot); err == nil || !strings.Contains(err.Error(), "run gopi build first") {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,95 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"gov/txn"
+	"io/fs"
+	"os"
+)
+
+// AuditFinding is a generated file failing the security baseline.
+type AuditFinding struct {
+	Path    string
+	Problem string
+	Fixed   bool
+	Err     error
+}
+
+// Audit checks the generated files in root for modes more permissive than
+// the configured fileMode, an owner differing from the one of the directory
+// (files left by root in containerized runs) and symlinks. With fix set the
+// problems are corrected: modes are reset, ownership handed back to the
+// directory owner and symlinks replaced by a regular file with their content.
+func (that *Class) Audit(root string, fix bool) ([]AuditFinding, error) {
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	dir, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	dirUID, dirGID, owned := fileOwner(dir)
+	want := that.fileMode()
+
+	var res []AuditFinding
+	add := func(pth string, problem string, fixFn func() error) {
+		f := AuditFinding{Path: pth, Problem: problem}
+		if fix {
+			f.Err = fixFn()
+			f.Fixed = f.Err == nil
+		}
+		res = append(res, f)
+	}
+
+	for _, pth := range that.generatedFiles(root) {
+		fi, err := os.Lstat(pth)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return res, err
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			target, _ := os.Readlink(pth)
+			add(pth, fmt.Sprintf("is a symlink to %s", target), func() error {
+				return unlink(pth, want)
+			})
+			// without the fix, check the target the symlink points to
+			if fi, err = os.Stat(pth); err != nil {
+				return res, err
+			}
+		}
+		if extra := fi.Mode().Perm() &^ want; extra != 0 {
+			add(pth, fmt.Sprintf("mode %04o is more permissive than %04o", fi.Mode().Perm(), want), func() error {
+				return os.Chmod(pth, want)
+			})
+		}
+		if uid, gid, ok := fileOwner(fi); ok && owned && (uid != dirUID || gid != dirGID) {
+			add(pth, fmt.Sprintf("owned by %d:%d, the directory by %d:%d", uid, gid, dirUID, dirGID), func() error {
+				return os.Lchown(pth, dirUID, dirGID)
+			})
+		}
+	}
+	return res, nil
+}
+
+// unlink replaces the symlink pth with a regular file holding its target
+// content, at once: pth is never missing or truncated.
+func unlink(pth string, perm os.FileMode) error {
+	raw, err := os.ReadFile(pth)
+	if err != nil {
+		return err
+	}
+	return txn.ReplaceFile(pth, raw, perm)
+}
+
+func (that AuditFinding) String() string {
+	switch {
+	case that.Fixed:
+		return fmt.Sprintf("%s: %s (fixed)", that.Path, that.Problem)
+	case that.Err != nil:
+		return fmt.Sprintf("%s: %s (fix failed: %s)", that.Path, that.Problem, that.Err.Error())
+	}
+	return fmt.Sprintf("%s: %s", that.Path, that.Problem)
+}
@@ -0,0 +1,44 @@
+package lib
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAudit_fix(t *testing.T) {
+	root := t.TempDir()
+	readme, makefile, target := filepath.Join(root, "README.md"), filepath.Join(root, "Makefile"), filepath.Join(root, "shared.mk")
+	_ = os.WriteFile(readme, []byte("# demo\n"), 0644)
+	_ = os.Chmod(readme, 0777)
+	_ = os.WriteFile(target, []byte("build:\n"), 0644)
+	if err := os.Symlink(target, makefile); err != nil {
+		t.Skip(err)
+	}
+
+	gopi, _ := newTestClass(memFS{})
+	findings, err := gopi.Audit(root, false)
+	if err != nil || len(findings) != 2 {
+		t.Fatal(findings, err)
+	}
+	if findings, err = gopi.Audit(root, true); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range findings {
+		if !f.Fixed {
+			t.Fatal(f.String())
+		}
+	}
+	if fi, _ := os.Lstat(readme); fi.Mode().Perm() != 0644 {
+		t.Fail()
+	}
+	if fi, _ := os.Lstat(makefile); fi.Mode()&os.ModeSymlink != 0 {
+		t.Fail()
+	}
+	if raw, _ := os.ReadFile(makefile); string(raw) != "build:\n" {
+		t.Fatal(string(raw))
+	}
+	if findings, _ = gopi.Audit(root, false); len(findings) != 0 {
+		t.Fatal(findings)
+	}
+}
@@ -0,0 +1,83 @@
+package lib
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// backupPath is where the previous content of pth is kept: <name>.bak next to
+// it, or in the configured backup directory. A relative one is next to pth
+// too, an absolute one (or ~/) keeps the whole path of pth below it so that
+// the files of every package have a backup of their own.
+func (that *Class) backupPath(pth string) string {
+	name := path.Base(pth) + ".bak"
+	dir := that.config.Backup.Dir
+	if dir == "" {
+		return path.Join(path.Dir(pth), name)
+	}
+	if dir = resolveDir(path.Dir(pth), dir); path.IsAbs(that.config.Backup.Dir) || strings.HasPrefix(that.config.Backup.Dir, "~/") {
+		if abs, err := filepath.Abs(path.Dir(pth)); err == nil {
+			return path.Join(dir, filepath.ToSlash(abs), name)
+		}
+	}
+	return path.Join(dir, name)
+}
+
+// mkdirAll creates dir, and its parents, on the file systems able to.
+func (that *Class) mkdirAll(dir string) error {
+	fsys := that.fs
+	if tx, ok := fsys.(txFS); ok {
+		fsys = tx.FS
+	}
+	if m, ok := fsys.(dirMaker); ok {
+		return m.MkdirAll(dir, 0755)
+	}
+	return nil
+}
+
+// generatedFiles lists every file gopi may generate in root.
+func (that *Class) generatedFiles(root string) []string {
+	files := []string{that.pkgPath(root), path.Join(root, that.config.ReadmeFile)}
+	for _, kind := range []string{"justfile", "makefile", "version"} {
+		files = append(files, that.generatedPath(root, kind))
+	}
+	return files
+}
+
+// Restore puts back the backed up content of the given files, by default of
+// every file gopi generates in root. It returns the restored paths.
+func (that *Class) Restore(root string, files ...string) ([]string, error) {
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	if len(files) == 0 {
+		files = that.generatedFiles(root)
+	}
+
+	var restored []string
+	for _, f := range files {
+		pth := f
+		if !path.IsAbs(pth) {
+			pth = path.Join(root, pth)
+		}
+		raw, err := that.fs.ReadFile(that.backupPath(pth))
+		if err != nil {
+			continue
+		}
+		perm := that.fileMode()
+		if fi, err := that.fs.Stat(pth); err == nil {
+			perm = fi.Mode().Perm()
+		}
+		if err = that.fs.WriteFile(pth, raw, perm); err != nil {
+			return restored, fmt.Errorf("unable to restore %s: %w", pth, err)
+		}
+		restored = append(restored, pth)
+	}
+	if len(restored) == 0 {
+		return nil, fmt.Errorf("no backups found in %s", root)
+	}
+	return restored, nil
+}
@@ -0,0 +1,64 @@
+package lib
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Badge is a badge of the README badge row.
+type Badge struct {
+	Label string
+	Image string
+	Link  string
+}
+
+// badgeData is what the configured badge templates are rendered with.
+func (that *Class) badgeData(root string) map[string]string {
+	repo := normalizeRepo(that.Repo)
+	host, slug, _ := strings.Cut(strings.TrimPrefix(repo, "https://"), "/")
+	return map[string]string{
+		"Name":      that.Name,
+		"Version":   that.Version,
+		"Tenant":    that.Tenant,
+		"License":   that.License,
+		"GoVersion": that.goVersion(root),
+		"Repo":      repo,
+		"Host":      host,
+		"Slug":      slug,
+	}
+}
+
+// badges renders the configured badges of the package in root, leaving out
+// the ones whose image is empty.
+func (that *Class) badges(root string) ([]Badge, error) {
+	data := that.badgeData(root)
+	render := func(label string, kind string, text string) (string, error) {
+		tpl, err := template.New(label).Parse(text)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s template of the %s badge: %w", kind, label, err)
+		}
+		var sb strings.Builder
+		if err = tpl.Execute(&sb, data); err != nil {
+			return "", fmt.Errorf("while processing the %s template of the %s badge: %w", kind, label, err)
+		}
+		return strings.TrimSpace(sb.String()), nil
+	}
+
+	var res []Badge
+	for _, b := range that.config.Badges {
+		image, err := render(b.Label, "image", b.Image)
+		if err != nil {
+			return nil, err
+		}
+		if image == "" {
+			continue
+		}
+		link, err := render(b.Label, "link", b.Link)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, Badge{Label: b.Label, Image: image, Link: link})
+	}
+	return res, nil
+}
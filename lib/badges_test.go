@@ -0,0 +1,49 @@
+package lib
+
+import (
+	"gov/config"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestBadges_default(t *testing.T) {
+	raw, err := os.ReadFile("../config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.New(raw, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gopi, _ := newTestClass(memFS{path.Join(tRoot, "go.mod"): []byte("module github.com/acme/tool\n\ngo 1.21\n")})
+	gopi.config.Badges = cfg.Badges
+	gopi.Version, gopi.Repo = "1.0.0+build.1", "git@github.com:acme/tool.git"
+
+	b, err := gopi.badges(tRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != 4 || b[0].Label != "version" || b[1].Label != "go" || b[2].Label != "build" {
+		t.Fatal(b)
+	}
+	if b[0].Image != "https://img.shields.io/static/v1?label=Version&message=1.0.0%2Bbuild.1&color=blue" ||
+		b[1].Image != "https://img.shields.io/static/v1?label=Go&message=1.21&color=00ADD8" ||
+		b[2].Image != "https://github.com/acme/tool/actions/workflows/ci.yml/badge.svg" ||
+		b[2].Link != "https://github.com/acme/tool/actions" || b[3].Link != "https://codecov.io/gh/acme/tool" {
+		t.Fatal(b)
+	}
+
+	gopi.Repo = "https://gitlab.com/acme/tool"
+	if b, err = gopi.badges(tRoot); err != nil || len(b) != 2 {
+		t.Fatal(b, err)
+	}
+}
+
+func TestBadges_invalid(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.config.Badges = []config.Badge{{Label: "broken", Image: "{{ .Nope"}}
+	if _, err := gopi.badges(tRoot); err == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,204 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"gov/platform"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+const defaultArtifactName = "{{ .Binary }}_{{ .OS }}_{{ .Arch }}{{ .Ext }}"
+
+// BuildResult is the outcome of compiling the package for one target.
+type BuildResult struct {
+	Target string
+	Binary string
+	Log    string
+	Err    error
+}
+
+// buildTargets are the architectures and matrix targets of the package, the
+// local platform when none is declared.
+func (that *Class) buildTargets() []string {
+	targets := that.Platforms()
+	if len(targets) == 0 {
+		return []string{that.localArch()}
+	}
+	return targets
+}
+
+// BuildDir is where gopi build writes the binaries of the package in root.
+func (that *Class) BuildDir(root string) string {
+	return resolveDir(root, that.config.BuildDir)
+}
+
+// artifactData is what the artifactName and downloadURL templates are
+// rendered with.
+func (that *Class) artifactData(target string) map[string]string {
+	goos, goarch := platform.Split(target)
+	ext := ""
+	if goos == "windows" {
+		ext = ".exe"
+	}
+	return map[string]string{
+		"Name":    that.Name,
+		"Version": that.Version,
+		"Tenant":  that.Tenant,
+		"Repo":    strings.TrimSuffix(that.Repo, "/"),
+		"Binary":  that.binaryName(),
+		"OS":      goos,
+		"Arch":    goarch,
+		"Ext":     ext,
+	}
+}
+
+// artifactName renders the configured artifactName template for target.
+func (that *Class) artifactName(target string) (string, error) {
+	format := that.config.ArtifactName
+	if format == "" {
+		format = defaultArtifactName
+	}
+	tpl, err := template.New("artifactName").Option("missingkey=error").Parse(format)
+	if err != nil {
+		return "", fmt.Errorf("invalid artifactName template: %w", err)
+	}
+	var sb strings.Builder
+	if err = tpl.Execute(&sb, that.artifactData(target)); err != nil {
+		return "", fmt.Errorf("while processing the artifactName template: %w", err)
+	}
+	name := sb.String()
+	if name == "" || strings.ContainsAny(name, "/\\") {
+		return "", fmt.Errorf("invalid artifact name %q for %s", name, target)
+	}
+	return name, nil
+}
+
+// commit is the revision being built: the CI commit, else the git HEAD of
+// root, else "".
+func (that *Class) commit(ctx context.Context, root string) string {
+	if c := that.CI(); c != nil && c.Commit != "" {
+		return c.Commit
+	}
+	out, err := that.runner.Run(ctx, root, "git", "rev-parse", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// ldflags sets the name, version, tenant and commit variables of the
+// configured versionPackage in the binary, e.g. -X main.version=1.2.0.
+func (that *Class) ldflags(ctx context.Context, root string) []string {
+	pkg := that.config.VersionPackage
+	if pkg == "" {
+		return nil
+	}
+	var x []string
+	for _, v := range [][2]string{{"name", that.Name}, {"version", that.Version}, {"tenant", that.Tenant},
+		{"commit", that.commit(ctx, root)}} {
+		def := pkg + "." + v[0] + "=" + v[1]
+		if strings.ContainsAny(def, " \t") {
+			def = "'" + def + "'"
+		}
+		x = append(x, "-X", def)
+	}
+	return []string{"-ldflags", strings.Join(x, " ")}
+}
+
+// targetBuild is the environment and go build flags of target: its
+// platform, then the settings of its arch entry, its ldflags added to the
+// version ones.
+func (that *Class) targetBuild(target string, flags []string) ([]string, []string) {
+	goos, goarch := platform.Split(target)
+	t := that.Target(target)
+	env := []string{"GOOS=" + goos, "GOARCH=" + goarch}
+	if t.CGO != nil {
+		cgo := "0"
+		if *t.CGO {
+			cgo = "1"
+		}
+		env = append(env, "CGO_ENABLED="+cgo)
+	}
+	names := make([]string, 0, len(t.Env))
+	for name := range t.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		env = append(env, name+"="+t.Env[name])
+	}
+
+	var args []string
+	if len(t.Tags) > 0 {
+		args = append(args, "-tags", strings.Join(t.Tags, ","))
+	}
+	switch {
+	case t.Ldflags == "":
+		args = append(args, flags...)
+	case len(flags) == 2:
+		args = append(args, flags[0], flags[1]+" "+t.Ldflags)
+	default:
+		args = append(args, "-ldflags", t.Ldflags)
+	}
+	return env, args
+}
+
+func (that *Class) buildTarget(ctx context.Context, root string, target string, flags []string) BuildResult {
+	goos, goarch := platform.Split(target)
+	res := BuildResult{Target: target}
+	name, err := that.artifactName(target)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	res.Binary = path.Join(that.BuildDir(root), name)
+	env, flags := that.targetBuild(target, flags)
+	args := append(append([]string{"build"}, flags...), "-o", res.Binary, ".")
+	out, err := that.runner.RunEnv(ctx, root, env, "go", args...)
+	res.Log = strings.TrimSpace(string(out))
+	if err != nil {
+		res.Err = fmt.Errorf("go build for %s/%s failed: %w", goos, goarch, err)
+	}
+	return res
+}
+
+// Build cross compiles the package in root for each of its architectures,
+// at most parallel targets at a time, and reports the outcome of every target
+// in the order of the arch list.
+func (that *Class) Build(ctx context.Context, root string, parallel int) []BuildResult {
+	targets := that.buildTargets()
+	if parallel < 1 {
+		parallel = 1
+	}
+	if parallel > len(targets) {
+		parallel = len(targets)
+	}
+
+	flags := that.ldflags(ctx, root)
+	res := make([]BuildResult, len(targets))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < parallel; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				if err := ctx.Err(); err != nil {
+					res[i] = BuildResult{Target: targets[i], Err: err}
+					continue
+				}
+				res[i] = that.buildTarget(ctx, root, targets[i], flags)
+			}
+		}()
+	}
+	for i := range targets {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return res
+}
@@ -0,0 +1,55 @@
+package lib
+
+import (
+	"fmt"
+	"gov/ci"
+	"gov/version"
+)
+
+func (that *Class) CI() *ci.Class {
+	return ci.Detect(that.getenv)
+}
+
+// BuildVersion is the package version stamped with the CI commit and run
+// metadata when running in CI, and the plain version otherwise.
+func (that *Class) BuildVersion() (string, error) {
+	v, err := version.New(that.Version)
+	if err != nil {
+		return "", err
+	}
+	if c := that.CI(); c != nil {
+		if err = c.Stamp(v); err != nil {
+			return "", err
+		}
+	}
+	return v.String(), nil
+}
+
+// Bump increments the package version: part is one of major, minor, patch or
+// pre (the trailing prerelease number). It returns the new version.
+func (that *Class) Bump(part string, opts ...version.IncOption) (string, error) {
+	v, err := version.New(that.Version)
+	if err != nil {
+		return "", fmt.Errorf("the %s version %q can't be bumped: %s", that.config.PkgInfoFile, that.Version, err.Error())
+	}
+	switch part {
+	case "major":
+		v.IncMajor(opts...)
+	case "minor":
+		v.IncMinor(opts...)
+	case "patch":
+		v.IncPatch(opts...)
+	case "pre":
+		err = v.IncPrerelease(opts...)
+	default:
+		err = fmt.Errorf("unknown version part %q, expected major, minor, patch or pre", part)
+	}
+	if err != nil {
+		return "", err
+	}
+	that.Version = v.String()
+	if that.Channel != "" {
+		that.Channel = that.channelOf(v)
+	}
+	return that.Version, nil
+}
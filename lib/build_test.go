@@ -0,0 +1,98 @@
+package lib
+
+import (
+	"context"
+	"gov/pkginfo"
+	"strings"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	r := fakeRunner{
+		"GOOS=linux GOARCH=arm64 go build -o /project/dist/demo_linux_arm64 .":         "",
+		"GOOS=windows GOARCH=amd64 go build -o /project/dist/demo_windows_amd64.exe .": "",
+	}
+	gopi, _ := newTestClassRunner(memFS{}, r)
+	gopi.config.BuildDir = "dist"
+	gopi.Name = "demo"
+	gopi.Arch = []string{"linux/arm64", "windows", "darwin/arm64"}
+	res := gopi.Build(context.Background(), tRoot, 2)
+	if len(res) != 3 || res[0].Err != nil || res[1].Err != nil || res[2].Err == nil {
+		t.Fatal(res)
+	}
+	if res[1].Binary != "/project/dist/demo_windows_amd64.exe" {
+		t.Fail()
+	}
+}
+
+func TestBuild_target_settings(t *testing.T) {
+	cgo := false
+	r := fakeRunner{
+		"git rev-parse HEAD": "abc123\n",
+		"GOOS=linux GOARCH=arm64 CGO_ENABLED=0 CC=zig GOARM64=v8.2 go build -tags netgo,osusergo " +
+			"-ldflags -X main.name=demo -X main.version=1.0.0 -X main.tenant= -X main.commit=abc123 -s -w -o /project/dist/demo_linux_arm64 .": "",
+	}
+	gopi, _ := newTestClassRunner(memFS{}, r)
+	gopi.getenv = func(string) string { return "" }
+	gopi.config.BuildDir = "dist"
+	gopi.config.VersionPackage = "main"
+	gopi.Name, gopi.Version = "demo", "1.0.0"
+	gopi.Arch = []string{"linux/arm64"}
+	gopi.Targets = map[string]pkginfo.Target{"linux/arm64": {Platform: "linux/arm64", Tags: []string{"netgo", "osusergo"},
+		CGO: &cgo, Env: map[string]string{"GOARM64": "v8.2", "CC": "zig"}, Ldflags: "-s -w"}}
+	res := gopi.Build(context.Background(), tRoot, 1)
+	if len(res) != 1 || res[0].Err != nil {
+		t.Fatal(res)
+	}
+}
+
+func TestBuild_local(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.Name = "demo"
+	if targets := gopi.buildTargets(); len(targets) != 1 || targets[0] != gopi.localArch() {
+		t.Fatal(targets)
+	}
+}
+
+func TestBuildTargets_matrix(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.Arch = []string{"linux/amd64"}
+	gopi.Matrix = &pkginfo.Matrix{OS: []string{"linux", "darwin"}, Arch: []string{"amd64", "arm64"}, Exclude: []string{"darwin/amd64"}}
+	if targets := gopi.buildTargets(); strings.Join(targets, " ") != "linux/amd64 linux/arm64 darwin/arm64" {
+		t.Fatal(targets)
+	}
+}
+
+func TestArtifactName(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.Name = "demo"
+	gopi.Version = "1.2.0"
+	gopi.config.ArtifactName = "{{ .Name }}_{{ .Version }}_{{ .OS }}_{{ .Arch }}{{ .Ext }}"
+	if name, err := gopi.artifactName("windows/arm64"); err != nil || name != "demo_1.2.0_windows_arm64.exe" {
+		t.Fatal(name, err)
+	}
+	for _, format := range []string{"{{ .Nope }}", "{{ .OS }}/{{ .Arch }}", "{{ .Name"} {
+		gopi.config.ArtifactName = format
+		if _, err := gopi.artifactName("linux/amd64"); err == nil {
+			t.Fatal(format)
+		}
+	}
+}
+
+func TestLdflags(t *testing.T) {
+	r := fakeRunner{"git rev-parse HEAD": "abc123\n"}
+	gopi, _ := newTestClassRunner(memFS{}, r)
+	gopi.getenv = func(string) string { return "" }
+	gopi.Name, gopi.Version, gopi.Tenant = "demo", "1.2.0", "acme corp"
+	gopi.config.VersionPackage = "example.com/demo/build"
+	got := strings.Join(gopi.ldflags(context.Background(), tRoot), " ")
+	want := "-ldflags -X example.com/demo/build.name=demo -X example.com/demo/build.version=1.2.0 " +
+		"-X 'example.com/demo/build.tenant=acme corp' -X example.com/demo/build.commit=abc123"
+	if got != want {
+		t.Fatal(got)
+	}
+	gopi.config.VersionPackage = ""
+	if gopi.ldflags(context.Background(), tRoot) != nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,64 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// changes are the commit subjects since the latest version tag, oldest first.
+func (that *Class) changes(ctx context.Context, root string) ([]string, error) {
+	args := []string{"log", "--reverse", "--pretty=format:%s"}
+	if _, tag := that.latestTag(ctx, root); tag != "" {
+		args = append(args, tag+"..HEAD")
+	}
+	out, err := that.runner.Run(ctx, root, "git", args...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the git log: %w", err)
+	}
+	var res []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			res = append(res, line)
+		}
+	}
+	return res, nil
+}
+
+// changelogEntry renders the section of the current version.
+func (that *Class) changelogEntry(changes []string, date time.Time) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## %s - %s\n\n", that.TagName(), date.Format("2006-01-02"))
+	for _, c := range changes {
+		fmt.Fprintf(&sb, "- %s\n", c)
+	}
+	if len(changes) == 0 {
+		sb.WriteString("- No changes recorded.\n")
+	}
+	return sb.String()
+}
+
+// UpdateChangelog adds the section of the current version, listing the
+// commits since the latest tag, at the top of the configured changelog file.
+func (that *Class) UpdateChangelog(ctx context.Context, root string) error {
+	changes, err := that.changes(ctx, root)
+	if err != nil {
+		return err
+	}
+	pth := path.Join(root, that.config.ChangelogFile)
+	entry := that.changelogEntry(changes, time.Now())
+
+	content := "# Changelog\n\n" + entry
+	if old, err := that.fs.ReadFile(pth); err == nil {
+		st := string(old)
+		if strings.HasPrefix(st, "# ") {
+			header, rest, _ := strings.Cut(st, "\n")
+			content = header + "\n\n" + entry + "\n" + strings.TrimLeft(rest, "\n")
+		} else {
+			content = entry + "\n" + st
+		}
+	}
+	return that.writeFile(pth, []byte(content), that.fileMode())
+}
@@ -0,0 +1,79 @@
+package lib
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"gov/version"
+	"path"
+	"strings"
+)
+
+// latestTag is the highest version among the git tags carrying the tag
+// prefix, nil when there is none.
+func (that *Class) latestTag(ctx context.Context, root string) (*version.Class, string) {
+	prefix := that.tagPrefix()
+	out, err := that.runner.Run(ctx, root, "git", "tag", "--list", prefix+"*")
+	if err != nil {
+		return nil, ""
+	}
+	var latest *version.Class
+	var latestTag string
+	for _, tag := range strings.Fields(string(out)) {
+		v, err := version.New(strings.TrimPrefix(tag, prefix))
+		if err == nil && (latest == nil || v.GreaterThan(latest)) {
+			latest, latestTag = v, tag
+		}
+	}
+	return latest, latestTag
+}
+
+// Check reports where the package drifted from its surroundings: a version
+// that is not the latest git tag or the release right after it, a repo that
+// does not match the go.mod module path and a README not generated from the
+// current package info.
+func (that *Class) Check(ctx context.Context, root string) ([]Diagnostic, error) {
+	var res []Diagnostic
+	add := func(field string, code string, format string, args ...interface{}) {
+		res = append(res, Diagnostic{Field: field, Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	v, err := version.New(that.Version)
+	if err != nil {
+		add("version", "semver", "%q is not a valid semver version", that.Version)
+	} else if latest, tag := that.latestTag(ctx, root); latest != nil && !v.Follows(latest) {
+		add("version", "tag", "%s does not follow the latest tag %s", that.Version, tag)
+	}
+
+	if modPath := that.goModule(root); modPath != "" && that.Repo != "" {
+		if want := moduleRepo(modPath); want != "" && !strings.EqualFold(want, normalizeRepo(that.Repo)) {
+			add("repo", "gomod", "%s does not match the go.mod module %s (%s)", that.Repo, modPath, want)
+		}
+	}
+
+	if _, err := that.fs.ReadFile(path.Join(root, that.config.ReadmeFile)); err == nil {
+		stale, err := that.ReadmeStale(ctx, root)
+		if err != nil {
+			return nil, err
+		}
+		if stale {
+			add("readme", "stale", "%s was not generated from the current %s, run gopi -readme", that.config.ReadmeFile, that.config.PkgInfoFile)
+		}
+	}
+	return res, nil
+}
+
+// ReadmeStale reports whether the README of the package in root is missing or
+// differs from what gopi -readme would write, with the configured icon.
+func (that *Class) ReadmeStale(ctx context.Context, root string) (bool, error) {
+	pth := path.Join(root, that.config.ReadmeFile)
+	raw, err := that.renderReadme(ctx, root, "")
+	if err == nil {
+		raw, err = that.managedReadme(pth, raw)
+	}
+	if err != nil {
+		return false, err
+	}
+	current, err := that.fs.ReadFile(pth)
+	return err != nil || !bytes.Equal(current, raw), nil
+}
@@ -0,0 +1,52 @@
+package lib
+
+import (
+	"context"
+	"path"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	fsys := memFS{path.Join(tRoot, "go.mod"): []byte("module github.com/acme/demo\n")}
+	r := fakeRunner{"git tag --list v*": "v1.0.0\nv1.1.0\nv-bad\n"}
+	gopi, _ := newTestClassRunner(fsys, r)
+	gopi.config.Tag.Prefix = "v"
+	gopi.Name, gopi.Version, gopi.Repo = "demo", "1.2.0", "git@github.com:acme/demo.git"
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+	if d, err := gopi.Check(context.Background(), tRoot); err != nil || len(d) != 0 {
+		t.Fatal(d, err)
+	}
+
+	gopi.Version, gopi.Repo = "1.3.0", "https://github.com/acme/other"
+	d, err := gopi.Check(context.Background(), tRoot)
+	if err != nil || len(d) != 3 || d[0].Code != "tag" || d[1].Code != "gomod" || d[2].Code != "stale" {
+		t.Fatal(d, err)
+	}
+}
+
+func TestReadmeStale(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	gopi.config.AutoAccept = true
+	gopi.Name, gopi.Version = "demo", "1.0.0"
+	if stale, err := gopi.ReadmeStale(context.Background(), tRoot); err != nil || !stale {
+		t.Fatal(stale, err)
+	}
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+	if stale, err := gopi.ReadmeStale(context.Background(), tRoot); err != nil || stale {
+		t.Fatal(stale, err)
+	}
+
+	// an unchanged README is not rewritten, even without the review
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err != nil || gopi.written != 1 {
+		t.Fatal(gopi.written, err)
+	}
+	gopi.Version = "1.1.0"
+	if stale, err := gopi.ReadmeStale(context.Background(), tRoot); err != nil || !stale {
+		t.Fatal(stale, err)
+	}
+}
@@ -0,0 +1,130 @@
+package lib
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ChecksumResult is the outcome of verifying one file against a checksum file.
+type ChecksumResult struct {
+	File      string
+	Algorithm string
+	OK        bool
+	Err       error
+}
+
+var hashes = map[string]func() hash.Hash{"sha256": sha256.New, "sha512": sha512.New}
+
+// checksumFiles maps the enabled algorithms to their checksum file names:
+// checksums.txt for sha256 and checksums.sha512.txt for sha512.
+func (that *Class) checksumFiles() map[string]string {
+	file := that.config.Checksums.File
+	if file == "" {
+		return nil
+	}
+	res := map[string]string{"sha256": file}
+	if that.config.Checksums.SHA512 {
+		ext := path.Ext(file)
+		res["sha512"] = strings.TrimSuffix(file, ext) + ".sha512" + ext
+	}
+	return res
+}
+
+func (that *Class) sum(algorithm string, pth string) (string, error) {
+	raw, err := that.fs.ReadFile(pth)
+	if err != nil {
+		return "", err
+	}
+	h := hashes[algorithm]()
+	h.Write(raw)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// WriteChecksums writes the checksum files of the given binaries, in the
+// sha256sum format, to the directory of the binaries. It returns their paths.
+func (that *Class) WriteChecksums(binaries []string) ([]string, error) {
+	if len(binaries) == 0 {
+		return nil, nil
+	}
+	dir := path.Dir(binaries[0])
+	names := make([]string, len(binaries))
+	for i, b := range binaries {
+		if path.Dir(b) != dir {
+			return nil, fmt.Errorf("%s is not in %s, the binaries have to share a directory", b, dir)
+		}
+		names[i] = path.Base(b)
+	}
+	sort.Strings(names)
+
+	var written []string
+	for algorithm, file := range that.checksumFiles() {
+		var sb strings.Builder
+		for _, name := range names {
+			s, err := that.sum(algorithm, path.Join(dir, name))
+			if err != nil {
+				return written, fmt.Errorf("unable to checksum %s: %w", name, err)
+			}
+			fmt.Fprintf(&sb, "%s  %s\n", s, name)
+		}
+		pth := path.Join(dir, file)
+		if err := that.fs.WriteFile(pth, []byte(sb.String()), that.fileMode()); err != nil {
+			return written, fmt.Errorf("unable to write %s: %w", pth, err)
+		}
+		written = append(written, pth)
+	}
+	sort.Strings(written)
+	return written, nil
+}
+
+// VerifyChecksums checks the files of dir against the checksum files found
+// there, the sha256 one being required.
+func (that *Class) VerifyChecksums(dir string) ([]ChecksumResult, error) {
+	files := that.checksumFiles()
+	if files == nil {
+		return nil, errors.New("checksums are disabled, set checksums.file")
+	}
+	var res []ChecksumResult
+	for _, algorithm := range []string{"sha256", "sha512"} {
+		file, ok := files[algorithm]
+		if !ok {
+			continue
+		}
+		raw, err := that.fs.ReadFile(path.Join(dir, file))
+		if errors.Is(err, fs.ErrNotExist) && algorithm != "sha256" {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", file, err)
+		}
+		for n, line := range strings.Split(string(raw), "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			want, name, ok := strings.Cut(line, " ")
+			name = strings.TrimPrefix(strings.TrimPrefix(name, " "), "*")
+			if !ok || name == "" || path.Base(name) != name {
+				return nil, fmt.Errorf("%s:%d: invalid checksum line", file, n+1)
+			}
+			r := ChecksumResult{File: name, Algorithm: algorithm}
+			got, err := that.sum(algorithm, path.Join(dir, name))
+			switch {
+			case err != nil:
+				r.Err = err
+			case !strings.EqualFold(got, want):
+				r.Err = fmt.Errorf("%s mismatch", algorithm)
+			default:
+				r.OK = true
+			}
+			res = append(res, r)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,41 @@
+package lib
+
+import (
+	"path"
+	"testing"
+)
+
+func TestChecksums(t *testing.T) {
+	dist := path.Join(tRoot, "dist")
+	fsys := memFS{path.Join(dist, "demo_linux_amd64"): []byte("elf"), path.Join(dist, "demo_windows_amd64.exe"): []byte("pe")}
+	gopi, _ := newTestClass(fsys)
+	gopi.config.Checksums.File = "checksums.txt"
+	gopi.config.Checksums.SHA512 = true
+	written, err := gopi.WriteChecksums([]string{path.Join(dist, "demo_windows_amd64.exe"), path.Join(dist, "demo_linux_amd64")})
+	if err != nil || len(written) != 2 || written[0] != path.Join(dist, "checksums.sha512.txt") {
+		t.Fatal(written, err)
+	}
+	sums := string(fsys[path.Join(dist, "checksums.txt")])
+	want := "780d84b20d7ae7e6292919399348bdbf96025270136198083fc8a4da398b5ca9  demo_linux_amd64\n" +
+		"cdf69b25a417e25753dc086819d2cdfd3939f7d0e175136812e936284bebb4a4  demo_windows_amd64.exe\n"
+	if sums != want {
+		t.Fatal(sums)
+	}
+
+	res, err := gopi.VerifyChecksums(dist)
+	if err != nil || len(res) != 4 {
+		t.Fatal(res, err)
+	}
+	for _, r := range res {
+		if !r.OK {
+			t.Fatal(r)
+		}
+	}
+
+	fsys[path.Join(dist, "demo_linux_amd64")] = []byte("tampered")
+	delete(fsys, path.Join(dist, "demo_windows_amd64.exe"))
+	res, _ = gopi.VerifyChecksums(dist)
+	if res[0].OK || res[1].OK || res[1].Err == nil {
+		t.Fatal(res)
+	}
+}
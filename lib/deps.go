@@ -0,0 +1,59 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"golang.org/x/mod/modfile"
+	"gov/pkginfo"
+	"path"
+	"strings"
+)
+
+// Deps lists the modules required directly by the go.mod in root. With
+// resolve the versions come from `go list -m all`, which applies replace
+// directives and minimal version selection, instead of the go.mod text.
+func (that *Class) Deps(ctx context.Context, root string, resolve bool) ([]pkginfo.Dependency, error) {
+	pth := path.Join(root, "go.mod")
+	raw, err := that.fs.ReadFile(pth)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", pth, err)
+	}
+	mod, err := modfile.ParseLax(pth, raw, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", pth, err)
+	}
+
+	var deps []pkginfo.Dependency
+	for _, r := range mod.Require {
+		if !r.Indirect {
+			deps = append(deps, pkginfo.Dependency{Path: r.Mod.Path, Version: r.Mod.Version})
+		}
+	}
+	if !resolve || len(deps) == 0 {
+		return deps, nil
+	}
+
+	out, err := that.runner.Run(ctx, root, "go", "list", "-m", "all")
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the modules of %s: %w", root, err)
+	}
+	resolved := map[string]string{}
+	for _, line := range strings.Split(string(out), "\n") {
+		// path version [=> replacement [version]]
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		v := fields[1]
+		if len(fields) >= 5 && fields[2] == "=>" {
+			v = fields[4]
+		}
+		resolved[fields[0]] = v
+	}
+	for i, d := range deps {
+		if v, ok := resolved[d.Path]; ok {
+			deps[i].Version = v
+		}
+	}
+	return deps, nil
+}
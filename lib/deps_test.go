@@ -0,0 +1,44 @@
+package lib
+
+import (
+	"context"
+	"path"
+	"testing"
+)
+
+const tGoMod = `module example.com/tool
+
+go 1.19
+
+require (
+	golang.org/x/mod v0.8.0
+	gopkg.in/yaml.v3 v3.0.1
+	golang.org/x/sys v0.5.0 // indirect
+)
+`
+
+func TestDeps(t *testing.T) {
+	gopi, _ := newTestClass(memFS{path.Join(tRoot, "go.mod"): []byte(tGoMod)})
+	deps, err := gopi.Deps(context.Background(), tRoot, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deps) != 2 || deps[0].Path != "golang.org/x/mod" || deps[1].Version != "v3.0.1" {
+		t.Fatal(deps)
+	}
+}
+
+func TestDeps_resolve(t *testing.T) {
+	r := fakeRunner{"go list -m all": "example.com/tool\n" +
+		"golang.org/x/mod v0.8.0 => golang.org/x/mod v0.9.0\n" +
+		"golang.org/x/sys v0.5.0\n" +
+		"gopkg.in/yaml.v3 v3.0.2\n"}
+	gopi, _ := newTestClassRunner(memFS{path.Join(tRoot, "go.mod"): []byte(tGoMod)}, r)
+	deps, err := gopi.Deps(context.Background(), tRoot, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deps) != 2 || deps[0].Version != "v0.9.0" || deps[1].Version != "v3.0.2" {
+		t.Fatal(deps)
+	}
+}
@@ -0,0 +1,73 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const listFormat = `{{.ImportPath}}{{range .Imports}} {{.}}{{end}}`
+
+// diagram renders the internal import graph of the module in root as a
+// Mermaid flowchart. Packages are labeled relative to the module path and
+// only imports between packages of the module are drawn.
+func (that *Class) diagram(ctx context.Context, root string, modPath string) (string, error) {
+	if modPath == "" {
+		return "", nil
+	}
+	out, err := that.runner.Run(ctx, root, "go", "list", "-f", listFormat, "./...")
+	if err != nil {
+		return "", fmt.Errorf("unable to list the packages of %s: %w", modPath, err)
+	}
+
+	internal := func(pkg string) bool {
+		return pkg == modPath || strings.HasPrefix(pkg, modPath+"/")
+	}
+	var pkgs []string
+	edges := map[string][]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !internal(fields[0]) {
+			continue
+		}
+		pkgs = append(pkgs, fields[0])
+		for _, imp := range fields[1:] {
+			if internal(imp) {
+				edges[fields[0]] = append(edges[fields[0]], imp)
+			}
+		}
+	}
+	if len(pkgs) == 0 {
+		return "", nil
+	}
+	sort.Strings(pkgs)
+	ids := map[string]string{}
+	for i, p := range pkgs {
+		ids[p] = fmt.Sprintf("p%d", i)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("```mermaid\ngraph TD\n")
+	for _, p := range pkgs {
+		sb.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", ids[p], diagramLabel(p, modPath)))
+	}
+	for _, p := range pkgs {
+		targets := edges[p]
+		sort.Strings(targets)
+		for _, imp := range targets {
+			if id, ok := ids[imp]; ok {
+				sb.WriteString(fmt.Sprintf("    %s --> %s\n", ids[p], id))
+			}
+		}
+	}
+	sb.WriteString("```")
+	return sb.String(), nil
+}
+
+func diagramLabel(pkg string, modPath string) string {
+	if pkg == modPath {
+		return moduleName(modPath)
+	}
+	return strings.TrimPrefix(pkg, modPath+"/")
+}
@@ -0,0 +1,39 @@
+package lib
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestDiagram(t *testing.T) {
+	r := fakeRunner{"go list -f " + listFormat + " ./...": "example.com/tool fmt example.com/tool/lib\n" +
+		"example.com/tool/lib example.com/tool/version strings\n" +
+		"example.com/tool/version fmt\n"}
+	gopi, _ := newTestClassRunner(memFS{}, r)
+	got, err := gopi.diagram(context.Background(), tRoot, "example.com/tool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "```mermaid\ngraph TD\n" +
+		"    p0[\"tool\"]\n    p1[\"lib\"]\n    p2[\"version\"]\n" +
+		"    p0 --> p1\n    p1 --> p2\n```"
+	if string(got) != want {
+		t.Fatalf("unexpected diagram: %q", got)
+	}
+}
+
+func TestCreateReadme_architecture(t *testing.T) {
+	fsys := memFS{path.Join(tRoot, "go.mod"): []byte("module example.com/tool\n")}
+	r := fakeRunner{"go list -f " + listFormat + " ./...": "example.com/tool example.com/tool/lib\nexample.com/tool/lib\n"}
+	gopi, _ := newTestClassRunner(fsys, r)
+	gopi.config.ArchitectureDiagram = true
+	gopi.config.Tpl = "{{ .Architecture }}"
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(fsys[path.Join(tRoot, "README.md")]), "p0 --> p1") {
+		t.Fail()
+	}
+}
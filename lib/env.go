@@ -0,0 +1,61 @@
+package lib
+
+import (
+	"gov/pkginfo"
+	"strings"
+)
+
+const envPrefix = "GOPI_PKG_"
+
+// envFields maps the GOPI_PKG_<FIELD> variables to the package info fields
+// they override.
+var envFields = map[string]func(info *pkginfo.Info) *string{
+	"NAME":        func(info *pkginfo.Info) *string { return &info.Name },
+	"VERSION":     func(info *pkginfo.Info) *string { return &info.Version },
+	"DESCRIPTION": func(info *pkginfo.Info) *string { return &info.Description },
+	"TENANT":      func(info *pkginfo.Info) *string { return &info.Tenant },
+	"REPO":        func(info *pkginfo.Info) *string { return &info.Repo },
+	"TYPE":        func(info *pkginfo.Info) *string { return &info.Type },
+}
+
+// applyEnv overrides the loaded package info with the GOPI_PKG_* environment
+// variables (GOPI_PKG_ARCH is a comma separated list). The values read from
+// the file are kept so the overrides never end up in pkg.info.
+func (that *Class) applyEnv() {
+	that.stored = that.Info
+	that.stored.Arch = append([]string(nil), that.Info.Arch...)
+	that.overrides = map[string]string{}
+	for key, field := range envFields {
+		if v, ok := that.lookupEnv(envPrefix + key); ok {
+			*field(&that.Info) = v
+			that.overrides[key] = v
+		}
+	}
+	if v, ok := that.lookupEnv(envPrefix + "ARCH"); ok {
+		that.Arch = pkginfo.SplitList(v)
+		that.overrides["ARCH"] = strings.Join(that.Arch, ",")
+	}
+}
+
+func (that *Class) lookupEnv(key string) (string, bool) {
+	v := that.getenv(key)
+	return v, v != ""
+}
+
+// fileInfo is the package info to write back: fields still holding their
+// environment override get the value read from the file again.
+func (that *Class) fileInfo() *pkginfo.Info {
+	info := that.Info
+	for key, v := range that.overrides {
+		if key == "ARCH" {
+			if strings.Join(info.Arch, ",") == v {
+				info.Arch = that.stored.Arch
+			}
+			continue
+		}
+		if field := envFields[key]; *field(&info) == v {
+			*field(&info) = *field(&that.stored)
+		}
+	}
+	return &info
+}
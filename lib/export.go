@@ -0,0 +1,19 @@
+package lib
+
+import "gov/manifest"
+
+// Export is the package info as the manifest of another ecosystem, a
+// package.json, pyproject.toml or Cargo.toml, see manifest.Formats.
+func (that *Class) Export(format string) ([]byte, error) {
+	doc := &manifest.Document{
+		Name:        that.Name,
+		Version:     that.Version,
+		Description: that.Description,
+		Repo:        that.Repo,
+		License:     that.License,
+	}
+	for _, m := range that.Maintainers {
+		doc.Authors = append(doc.Authors, manifest.Author{Name: m.Name, Email: m.Email})
+	}
+	return manifest.Marshal(doc, format)
+}
@@ -0,0 +1,17 @@
+package lib
+
+import (
+	"gov/pkginfo"
+	"strings"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.Name, gopi.Version, gopi.License = "demo", "1.0.0", "MIT"
+	gopi.Maintainers = []pkginfo.Maintainer{{Name: "Jane Doe", Email: "jane@acme.io", Role: "lead"}}
+	raw, err := gopi.Export("cargo")
+	if err != nil || !strings.Contains(string(raw), `authors = ["Jane Doe <jane@acme.io>"]`) {
+		t.Fatal(string(raw), err)
+	}
+}
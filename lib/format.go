@@ -0,0 +1,85 @@
+package lib
+
+import (
+	"fmt"
+	"gov/pkginfo"
+	"path"
+)
+
+const jsonExt = ".json"
+
+func isJSON(content []byte) bool {
+	return pkginfo.IsJSON(content)
+}
+
+// pkgPath returns the package info file in root: the existing pkg.info or
+// pkg.info.json, otherwise the name matching the configured format.
+func (that *Class) pkgPath(root string) string {
+	yml := path.Join(root, that.config.PkgInfoFile)
+	js := yml + jsonExt
+	for _, p := range []string{yml, js} {
+		if _, err := that.fs.Stat(p); err == nil {
+			return p
+		}
+	}
+	if that.config.PkgInfoFormat == "json" {
+		return js
+	}
+	return yml
+}
+
+// PkgFile returns the path of the package info file used for root.
+func (that *Class) PkgFile(root string) string {
+	return that.pkgPath(root)
+}
+
+// checkFormat refuses a configured format other than the one of the existing
+// file at pth, which would otherwise be rewritten in that format under its
+// old name.
+func (that *Class) checkFormat(pth string) error {
+	content, err := that.fs.ReadFile(pth)
+	if that.config.PkgInfoFormat == "" || err != nil {
+		return nil
+	}
+	existing := "yaml"
+	if isJSON(content) {
+		existing = "json"
+	}
+	if existing != that.config.PkgInfoFormat {
+		return fmt.Errorf("%s is %s, writing it as %s would keep its name; remove it first or drop -format", pth, existing, that.config.PkgInfoFormat)
+	}
+	return nil
+}
+
+// pkgFormat is the configured format, or else the format of the existing file.
+func (that *Class) pkgFormat(pth string) string {
+	if that.config.PkgInfoFormat != "" {
+		return that.config.PkgInfoFormat
+	}
+	if content, err := that.fs.ReadFile(pth); err == nil && isJSON(content) {
+		return "json"
+	}
+	return "yaml"
+}
+
+// marshalPkg encodes the package info for pth. An existing yaml file is
+// patched in place so its comments, key order and indentation survive.
+func (that *Class) marshalPkg(pth string, format string) ([]byte, error) {
+	if format == "yaml" {
+		if old, err := that.fs.ReadFile(pth); err == nil && !isJSON(old) {
+			if raw, err := pkginfo.Patch(old, that.fileInfo()); err == nil {
+				return raw, nil
+			}
+		}
+	}
+	return pkginfo.Marshal(that.fileInfo(), format)
+}
+
+func (that *Class) unmarshalPkg(content []byte) error {
+	info, err := pkginfo.Parse(content)
+	if err != nil {
+		return err
+	}
+	that.Info = *info
+	return nil
+}
@@ -0,0 +1,99 @@
+package lib
+
+import (
+	"fmt"
+	"gov/version"
+	"html"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// mdSpecial escapes the characters that start markdown emphasis, code, links,
+// html and table cells inside a line of text.
+var mdSpecial = strings.NewReplacer(`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "|", `\|`)
+
+// templateFuncs are the helpers available to the README templates, besides
+// multiline and snippet. Values are written as is: escape them with mdescape,
+// or the html and urlquery builtins, where they could break the markdown.
+var templateFuncs = template.FuncMap{
+	"mdescape":  mdSpecial.Replace,
+	"upper":     strings.ToUpper,
+	"lower":     strings.ToLower,
+	"title":     title,
+	"now":       now,
+	"badge":     badge,
+	"codeblock": codeblock,
+	"toc":       toc,
+	"join": func(sep string, s []string) string {
+		return strings.Join(s, sep)
+	},
+	"semver": version.New,
+	"major": func(v string) (uint64, error) {
+		s, err := semver(v)
+		return s.Major, err
+	},
+	"minor": func(v string) (uint64, error) {
+		s, err := semver(v)
+		return s.Minor, err
+	},
+	"patch": func(v string) (uint64, error) {
+		s, err := semver(v)
+		return s.Patch, err
+	},
+	"prerelease": func(v string) (string, error) {
+		s, err := semver(v)
+		return s.Prerelease, err
+	},
+}
+
+// semver parses v for the accessors, the zero version when invalid.
+func semver(v string) (version.Class, error) {
+	s, err := version.New(v)
+	if err != nil {
+		return version.Class{}, err
+	}
+	return *s, nil
+}
+
+// title upper cases the first letter of every word of st.
+func title(st string) string {
+	prev := ' '
+	return strings.Map(func(r rune) rune {
+		start := unicode.IsSpace(prev) || prev == '-' || prev == '_'
+		prev = r
+		if start {
+			return unicode.ToTitle(r)
+		}
+		return r
+	}, st)
+}
+
+// now is the current time in layout, RFC 3339 when none is given, e.g.
+// {{ now "2006-01-02" }}.
+func now(layout ...string) string {
+	if len(layout) == 0 {
+		return time.Now().Format(time.RFC3339)
+	}
+	return time.Now().Format(layout[0])
+}
+
+// badge is a shields.io badge image, e.g. {{ badge "Version" .Version "blue" }}.
+func badge(label string, message string, color string) string {
+	src := fmt.Sprintf("https://img.shields.io/static/v1?label=%s&message=%s&color=%s",
+		url.QueryEscape(label), url.QueryEscape(message), url.QueryEscape(color))
+	return fmt.Sprintf(`<img src="%s" alt="%s"/>`, html.EscapeString(src), html.EscapeString(strings.ToLower(label)))
+}
+
+// codeblock fences code as a markdown code block in lang, the fence longer
+// than any backtick run of code.
+func codeblock(lang string, code string) string {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + lang + "\n" + strings.Trim(code, "\n") + "\n" + fence
+}
@@ -0,0 +1,62 @@
+package lib
+
+import (
+	"context"
+	"path"
+	"testing"
+)
+
+func TestTitle(t *testing.T) {
+	if title("go package-info tool") != "Go Package-Info Tool" {
+		t.Fail()
+	}
+}
+
+func TestCodeblock(t *testing.T) {
+	if codeblock("sh", "\ngo install\n") != "```sh\ngo install\n```" {
+		t.Fail()
+	}
+	if codeblock("md", "```go\n```") != "````md\n```go\n```\n````" {
+		t.Fail()
+	}
+}
+
+func TestBadge(t *testing.T) {
+	if badge("Go Version", "1.19", "blue") != `<img src="https://img.shields.io/static/v1?label=Go+Version&amp;message=1.19&amp;color=blue" alt="go version"/>` {
+		t.Fail()
+	}
+}
+
+func TestCreateReadme_funcs(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	gopi.Name, gopi.Version = "Demo", "1.2.3-rc.1"
+	gopi.Keywords = []string{"cli", "tooling"}
+	gopi.config.Tpl = "{{ lower .Name }} v{{ major .Version }}.{{ minor .Version }} {{ prerelease .Version }} " +
+		"{{ (semver .Version).Patch }} {{ .Keywords | join \", \" }}\n"
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(fsys[path.Join(tRoot, "README.md")]); got != "demo v1.2 rc.1 3 cli, tooling\n" {
+		t.Fatalf("%q", got)
+	}
+
+	gopi.Version = "latest"
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err == nil {
+		t.Fail()
+	}
+}
+
+func TestCreateReadme_markdown(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	gopi.Description = `Fast & "simple" tool`
+	gopi.Name = "my_tool"
+	gopi.config.Tpl = "{{ .Description }}\n{{ mdescape .Name }}\n"
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(fsys[path.Join(tRoot, "README.md")]); got != "Fast & \"simple\" tool\nmy\\_tool\n" {
+		t.Fatalf("%q", got)
+	}
+}
@@ -0,0 +1,84 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GateResult is the outcome of one quality gate.
+type GateResult struct {
+	Name    string
+	Command string
+	Log     string
+	Elapsed time.Duration
+	Err     error
+}
+
+func (that GateResult) String() string {
+	status := "ok  "
+	if that.Err != nil {
+		status = "FAIL"
+	}
+	return fmt.Sprintf("%s %-5s %s (%s)", status, that.Name, that.Command, that.Elapsed.Round(time.Millisecond))
+}
+
+type gate struct {
+	name string
+	args []string
+}
+
+// gates are the configured quality gates with their go arguments, in the
+// order they run.
+func (that *Class) gates() []gate {
+	var res []gate
+	if that.config.Gates.Vet {
+		res = append(res, gate{"vet", []string{"vet", "./..."}})
+	}
+	if that.config.Gates.Test {
+		res = append(res, gate{"test", append(append([]string{"test"}, that.config.Gates.TestFlags...), "./...")})
+	}
+	return res
+}
+
+// Gates runs the configured quality gates of the package in root, every one
+// of them even when an earlier one fails.
+func (that *Class) Gates(ctx context.Context, root string) []GateResult {
+	var res []GateResult
+	for _, g := range that.gates() {
+		r := GateResult{Name: g.name, Command: "go " + strings.Join(g.args, " ")}
+		if err := ctx.Err(); err != nil {
+			r.Err = err
+			res = append(res, r)
+			continue
+		}
+		started := time.Now()
+		out, err := that.runner.Run(ctx, root, "go", g.args...)
+		r.Elapsed, r.Log = time.Since(started), strings.TrimSpace(string(out))
+		if err != nil {
+			r.Err = fmt.Errorf("%s failed: %w", r.Command, err)
+		}
+		res = append(res, r)
+	}
+	return res
+}
+
+// Gate runs the quality gates, printing their summary and the output of the
+// failed ones, and fails when one of them does.
+func (that *Class) Gate(ctx context.Context, root string) error {
+	var failed []string
+	for _, r := range that.Gates(ctx, root) {
+		if r.Err != nil {
+			failed = append(failed, r.Name)
+			if r.Log != "" {
+				fmt.Printf("==> %s\n%s\n", r.Name, r.Log)
+			}
+		}
+		fmt.Println(r.String())
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("quality gate(s) failed: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package lib
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGates(t *testing.T) {
+	gopi, _ := newTestClassRunner(memFS{}, fakeRunner{"go vet ./...": ""})
+	if res := gopi.Gates(context.Background(), tRoot); len(res) != 0 {
+		t.Fatal(res)
+	}
+	gopi.config.Gates.Vet, gopi.config.Gates.Test = true, true
+	gopi.config.Gates.TestFlags = []string{"-race"}
+	res := gopi.Gates(context.Background(), tRoot)
+	if len(res) != 2 || res[0].Err != nil || res[1].Err == nil || res[1].Command != "go test -race ./..." {
+		t.Fatal(res)
+	}
+	if !strings.HasPrefix(res[1].String(), "FAIL test  go test -race ./...") {
+		t.Fatal(res[1].String())
+	}
+	if err := gopi.Gate(context.Background(), tRoot); err == nil || err.Error() != "quality gate(s) failed: test" {
+		t.Fatal(err)
+	}
+}
+
+func TestGate_ok(t *testing.T) {
+	gopi, _ := newTestClassRunner(memFS{}, fakeRunner{"go vet ./...": "", "go test ./...": "ok  \tdemo\t0.01s"})
+	gopi.config.Gates.Vet, gopi.config.Gates.Test = true, true
+	if err := gopi.Gate(context.Background(), tRoot); err != nil {
+		t.Fatal(err)
+	}
+}
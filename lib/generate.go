@@ -0,0 +1,104 @@
+package lib
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path"
+	"text/template"
+)
+
+var generators = map[string]string{
+	"makefile": "Makefile",
+	"justfile": "justfile",
+	"version":  "version_gen.go",
+}
+
+// generatedPath is where the kind generator writes in root, the version file
+// location being configurable.
+func (that *Class) generatedPath(root string, kind string) string {
+	if kind == "version" && that.config.VersionFile.Path != "" {
+		return path.Join(root, that.config.VersionFile.Path)
+	}
+	return path.Join(root, generators[kind])
+}
+
+// goPackage is the package clause of the generated version file: the
+// configured one, else main at the package root and the directory name below.
+func (that *Class) goPackage(root string, pth string) string {
+	if that.config.VersionFile.Package != "" {
+		return that.config.VersionFile.Package
+	}
+	if path.Dir(pth) == path.Clean(root) {
+		return "main"
+	}
+	return path.Base(path.Dir(pth))
+}
+
+// Generate renders one of the embedded templates/<kind>.tpl files with the
+// package info and writes it to its conventional file name in root.
+func (that *Class) Generate(ctx context.Context, root string, kind string, force bool) error {
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	if _, ok := generators[kind]; !ok {
+		return fmt.Errorf("unknown generator %s, available: makefile, justfile, version", kind)
+	}
+	pth := that.generatedPath(root, kind)
+	fileName := path.Base(pth)
+
+	raw, err := fs.ReadFile(that.config.Templates, path.Join("templates", kind+".tpl"))
+	if err != nil {
+		return fmt.Errorf("unable to load the %s template: %w", kind, err)
+	}
+	tpl, err := template.New(kind).Parse(string(raw))
+	if err != nil {
+		return fmt.Errorf("unable to parse the %s template: %w", kind, err)
+	}
+
+	if _, err = that.fs.Stat(pth); err == nil && !force {
+		return fmt.Errorf("a %s already exists in %s, use -force to overwrite it", fileName, path.Dir(pth))
+	}
+
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+	downloads, err := that.downloads()
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	err = tpl.Execute(&buf, map[string]interface{}{
+		"Name":        that.Name,
+		"Version":     that.Version,
+		"Channel":     that.Channel,
+		"Tenant":      that.Tenant,
+		"Repo":        that.Repo,
+		"Arch":        that.Platforms(),
+		"Keywords":    that.Keywords,
+		"PkgInfoFile": that.config.PkgInfoFile,
+		"BinaryName":  that.binaryName(),
+		"InstallPath": that.installPath(),
+		"Downloads":   downloads,
+		"GoPackage":   that.goPackage(root, pth),
+	})
+	if err != nil {
+		return fmt.Errorf("while processing the %s template: %w", kind, err)
+	}
+	out := buf.Bytes()
+	if path.Ext(pth) == ".go" {
+		if out, err = format.Source(out); err != nil {
+			return fmt.Errorf("the %s template produced invalid go code: %w", kind, err)
+		}
+	}
+
+	err = that.writeFile(pth, out, that.fileMode())
+	if err != nil {
+		return fmt.Errorf("unable to write %s file in %s: %w", fileName, path.Dir(pth), err)
+	}
+	fmt.Printf("%s written to %s\n", fileName, pth)
+	return nil
+}
@@ -0,0 +1,86 @@
+package lib
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestGenerate_makefile(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	gopi.Name = "demo"
+	gopi.Version = "1.4.0"
+	if err := gopi.Generate(context.Background(), tRoot, "makefile", false); err != nil {
+		t.Fatal(err)
+	}
+
+	got := string(fsys[path.Join(tRoot, "Makefile")])
+	if !strings.Contains(got, "NAME    := demo") || !strings.Contains(got, "VERSION := 1.4.0") {
+		t.Fail()
+	}
+	if !strings.Contains(got, "\nrelease: validate test readme\n") {
+		t.Fail()
+	}
+}
+
+func TestGenerate_justfile(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	gopi.Name = "demo"
+	if err := gopi.Generate(context.Background(), tRoot, "justfile", false); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(fsys[path.Join(tRoot, "justfile")]), `NAME := "demo"`) {
+		t.Fail()
+	}
+}
+
+func TestGenerate_install(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	gopi.Name = "demo"
+	gopi.config.BinaryName = "demo-cli"
+	gopi.config.InstallDir = "/opt/bin"
+	if err := gopi.Generate(context.Background(), tRoot, "makefile", false); err != nil {
+		t.Fatal(err)
+	}
+
+	got := string(fsys[path.Join(tRoot, "Makefile")])
+	if !strings.Contains(got, "go build -o bin/$(BINARY)") || !strings.Contains(got, "install -m 0755 bin/$(BINARY) /opt/bin/demo-cli") {
+		t.Fatal(got)
+	}
+}
+
+func TestDownloads(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.Name, gopi.Version, gopi.Repo = "demo", "1.2.0", "https://github.com/acme/demo"
+	gopi.Arch = []string{"linux_arm64", "windows"}
+	gopi.config.DownloadURL = "{{ .Repo }}/releases/download/v{{ .Version }}/{{ .Binary }}_{{ .OS }}_{{ .Arch }}{{ .Ext }}"
+	d, err := gopi.downloads()
+	if err != nil || len(d) != 2 {
+		t.Fatal(err)
+	}
+	if d[0].URL != "https://github.com/acme/demo/releases/download/v1.2.0/demo_linux_arm64" ||
+		d[1].URL != "https://github.com/acme/demo/releases/download/v1.2.0/demo_windows_amd64.exe" {
+		t.Fatal(d)
+	}
+}
+
+func TestGenerate_version(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	gopi.Name, gopi.Version, gopi.Tenant = "demo", "1.4.0", `acme "inc"`
+	gopi.config.VersionFile.Path = "internal/build/version_gen.go"
+	if err := gopi.Generate(context.Background(), tRoot, "version", false); err != nil {
+		t.Fatal(err)
+	}
+
+	got := string(fsys[path.Join(tRoot, "internal/build/version_gen.go")])
+	if !strings.Contains(got, "package build\n") || !strings.Contains(got, "Version = \"1.4.0\"") ||
+		!strings.Contains(got, `Tenant  = "acme \"inc\""`) {
+		t.Fatal(got)
+	}
+}
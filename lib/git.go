@@ -0,0 +1,80 @@
+package lib
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"gov/pkginfo"
+	"path"
+	"strings"
+)
+
+// gitRemote returns the url of the origin remote, asking git first and
+// falling back to reading .git/config when git is not available.
+func (that *Class) gitRemote(ctx context.Context, root string) string {
+	out, err := that.runner.Run(ctx, root, "git", "remote", "get-url", "origin")
+	if err == nil {
+		return strings.TrimSpace(string(out))
+	}
+
+	raw, err := that.fs.ReadFile(path.Join(root, ".git", "config"))
+	if err != nil {
+		return ""
+	}
+	inOrigin := false
+	sc := bufio.NewScanner(bytes.NewReader(raw))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+		if k, v, ok := strings.Cut(line, "="); inOrigin && ok && strings.TrimSpace(k) == "url" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+// normalizeRepo turns the ssh/git/https flavours of a remote url into the
+// https address of the repository: git@github.com:acme/tool.git -> https://github.com/acme/tool
+func normalizeRepo(url string) string {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return ""
+	}
+
+	if i := strings.Index(url, "://"); i >= 0 {
+		rest := url[i+3:]
+		if at := strings.Index(rest, "@"); at >= 0 && at < strings.Index(rest+"/", "/") {
+			rest = rest[at+1:]
+		}
+		host, p, _ := strings.Cut(rest, "/")
+		if strings.HasPrefix(url, "ssh://") || strings.HasPrefix(url, "git+ssh://") {
+			host, _, _ = strings.Cut(host, ":")
+		}
+		url = host + "/" + p
+	} else if m := pkginfo.ScpLike.FindStringSubmatch(url); m != nil {
+		url = m[1] + "/" + strings.TrimPrefix(m[2], "/")
+	}
+
+	return "https://" + strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+}
+
+// SyncRepo updates the repo field from the git remote. It reports whether the
+// field changed.
+func (that *Class) SyncRepo(ctx context.Context, root string) (bool, error) {
+	remote := normalizeRepo(that.gitRemote(ctx, root))
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	if remote == "" {
+		return false, fmt.Errorf("no origin remote found in %s", root)
+	}
+	if remote == that.Repo {
+		return false, nil
+	}
+	that.Repo = remote
+	return true, nil
+}
@@ -0,0 +1,63 @@
+package lib
+
+import (
+	"context"
+	"path"
+	"testing"
+)
+
+func TestNormalizeRepo(t *testing.T) {
+	cases := map[string]string{
+		"git@github.com:acme/tool.git":              "https://github.com/acme/tool",
+		"ssh://git@gitlab.com:2222/acme/g/tool.git": "https://gitlab.com/acme/g/tool",
+		"https://user@github.com/acme/tool.git":     "https://github.com/acme/tool",
+		"git://github.com/acme/tool":                "https://github.com/acme/tool",
+		"https://github.com/acme/tool/":             "https://github.com/acme/tool",
+		"":                                          "",
+	}
+	for in, want := range cases {
+		if got := normalizeRepo(in); got != want {
+			t.Errorf("normalizeRepo(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGitRemote_runner(t *testing.T) {
+	gopi, _ := newTestClassRunner(memFS{}, fakeRunner{
+		"git remote get-url origin": "git@github.com:acme/tool.git\n",
+	})
+	if gopi.gitRemote(context.Background(), tRoot) != "git@github.com:acme/tool.git" {
+		t.Fail()
+	}
+}
+
+func TestGitRemote_config_fallback(t *testing.T) {
+	fsys := memFS{}
+	fsys[path.Join(tRoot, ".git/config")] = []byte(`[core]
+	bare = false
+[remote "upstream"]
+	url = git@github.com:other/tool.git
+[remote "origin"]
+	url = git@github.com:acme/tool.git
+	fetch = +refs/heads/*:refs/remotes/origin/*
+`)
+	gopi, _ := newTestClass(fsys)
+	if gopi.gitRemote(context.Background(), tRoot) != "git@github.com:acme/tool.git" {
+		t.Fail()
+	}
+}
+
+func TestSyncRepo(t *testing.T) {
+	gopi, _ := newTestClassRunner(memFS{}, fakeRunner{
+		"git remote get-url origin": "git@github.com:acme/tool.git\n",
+	})
+	gopi.Repo = "https://github.com/acme/old"
+	changed, err := gopi.SyncRepo(context.Background(), tRoot)
+	if err != nil || !changed || gopi.Repo != "https://github.com/acme/tool" {
+		t.Fail()
+	}
+	changed, _ = gopi.SyncRepo(context.Background(), tRoot)
+	if changed {
+		t.Fail()
+	}
+}
@@ -0,0 +1,95 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path"
+	"strings"
+)
+
+// API is the go doc of the package in the root of the project.
+type API struct {
+	Name     string
+	Synopsis string
+	Doc      string
+	Symbols  []Symbol
+}
+
+// Symbol is an exported constant, variable, function, type or method.
+type Symbol struct {
+	Kind string
+	Name string
+	Decl string
+	Doc  string
+}
+
+// apiDoc extracts the documentation of the package in root, its test files
+// aside, from its source comments.
+func (that *Class) apiDoc(ctx context.Context, root string) (*API, error) {
+	out, err := that.runner.Run(ctx, root, "go", "list", "-f", `{{ join .GoFiles "\n" }}`, ".")
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the go files of %s: %w", root, err)
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range strings.Fields(string(out)) {
+		src, err := that.fs.ReadFile(path.Join(root, name))
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %w", name, err)
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+	pkg, err := doc.NewFromFiles(fset, files, that.goModule(root))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &API{Name: pkg.Name, Synopsis: pkg.Synopsis(pkg.Doc), Doc: strings.TrimSpace(pkg.Doc)}
+	decl := func(node interface{}) string {
+		if fn, ok := node.(*ast.FuncDecl); ok {
+			c := *fn
+			c.Body, c.Doc = nil, nil
+			node = &c
+		}
+		var sb strings.Builder
+		_ = printer.Fprint(&sb, fset, node)
+		return sb.String()
+	}
+	values := func(kind string, vs []*doc.Value) {
+		for _, v := range vs {
+			res.Symbols = append(res.Symbols, Symbol{Kind: kind, Name: strings.Join(v.Names, ", "), Decl: decl(v.Decl), Doc: strings.TrimSpace(v.Doc)})
+		}
+	}
+	funcs := func(kind string, fs []*doc.Func) {
+		for _, f := range fs {
+			name := f.Name
+			if f.Recv != "" {
+				name = strings.TrimPrefix(f.Recv, "*") + "." + f.Name
+			}
+			res.Symbols = append(res.Symbols, Symbol{Kind: kind, Name: name, Decl: decl(f.Decl), Doc: strings.TrimSpace(f.Doc)})
+		}
+	}
+	values("const", pkg.Consts)
+	values("var", pkg.Vars)
+	funcs("func", pkg.Funcs)
+	for _, t := range pkg.Types {
+		res.Symbols = append(res.Symbols, Symbol{Kind: "type", Name: t.Name, Decl: decl(t.Decl), Doc: strings.TrimSpace(t.Doc)})
+		values("const", t.Consts)
+		values("var", t.Vars)
+		funcs("func", t.Funcs)
+		funcs("method", t.Methods)
+	}
+	return res, nil
+}
@@ -0,0 +1,67 @@
+package lib
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+)
+
+const tGoSrc = `// Package greet says hello. It is polite.
+package greet
+
+// Greeter greets people.
+type Greeter struct {
+	Name   string
+	secret string
+}
+
+// New returns a Greeter.
+func New(name string) *Greeter {
+	return &Greeter{Name: name}
+}
+
+// Hello greets who.
+func (g *Greeter) Hello(who string) string {
+	return "hello " + who
+}
+
+func hidden() {}
+`
+
+func TestAPIDoc(t *testing.T) {
+	fsys := memFS{path.Join(tRoot, "greet.go"): []byte(tGoSrc)}
+	r := fakeRunner{`go list -f {{ join .GoFiles "\n" }} .`: "greet.go\n"}
+	gopi, _ := newTestClassRunner(fsys, r)
+	api, err := gopi.apiDoc(context.Background(), tRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.Name != "greet" || api.Synopsis != "Package greet says hello." || len(api.Symbols) != 3 {
+		t.Fatal(api)
+	}
+	s := api.Symbols
+	if s[0].Kind != "type" || strings.Contains(s[0].Decl, "secret") || s[0].Doc != "Greeter greets people." {
+		t.Fatal(s[0])
+	}
+	if s[1].Name != "New" || s[1].Decl != "func New(name string) *Greeter" {
+		t.Fatal(s[1])
+	}
+	if s[2].Kind != "method" || s[2].Name != "Greeter.Hello" || s[2].Decl != "func (g *Greeter) Hello(who string) string" {
+		t.Fatal(s[2])
+	}
+}
+
+func TestCreateReadme_api(t *testing.T) {
+	fsys := memFS{path.Join(tRoot, "greet.go"): []byte(tGoSrc)}
+	r := fakeRunner{`go list -f {{ join .GoFiles "\n" }} .`: "greet.go\n"}
+	gopi, _ := newTestClassRunner(fsys, r)
+	gopi.config.APIDoc = true
+	gopi.config.Tpl = "{{ .API.Synopsis }}{{ range .API.Symbols }} {{ .Name }}{{ end }}\n"
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+	if string(fsys[path.Join(tRoot, "README.md")]) != "Package greet says hello. Greeter New Greeter.Hello\n" {
+		t.Fatalf("%q", fsys[path.Join(tRoot, "README.md")])
+	}
+}
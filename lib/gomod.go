@@ -0,0 +1,69 @@
+package lib
+
+import (
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"path"
+	"strings"
+)
+
+// goModule returns the module path declared in root/go.mod, or "" outside a module.
+func (that *Class) goModule(root string) string {
+	raw, err := that.fs.ReadFile(path.Join(root, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	return modfile.ModulePath(raw)
+}
+
+// Module is the module path of the go.mod in root, empty without one.
+func (that *Class) Module(root string) string {
+	return that.goModule(root)
+}
+
+// moduleName is the last path element of the module, ignoring a major
+// version suffix: github.com/acme/tool/v2 -> tool
+func moduleName(modPath string) string {
+	if modPath == "" {
+		return ""
+	}
+	prefix, _, ok := module.SplitPathVersion(modPath)
+	if !ok {
+		prefix = modPath
+	}
+	return path.Base(prefix)
+}
+
+// moduleRepo guesses the repository url for modules hosted on the well known
+// forges. Vanity import paths can't be resolved offline and yield "".
+func moduleRepo(modPath string) string {
+	prefix, _, ok := module.SplitPathVersion(modPath)
+	if !ok {
+		prefix = modPath
+	}
+	parts := strings.Split(prefix, "/")
+	switch parts[0] {
+	case "github.com", "bitbucket.org":
+		if len(parts) >= 3 {
+			return "https://" + strings.Join(parts[:3], "/")
+		}
+	case "gitlab.com":
+		if len(parts) >= 3 {
+			return "https://" + prefix
+		}
+	}
+	return ""
+}
+
+// goVersion is the go directive of root/go.mod, or "" outside a module.
+func (that *Class) goVersion(root string) string {
+	raw, err := that.fs.ReadFile(path.Join(root, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	f, err := modfile.ParseLax("go.mod", raw, nil)
+	if err != nil || f.Go == nil {
+		return ""
+	}
+	return f.Go.Version
+}
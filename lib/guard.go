@@ -0,0 +1,60 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// inGit reports whether root is inside a git work tree.
+func (that *Class) inGit(ctx context.Context, root string) bool {
+	out, err := that.runner.Run(ctx, root, "git", "rev-parse", "--is-inside-work-tree")
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
+
+// branch is the checked out branch, the CI one when HEAD is detached in CI.
+func (that *Class) branch(ctx context.Context, root string) (string, error) {
+	out, err := that.runner.Run(ctx, root, "git", "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("unable to read the current branch: %w", err)
+	}
+	b := strings.TrimSpace(string(out))
+	if c := that.CI(); b == "HEAD" && c != nil && c.Branch != "" {
+		b = c.Branch
+	}
+	return b, nil
+}
+
+// Guard refuses to let a command change the package when the git work tree
+// of root has uncommitted changes or the branch is not one of the configured
+// ones. Outside a git work tree there is nothing to guard.
+func (that *Class) Guard(ctx context.Context, root string) error {
+	guards := that.config.Guards
+	if (!guards.CleanTree && len(guards.Branches) == 0) || !that.inGit(ctx, root) {
+		return nil
+	}
+	if guards.CleanTree {
+		out, err := that.runner.Run(ctx, root, "git", "status", "--porcelain")
+		if err != nil {
+			return fmt.Errorf("unable to read the git status: %w", err)
+		}
+		if strings.TrimSpace(string(out)) != "" {
+			return errors.New("the working tree has uncommitted changes, commit or stash them first (guards.cleanTree)")
+		}
+	}
+	if len(guards.Branches) == 0 {
+		return nil
+	}
+	b, err := that.branch(ctx, root)
+	if err != nil {
+		return err
+	}
+	for _, pattern := range guards.Branches {
+		if ok, _ := path.Match(pattern, b); ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("branch %s is not one of %s (guards.branches)", b, strings.Join(guards.Branches, ", "))
+}
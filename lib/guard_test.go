@@ -0,0 +1,40 @@
+package lib
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGuard(t *testing.T) {
+	r := fakeRunner{
+		"git rev-parse --is-inside-work-tree": "true\n",
+		"git status --porcelain":              "",
+		"git rev-parse --abbrev-ref HEAD":     "feature/x\n",
+	}
+	gopi, _ := newTestClassRunner(memFS{}, r)
+	gopi.getenv = func(string) string { return "" }
+	gopi.config.Guards.CleanTree = true
+	if err := gopi.Guard(context.Background(), tRoot); err != nil {
+		t.Fatal(err)
+	}
+	gopi.config.Guards.Branches = []string{"main", "release/*"}
+	if err := gopi.Guard(context.Background(), tRoot); err == nil {
+		t.Fail()
+	}
+	r["git rev-parse --abbrev-ref HEAD"] = "release/1.2\n"
+	if err := gopi.Guard(context.Background(), tRoot); err != nil {
+		t.Fatal(err)
+	}
+	r["git status --porcelain"] = "?? notes.txt\n"
+	if err := gopi.Guard(context.Background(), tRoot); err == nil {
+		t.Fail()
+	}
+}
+
+func TestGuard_outside_git(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.config.Guards.CleanTree = true
+	if err := gopi.Guard(context.Background(), tRoot); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,15 +1,19 @@
 package lib
 
 import (
-	"bufio"
 	"fmt"
-	"log"
+	"gov/pkginfo"
+	"gov/validator"
 	"os"
 	"regexp"
 	"strings"
 )
 
-var isSemver = regexp.MustCompile("^(0|[1-9]\\d*)\\.(0|[1-9]\\d*)\\.(0|[1-9]\\d*)(?:-((?:0|[1-9]\\d*|\\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\\.(?:0|[1-9]\\d*|\\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\\+([0-9a-zA-Z-]+(?:\\.[0-9a-zA-Z-]+)*))?$")
+var pkgTypes = pkginfo.Types
+
+var sentenceEnd = regexp.MustCompile(`[.!?](\s|$)`)
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
 
 func contains(s []string, str string) bool {
 	for _, v := range s {
@@ -21,73 +25,84 @@ func contains(s []string, str string) bool {
 	return false
 }
 
-func prompt(label string, valid func(st string) bool) string {
-	var s string
-	var err error
-	r := bufio.NewReader(os.Stdin)
-	for {
-		_, err = fmt.Fprint(os.Stderr, label)
-		s, err = r.ReadString('\n')
-		if err != nil {
-			log.Fatalln("Unable to read/write from/to console.")
-		}
-		if valid(s) {
-			break
-		}
-	}
-	return strings.TrimSpace(s)
+// validators are the registry of the prompt validators: the built-ins of the
+// validator package and the package info ones.
+func validators() *validator.Class {
+	v := validator.New()
+	// empty or a repository url, normalized by the caller
+	v.Register("repo", validator.Optional(func(st string) error {
+		return pkginfo.ValidRepo(strings.TrimSpace(st))
+	}))
+	// package type, one of pkgTypes
+	v.Register("type", validator.Enum(pkgTypes...))
+	// empty or a valid SPDX license expression
+	v.Register("license", validator.Optional(pkginfo.ValidLicense))
+	// comma separated keywords, all valid
+	v.Register("keywords", func(st string) error {
+		return pkginfo.SetField(&pkginfo.Info{}, "keywords", st)
+	})
+	// comma separated Name <email> (role) entries, all valid
+	v.Register("maintainers", func(st string) error {
+		return pkginfo.SetField(&pkginfo.Info{}, "maintainers", st)
+	})
+	return v
 }
 
-func promptConfirm(label string) bool {
-	var s string
-	var err error
-	r := bufio.NewReader(os.Stdin)
-
-	_, err = fmt.Fprint(os.Stderr, label)
-	s, err = r.ReadString('\n')
+// valid adapts the named validator to the prompts, telling the user what is
+// wrong with a rejected answer.
+func (that *Class) valid(name string) (func(st string) bool, error) {
+	fn, err := that.validators.Get(name)
 	if err != nil {
-		log.Fatalln("Unable to read/write from/to console.")
+		return nil, err
 	}
-	st := strings.TrimSpace(s)
-	return st == "y" || st == "yes"
+	return func(st string) bool {
+		if err := fn(st); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			return false
+		}
+		return true
+	}, nil
 }
 
-func getValidator(name string) func(st string) bool {
-	v := map[string]func(st string) bool{
-		"none": func(st string) bool {
-			return true
-		},
-		// empty - check empty string
-		"empty": func(st string) bool {
-			return st != ""
-		},
-		// check string is a valid semver version
-		"semver": func(st string) bool {
-			return isSemver.MatchString(strings.TrimSpace(st))
-		},
-	}
-	return v[name]
+// archValid parses a comma separated architecture list the same way gopi set
+// does, rejecting it when an entry is not in archList.
+func archValid(st string, archList []string) ([]string, error) {
+	var info pkginfo.Info
+	err := pkginfo.SetField(&info, "arch", st, archList...)
+	return info.Arch, err
 }
 
-func archValid(st string, archList []string) ([]string, error) {
-	if len(strings.TrimSpace(st)) == 0 {
-		panic("Empty arch list")
+// summarize returns the first sentence of the (first paragraph of the) description,
+// shortened on a word boundary to at most max characters.
+func summarize(st string, max int) string {
+	st = strings.TrimSpace(strings.ReplaceAll(st, "\r\n", "\n"))
+	if i := strings.Index(st, "\n\n"); i >= 0 {
+		st = st[:i]
 	}
+	if loc := sentenceEnd.FindStringIndex(st); loc != nil {
+		st = st[:loc[0]+1]
+	}
+	st = strings.Join(strings.Fields(st), " ")
 
-	var lst []string
-
-	al := strings.Split(st, ",")
-	for _, a := range al {
-		tmp := strings.TrimSpace(a)
-		if len(tmp) > 0 && contains(archList, tmp) {
-			lst = append(lst, tmp)
-		} else {
-			fmt.Printf("invalid architecture specification: %s. It wioll be ignored", tmp)
-
-		}
+	r := []rune(st)
+	if max <= 0 || len(r) <= max {
+		return st
+	}
+	if max <= 3 {
+		return string(r[:max])
 	}
-	if len(lst) == 0 {
-		fmt.Println("No build architecture specified. Assuming local platform.")
+	cut := string(r[:max-3])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
 	}
-	return lst, nil
+	return strings.TrimRight(cut, " ,;:.") + "..."
+}
+
+// multiline keeps the markdown of a (possibly multi-paragraph) description intact
+// while neutralizing raw html that could break the README layout.
+func multiline(st string) string {
+	st = strings.ReplaceAll(st, "\r\n", "\n")
+	st = strings.ReplaceAll(st, "<", "&lt;")
+	st = blankLines.ReplaceAllString(st, "\n\n")
+	return strings.Trim(st, "\n")
 }
@@ -1,6 +1,7 @@
 package lib
 
 import (
+	"gov/validator"
 	"strings"
 	"testing"
 )
@@ -37,26 +38,112 @@ func TestArchValid_ok(t *testing.T) {
 	}
 }
 
-func TestArchValid_omit(t *testing.T) {
+func TestArchValid_invalid(t *testing.T) {
 	arh, err := archValid("linux_amd64, darwin_arm64,not-found", tArch)
-	if err != nil {
+	if err == nil || arh != nil || !strings.Contains(err.Error(), "not-found") {
 		t.Fail()
 	}
-	if strings.Join(arh[:], ", ") != "linux_amd64, darwin_arm64" {
+}
+
+func TestArchValid_platforms(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.config.ArchList = nil
+	arh, err := archValid("linux/riscv64, windows/arm64", gopi.archList())
+	if err != nil || len(arh) != 2 {
+		t.Fatal(arh, err)
+	}
+}
+
+func tValid(t *testing.T, gopi *Class, name string) func(st string) bool {
+	t.Helper()
+	valid, err := gopi.valid(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return valid
+}
+
+func TestValid_unknown(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	if valid, err := gopi.valid("nope"); err == nil || valid != nil {
 		t.Fail()
 	}
 }
 
 func TestSemverValidator_ok(t *testing.T) {
-	sv := getValidator("semver")
+	gopi, _ := newTestClass(memFS{})
+	sv := tValid(t, gopi, "semver")
 	if !sv("1.0.0") {
 		t.Fail()
 	}
 }
 
 func TestSemverValidator_not_ok(t *testing.T) {
-	sv := getValidator("semver")
-	if sv("1.0.0.wrong") {
+	gopi, _ := newTestClass(memFS{})
+	sv := tValid(t, gopi, "semver")
+	if sv("1.0.0.wrong") || sv("v1.0.0") || sv("99999999999999999999.0.0") {
+		t.Fail()
+	}
+}
+
+func TestWithValidator(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	WithValidator("tenant", validator.Enum("acme"))(gopi)
+	if tValid(t, gopi, "tenant")("globex") || !tValid(t, gopi, "tenant")("acme") || tValid(t, gopi, "license")("NOT-SPDX") {
+		t.Fail()
+	}
+}
+
+func TestRepoValidator(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	repo := tValid(t, gopi, "repo")
+	for _, ok := range []string{"", "https://github.com/acme/tool", "git@github.com:acme/tool.git\n", "ssh://git@gitlab.com/acme/tool"} {
+		if !repo(ok) {
+			t.Fatal(ok)
+		}
+	}
+	for _, junk := range []string{"tool", "github.com/acme/tool", "https://github.com/", "ftp://acme.io/tool", "https://github.com/acme/my tool"} {
+		if repo(junk) {
+			t.Fatal(junk)
+		}
+	}
+}
+
+func TestSummarize_first_sentence(t *testing.T) {
+	s := summarize("Go package info utility. It does many other things.", 80)
+	if s != "Go package info utility." {
+		t.Fail()
+	}
+}
+
+func TestSummarize_truncate(t *testing.T) {
+	s := summarize("A very long description without any sentence end at all", 20)
+	if s != "A very long..." || len(s) > 20 {
+		t.Fail()
+	}
+}
+
+func TestMultiline_preserves_markdown(t *testing.T) {
+	s := string(multiline("First **bold**\r\n\r\n\r\n\r\n- item <b>\n"))
+	if s != "First **bold**\n\n- item &lt;b>" {
+		t.Fail()
+	}
+}
+
+func TestModuleName(t *testing.T) {
+	if moduleName("github.com/acme/tool/v2") != "tool" || moduleName("gov") != "gov" || moduleName("") != "" {
+		t.Fail()
+	}
+}
+
+func TestModuleRepo(t *testing.T) {
+	if moduleRepo("github.com/acme/tool/v2/cmd/x") != "https://github.com/acme/tool" {
+		t.Fail()
+	}
+	if moduleRepo("gitlab.com/acme/group/tool") != "https://gitlab.com/acme/group/tool" {
+		t.Fail()
+	}
+	if moduleRepo("go.acme.io/tool") != "" {
 		t.Fail()
 	}
 }
@@ -0,0 +1,66 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"gov/pkginfo"
+	"os"
+	"sort"
+	"strings"
+)
+
+// hook is the command of the hook named event, the one of the package info
+// of root winning over the configured one.
+func (that *Class) hook(root string, event string) (string, *pkginfo.Info) {
+	info := &pkginfo.Info{}
+	if content, err := that.fs.ReadFile(that.pkgPath(root)); err == nil {
+		if parsed, err := pkginfo.Parse(content); err == nil {
+			info = parsed
+		}
+	}
+	if cmd, ok := info.Hooks[event]; ok {
+		return cmd, info
+	}
+	return that.config.Hooks[event], info
+}
+
+// hookEnv exports the package info fields as GOPI_PKG_<FIELD> variables, lists
+// comma separated. Variables already set are left alone, they override the
+// package info anyway.
+func (that *Class) hookEnv(info *pkginfo.Info) []string {
+	fields := map[string]string{}
+	for key, field := range envFields {
+		fields[key] = *field(info)
+	}
+	fields["CHANNEL"] = info.Channel
+	fields["LICENSE"] = info.License
+	fields["KEYWORDS"] = strings.Join(info.Keywords, ",")
+	fields["ARCH"] = strings.Join(info.Arch, ",")
+
+	var env []string
+	for key, v := range fields {
+		if _, set := that.lookupEnv(envPrefix + key); !set {
+			env = append(env, envPrefix+key+"="+v)
+		}
+	}
+	sort.Strings(env)
+	return env
+}
+
+// RunHook runs the pre-<command> or post-<command> hook named event with sh in
+// root, when one is declared in the package info or the configuration.
+func (that *Class) RunHook(ctx context.Context, root string, event string) error {
+	cmd, info := that.hook(root, event)
+	if strings.TrimSpace(cmd) == "" {
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "Running the %s hook: %s\n", event, cmd)
+	out, err := that.runner.RunEnv(ctx, root, that.hookEnv(info), "sh", "-c", cmd)
+	if len(out) > 0 {
+		fmt.Print(string(out))
+	}
+	if err != nil {
+		return fmt.Errorf("the %s hook failed: %w", event, err)
+	}
+	return nil
+}
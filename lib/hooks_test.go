@@ -0,0 +1,42 @@
+package lib
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestRunHook(t *testing.T) {
+	fsys := memFS{path.Join(tRoot, "pkg.info"): []byte("name: demo\nversion: 1.0.0\ntenant: acme\narch: [linux_amd64]\nhooks:\n    post-readme: prettier --write README.md\n")}
+	env := "GOPI_PKG_ARCH=linux_amd64 GOPI_PKG_CHANNEL= GOPI_PKG_DESCRIPTION= GOPI_PKG_KEYWORDS= GOPI_PKG_LICENSE= GOPI_PKG_NAME=demo " +
+		"GOPI_PKG_REPO= GOPI_PKG_TENANT=acme GOPI_PKG_TYPE= GOPI_PKG_VERSION=1.0.0 "
+	gopi, _ := newTestClassRunner(fsys, fakeRunner{env + "sh -c prettier --write README.md": ""})
+	gopi.config.Hooks = map[string]string{"post-readme": "ignored", "pre-release": "make test"}
+	if err := gopi.RunHook(context.Background(), tRoot, "post-readme"); err != nil {
+		t.Fatal(err)
+	}
+	// no hook, nothing to run
+	if err := gopi.RunHook(context.Background(), tRoot, "pre-readme"); err != nil {
+		t.Fatal(err)
+	}
+	err := gopi.RunHook(context.Background(), tRoot, "pre-release")
+	if err == nil || !strings.HasPrefix(err.Error(), "the pre-release hook failed") {
+		t.Fatal(err)
+	}
+}
+
+func TestHookEnv_overridden(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.getenv = func(key string) string {
+		if key == "GOPI_PKG_VERSION" {
+			return "2.0.0"
+		}
+		return ""
+	}
+	for _, v := range gopi.hookEnv(&gopi.Info) {
+		if strings.HasPrefix(v, "GOPI_PKG_VERSION=") {
+			t.Fatal(v)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+// iconTypes are the image types accepted for the README icon, by extension.
+var iconTypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".svg":  "image/svg+xml",
+}
+
+// remoteIcon tells an icon url or data URI, which gopi does not check.
+func remoteIcon(icon string) bool {
+	return strings.Contains(icon, "://") || strings.HasPrefix(icon, "data:")
+}
+
+// CheckIcon checks that the icon of the package in root, the configured one
+// when icon is empty, exists and is a supported image, returning its content.
+func (that *Class) CheckIcon(root string, icon string) ([]byte, error) {
+	if icon == "" {
+		icon = that.config.IconPath
+	}
+	mime, ok := iconTypes[strings.ToLower(path.Ext(icon))]
+	if !ok {
+		return nil, fmt.Errorf("the icon %s is not a supported image, expected png, jpg, gif, webp or svg", icon)
+	}
+	raw, err := that.fs.ReadFile(resolveDir(root, icon))
+	if err != nil {
+		return nil, fmt.Errorf("the icon %s does not exist", icon)
+	}
+	if mime == "image/svg+xml" {
+		if !bytes.Contains(raw, []byte("<svg")) {
+			return nil, fmt.Errorf("the icon %s is not an svg image", icon)
+		}
+	} else if sniffed := http.DetectContentType(raw); sniffed != mime {
+		return nil, fmt.Errorf("the icon %s is not a %s image but %s", icon, mime, sniffed)
+	}
+	return raw, nil
+}
+
+// iconSrc is the image source of the icon in the README: its path, or a
+// base64 data URI when it is no bigger than the iconEmbed configuration.
+func (that *Class) iconSrc(root string, icon string) (string, error) {
+	if that.config.IconEmbed <= 0 || icon == "" || remoteIcon(icon) {
+		return icon, nil
+	}
+	raw, err := that.CheckIcon(root, icon)
+	if err != nil {
+		return "", err
+	}
+	if len(raw) > that.config.IconEmbed {
+		return icon, nil
+	}
+	mime := iconTypes[strings.ToLower(path.Ext(icon))]
+	return "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(raw), nil
+}
+
+// IconFile is the location of the configured icon of root.
+func (that *Class) IconFile(root string) string {
+	return resolveDir(root, that.config.IconPath)
+}
+
+// CopyIcon writes the icon bundled with gopi to the configured iconPath of
+// root, unless a file is already there. It returns the path of the icon.
+func (that *Class) CopyIcon(root string) (string, error) {
+	icon := that.config.IconPath
+	if strings.ToLower(path.Ext(icon)) != ".png" || remoteIcon(icon) {
+		return "", fmt.Errorf("the bundled icon is a png file, iconPath %s is not", icon)
+	}
+	target := that.IconFile(root)
+	if _, err := that.fs.Stat(target); err == nil {
+		fmt.Printf("%s already exists, kept\n", target)
+		return target, nil
+	}
+	raw, err := fs.ReadFile(that.config.Templates, path.Join("templates", "icon.png"))
+	if err != nil {
+		return "", fmt.Errorf("the bundled icon is missing: %w", err)
+	}
+	if err = that.writeFile(target, raw, that.fileMode()); err != nil {
+		return "", fmt.Errorf("unable to write %s: %w", target, err)
+	}
+	fmt.Printf("%s written\n", target)
+	return target, nil
+}
+
+// iconValid checks an icon answered at the prompt of root: blank for the
+// configured one, an url or data URI, or a supported image file.
+func (that *Class) iconValid(root string) func(st string) bool {
+	return func(st string) bool {
+		if st = strings.TrimSpace(st); st == "" || remoteIcon(st) {
+			return true
+		}
+		if _, err := that.CheckIcon(root, st); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			return false
+		}
+		return true
+	}
+}
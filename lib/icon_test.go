@@ -0,0 +1,82 @@
+package lib
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestCheckIcon(t *testing.T) {
+	fsys := memFS{
+		path.Join(tRoot, "icon.png"):  []byte("\x89PNG\r\n\x1a\n"),
+		path.Join(tRoot, "fake.png"):  []byte("text"),
+		path.Join(tRoot, "logo.svg"):  []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"/>`),
+		path.Join(tRoot, "icon.tiff"): []byte("II*"),
+	}
+	gopi, _ := newTestClass(fsys)
+	for icon, want := range map[string]string{
+		"":          "",
+		"logo.svg":  "",
+		"fake.png":  "not a image/png image",
+		"icon.tiff": "not a supported image",
+		"none.png":  "does not exist",
+	} {
+		_, err := gopi.CheckIcon(tRoot, icon)
+		if (want == "" && err != nil) || (want != "" && (err == nil || !strings.Contains(err.Error(), want))) {
+			t.Fatalf("%s: %v", icon, err)
+		}
+	}
+}
+
+func TestRenderReadme_icon_embed(t *testing.T) {
+	fsys := memFS{path.Join(tRoot, "icon.png"): []byte("\x89PNG\r\n\x1a\n")}
+	gopi, _ := newTestClass(fsys)
+	gopi.Name, gopi.Version = "demo", "1.0.0"
+	gopi.config.Tpl = "{{ .Icon }}"
+	gopi.config.IconEmbed = 1024
+	raw, err := gopi.RenderReadme(context.Background(), tRoot)
+	if err != nil || string(raw) != "data:image/png;base64,iVBORw0KGgo=" {
+		t.Fatal(string(raw), err)
+	}
+
+	// bigger icons keep their path
+	gopi.config.IconEmbed = 4
+	if raw, err = gopi.RenderReadme(context.Background(), tRoot); err != nil || string(raw) != "icon.png" {
+		t.Fatal(string(raw), err)
+	}
+}
+
+func TestCopyIcon(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	target, err := gopi.CopyIcon(tRoot)
+	if err != nil || target != path.Join(tRoot, "icon.png") {
+		t.Fatal(target, err)
+	}
+	if _, err = gopi.CheckIcon(tRoot, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// an existing icon is kept
+	fsys[target] = []byte("custom")
+	if _, err = gopi.CopyIcon(tRoot); err != nil || string(fsys[target]) != "custom" {
+		t.Fatal(err)
+	}
+	gopi.config.IconPath = "logo.svg"
+	if _, err = gopi.CopyIcon(tRoot); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestCreateReadme_icon_prompt(t *testing.T) {
+	fsys := memFS{path.Join(tRoot, "logo.png"): []byte("\x89PNG\r\n\x1a\n")}
+	gopi, p := newTestClass(fsys, "missing.png", "logo.png")
+	gopi.Name, gopi.Version = "demo", "1.0.0"
+	if err := gopi.CreateReadme(context.Background(), tRoot, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.asked) != 2 || !strings.Contains(string(fsys[path.Join(tRoot, "README.md")]), "logo.png") {
+		t.Fatal(p.asked)
+	}
+}
@@ -0,0 +1,116 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"gov/manifest"
+	"gov/pkginfo"
+	"gov/validator"
+	"os"
+	"strings"
+)
+
+// ImportPkg writes the package info of root from the manifest of another
+// ecosystem at pth, a package.json, pyproject.toml or Cargo.toml, prompting
+// only for what it lacks: the tenant, the architectures and an invalid name
+// or version. An existing package info is overwritten once confirmed, or
+// right away when force is set.
+func (that *Class) ImportPkg(ctx context.Context, root string, pth string, force bool) error {
+	format, err := manifest.FormatOf(pth)
+	if err != nil {
+		return err
+	}
+	raw, err := that.fs.ReadFile(pth)
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %w", pth, err)
+	}
+	doc, err := manifest.Parse(raw, format)
+	if err != nil {
+		return err
+	}
+	if !force && that.checkPkgExists(root) {
+		ovr, err := that.confirm(fmt.Sprintf("A %s file already exists in the %s directory. Overwrite? ( y/yes to confirm): ",
+			that.config.PkgInfoFile, root))
+		if err != nil || !ovr {
+			return err
+		}
+	}
+
+	that.Info = pkginfo.Info{Name: doc.Name, Version: doc.Version, Description: doc.Description, Type: that.guessType(root)}
+	var imported []string
+	for _, f := range [][2]string{{"name", doc.Name}, {"version", doc.Version}, {"description", doc.Description}} {
+		if f[1] != "" {
+			imported = append(imported, f[0])
+		}
+	}
+	if that.Repo = normalizeRepo(doc.Repo); that.Repo != "" {
+		if pkginfo.ValidRepo(that.Repo) == nil {
+			imported = append(imported, "repo")
+		} else {
+			fmt.Fprintf(os.Stderr, "Skipping the repository %q, it is not a repository url.\n", doc.Repo)
+			that.Repo = ""
+		}
+	}
+	if that.Repo == "" {
+		if that.Repo = normalizeRepo(that.gitRemote(ctx, root)); that.Repo == "" {
+			that.Repo = moduleRepo(that.goModule(root))
+		}
+	}
+	if doc.License != "" {
+		if pkginfo.ValidLicense(doc.License) == nil {
+			that.License = doc.License
+			imported = append(imported, "license")
+		} else {
+			fmt.Fprintf(os.Stderr, "Skipping the license %q, it is not an SPDX expression.\n", doc.License)
+		}
+	}
+	for _, a := range doc.Authors {
+		if validator.Email(a.Email) != nil {
+			fmt.Fprintf(os.Stderr, "Skipping the author %s, a maintainer needs an email address.\n", a.Name)
+			continue
+		}
+		that.Maintainers = append(that.Maintainers, pkginfo.Maintainer{Name: a.Name, Email: a.Email})
+	}
+	if len(that.Maintainers) > 0 {
+		imported = append(imported, "maintainers")
+	}
+	fmt.Printf("Imported %s from %s.\n", strings.Join(imported, ", "), pth)
+
+	// only the gaps are asked, the tenant always is
+	that.loadTenants(ctx)
+	ask := func(dst *string, label string, name string) {
+		if err != nil {
+			return
+		}
+		fn, gErr := that.validators.Get(name)
+		if gErr != nil {
+			err = gErr
+			return
+		}
+		if name == "tenant" || fn(*dst) != nil {
+			var valid func(st string) bool
+			if valid, err = that.valid(name); err == nil {
+				err = ctx.Err()
+			}
+			if err == nil {
+				*dst, err = that.prompter.Prompt(label, *dst, valid)
+			}
+		}
+	}
+	ask(&that.Name, "Project name(required): ", "required")
+	ask(&that.Version, "Project version (is required & has to semver compatible): ", "semver")
+	ask(&that.Tenant, "Tenant to which the project belongs to (required): ", "tenant")
+	if err != nil {
+		return err
+	}
+	archLabel := fmt.Sprintf("Architectures on which the project should be built (none for local only, %s): ", that.localArch())
+	picked, err := that.prompter.Select(archLabel, that.archList(), nil)
+	if err != nil {
+		return err
+	}
+	if that.Arch, err = archValid(strings.Join(picked, ","), that.archList()); err != nil {
+		return err
+	}
+	that.defaultArch()
+	return that.CreatePkg(root)
+}
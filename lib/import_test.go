@@ -0,0 +1,60 @@
+package lib
+
+import (
+	"context"
+	"gov/validator"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestImportPkg(t *testing.T) {
+	fsys := memFS{path.Join(tRoot, "package.json"): []byte(`{"name": "demo", "version": "1.2", "description": "A demo.", "license": "Nonsense",
+		"repository": "github:acme/demo", "author": "Jane Doe <jane@acme.io>", "contributors": ["John Roe"]}`)}
+	gopi, p := newTestClass(fsys, "1.2.0", "acme", "linux_amd64")
+	if err := gopi.ImportPkg(context.Background(), tRoot, path.Join(tRoot, "package.json"), false); err != nil {
+		t.Fatal(err)
+	}
+	// the name is imported, the invalid version asked again
+	if len(p.asked) != 3 || !strings.HasPrefix(p.asked[0], "Project version") || !strings.HasPrefix(p.asked[1], "Tenant") {
+		t.Fatal(p.asked)
+	}
+	got := string(fsys[path.Join(tRoot, "pkg.info")])
+	for _, want := range []string{"version: 1.2.0\n", "tenant: acme\n", "repo: https://github.com/acme/demo\n", "    - name: Jane Doe\n      email: jane@acme.io\n", "    - linux_amd64\n"} {
+		if !strings.Contains(got, want) {
+			t.Fatal(got)
+		}
+	}
+	if strings.Contains(got, "license") || strings.Contains(got, "John") {
+		t.Fatal(got)
+	}
+}
+
+func TestImportPkg_errors(t *testing.T) {
+	fsys := memFS{path.Join(tRoot, "Cargo.toml"): []byte("[package\n"), path.Join(tRoot, "pkg.info"): []byte("name: old\n")}
+	gopi, _ := newTestClass(fsys, "n")
+	if err := gopi.ImportPkg(context.Background(), tRoot, path.Join(tRoot, "setup.py"), false); err == nil {
+		t.Fail()
+	}
+	if err := gopi.ImportPkg(context.Background(), tRoot, path.Join(tRoot, "pyproject.toml"), false); err == nil {
+		t.Fail()
+	}
+	if err := gopi.ImportPkg(context.Background(), tRoot, path.Join(tRoot, "Cargo.toml"), false); err == nil || !strings.Contains(err.Error(), "unterminated table header") {
+		t.Fatal(err)
+	}
+
+	fsys[path.Join(tRoot, "Cargo.toml")] = []byte("[package]\nname = \"demo\"\nversion = \"1.0.0\"\n")
+	// the overwrite is declined
+	if err := gopi.ImportPkg(context.Background(), tRoot, path.Join(tRoot, "Cargo.toml"), false); err != nil || string(fsys[path.Join(tRoot, "pkg.info")]) != "name: old\n" {
+		t.Fatal(err)
+	}
+}
+
+func TestImportPkg_unknown_validator(t *testing.T) {
+	fsys := memFS{path.Join(tRoot, "package.json"): []byte(`{"name": "demo", "version": "1.2.0"}`)}
+	gopi, _ := newTestClass(fsys, "acme")
+	gopi.validators = validator.New()
+	if err := gopi.ImportPkg(context.Background(), tRoot, path.Join(tRoot, "package.json"), false); err == nil || !strings.Contains(err.Error(), "tenant") {
+		t.Fatal(err)
+	}
+}
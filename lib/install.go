@@ -0,0 +1,55 @@
+package lib
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// Download is the release artifact of one build architecture.
+type Download struct {
+	Target string
+	OS     string
+	Arch   string
+	URL    string
+}
+
+// binaryName is the configured binary name, defaulting to the package name.
+func (that *Class) binaryName() string {
+	if that.config.BinaryName != "" {
+		return that.config.BinaryName
+	}
+	return that.Name
+}
+
+func (that *Class) installPath() string {
+	if that.config.InstallDir == "" {
+		return ""
+	}
+	return path.Join(that.config.InstallDir, that.binaryName())
+}
+
+// downloads renders the configured download url for every architecture of the package.
+func (that *Class) downloads() ([]Download, error) {
+	if that.config.DownloadURL == "" || that.Repo == "" {
+		return nil, nil
+	}
+	tpl, err := template.New("downloadURL").Parse(that.config.DownloadURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid downloadURL template: %w", err)
+	}
+	var res []Download
+	for _, target := range that.Platforms() {
+		data := that.artifactData(target)
+		if data["Artifact"], err = that.artifactName(target); err != nil {
+			return nil, err
+		}
+		var sb strings.Builder
+		if err = tpl.Execute(&sb, data); err != nil {
+			return nil, fmt.Errorf("while processing the downloadURL template: %w", err)
+		}
+		res = append(res, Download{Target: target, OS: data["OS"], Arch: data["Arch"], URL: sb.String()})
+	}
+	return res, nil
+}
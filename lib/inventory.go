@@ -0,0 +1,62 @@
+package lib
+
+import (
+	"context"
+	"gov/inventory"
+	"gov/pkginfo"
+	"path/filepath"
+	"time"
+)
+
+// Inventory opens the configured inventory catalog, the one of the user cache
+// directory when inventoryFile is empty.
+func (that *Class) Inventory() (*inventory.Class, error) {
+	pth := that.config.InventoryFile
+	if pth == "" {
+		pth = inventory.DefaultPath()
+	}
+	return inventory.Open(resolveDir("", pth))
+}
+
+// RefreshInventory records the packages under root in inv, see List. It
+// returns the changes since the last scan and the packages whose package info
+// could not be read, which are left out.
+func (that *Class) RefreshInventory(inv *inventory.Class, root string) ([]inventory.Change, []Listing, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	found, err := that.List(abs)
+	if err != nil {
+		return nil, nil, err
+	}
+	var entries []inventory.Entry
+	var broken []Listing
+	for _, l := range found {
+		if l.Error != "" {
+			broken = append(broken, l)
+			continue
+		}
+		entries = append(entries, inventory.Entry{Name: l.Name, Version: l.Version, Tenant: l.Tenant, Path: filepath.Join(abs, l.Path)})
+	}
+	return inv.Refresh(abs, entries, time.Now()), broken, nil
+}
+
+// PackageInfo reads the package info of the inventory entry e, from disk.
+func (that *Class) PackageInfo(e inventory.Entry) (*pkginfo.Info, error) {
+	content, err := that.fs.ReadFile(that.pkgPath(e.Path))
+	if err != nil {
+		return nil, err
+	}
+	return pkginfo.Parse(content)
+}
+
+// PackageReadme renders the README of the inventory entry e, with the
+// configured icon.
+func (that *Class) PackageReadme(ctx context.Context, e inventory.Entry) ([]byte, error) {
+	pkg := New(&that.config, WithFS(that.fs), WithRunner(that.runner), WithEnv(that.getenv))
+	if err := pkg.GetPackage(e.Path); err != nil {
+		return nil, err
+	}
+	return pkg.RenderReadme(ctx, e.Path)
+}
@@ -0,0 +1,33 @@
+package lib
+
+import (
+	"gov/inventory"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRefreshInventory(t *testing.T) {
+	root := t.TempDir()
+	for p, content := range map[string]string{
+		"api/pkg.info":    "name: api\nversion: 1.0.0\ntenant: acme\n",
+		"broken/pkg.info": "name: [x\n",
+	} {
+		_ = os.MkdirAll(filepath.Join(root, filepath.Dir(p)), 0755)
+		_ = os.WriteFile(filepath.Join(root, p), []byte(content), 0644)
+	}
+	gopi, _ := newTestClass(memFS{})
+	gopi.fs = osFS{}
+	gopi.config.InventoryFile = filepath.Join(root, "inventory.db")
+	inv, err := gopi.Inventory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, broken, err := gopi.RefreshInventory(inv, root)
+	if err != nil || len(changes) != 1 || len(broken) != 1 || broken[0].Path != "broken" {
+		t.Fatal(changes, broken, err)
+	}
+	if found, _ := inv.Query(inventory.Query{Tenant: "acme"}); len(found) != 1 || found[0].Path != filepath.Join(root, "api") {
+		t.Fatal(found)
+	}
+}
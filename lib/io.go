@@ -0,0 +1,223 @@
+package lib
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"gov/txn"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type FS interface {
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte, perm os.FileMode) error
+	Stat(name string) (os.FileInfo, error)
+}
+
+type Prompter interface {
+	// Prompt asks until valid accepts the answer. An empty answer selects def
+	// when one is given.
+	Prompt(label string, def string, valid func(st string) bool) (string, error)
+	Confirm(label string) (bool, error)
+	// Select lets the user pick any number of options, selected being picked
+	// to begin with.
+	Select(label string, options []string, selected []string) ([]string, error)
+	Edit(text string) (string, error)
+}
+
+type Runner interface {
+	Run(ctx context.Context, dir string, name string, args ...string) ([]byte, error)
+	// RunEnv runs name with env (KEY=value) added to the environment.
+	RunEnv(ctx context.Context, dir string, env []string, name string, args ...string) ([]byte, error)
+}
+
+// download GETs url, reading at most 1MiB of the body.
+func download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, res.Status)
+	}
+	return io.ReadAll(io.LimitReader(res.Body, 1<<20))
+}
+
+type osFS struct{}
+
+func (osFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+// WriteFile replaces name atomically, an interrupted run never leaves it truncated.
+func (osFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return txn.WriteFile(name, data, perm)
+}
+
+// WritePrivate replaces name, never a file it links to, readable by the user
+// only, creating its directory for the user only too.
+func (osFS) WritePrivate(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0700); err != nil {
+		return err
+	}
+	return txn.ReplaceFile(name, data, 0600)
+}
+
+func (osFS) MkdirAll(name string, perm os.FileMode) error {
+	return os.MkdirAll(name, perm)
+}
+
+func (osFS) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (osFS) Remove(name string) error {
+	return os.Remove(name)
+}
+
+// txFS reads through the staged changes of a transaction and stages writes.
+type txFS struct {
+	FS
+	tx *txn.Class
+}
+
+func (that txFS) ReadFile(name string) ([]byte, error) {
+	if data, ok := that.tx.Staged(name); ok {
+		return data, nil
+	}
+	return that.FS.ReadFile(name)
+}
+
+func (that txFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return that.tx.Stage(name, data, perm)
+}
+
+// IsTerminal reports whether f is an interactive terminal, not a pipe, a file
+// or the null device.
+func IsTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	null, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(fi, null)
+}
+
+type consolePrompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newConsolePrompter() *consolePrompter {
+	return &consolePrompter{
+		in:  bufio.NewReader(os.Stdin),
+		out: os.Stderr,
+	}
+}
+
+func (that *consolePrompter) read(label string) (string, error) {
+	_, err := fmt.Fprint(that.out, label)
+	if err != nil {
+		return "", fmt.Errorf("unable to write to console: %w", err)
+	}
+	s, err := that.in.ReadString('\n')
+	if err == io.EOF && s != "" {
+		err = nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to read from console: %w", err)
+	}
+	return s, nil
+}
+
+func (that *consolePrompter) Prompt(label string, def string, valid func(st string) bool) (string, error) {
+	if def != "" {
+		label = fmt.Sprintf("%s[%s] ", label, def)
+	}
+	for {
+		s, err := that.read(label)
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(s) == "" && def != "" {
+			s = def
+		}
+		if valid(s) {
+			return strings.TrimSpace(s), nil
+		}
+	}
+}
+
+func (that *consolePrompter) Confirm(label string) (bool, error) {
+	s, err := that.read(label)
+	if err != nil {
+		return false, err
+	}
+	st := strings.TrimSpace(s)
+	return st == "y" || st == "yes", nil
+}
+
+type execRunner struct{}
+
+func (that execRunner) Run(ctx context.Context, dir string, name string, args ...string) ([]byte, error) {
+	return that.RunEnv(ctx, dir, nil, name, args...)
+}
+
+func (execRunner) RunEnv(ctx context.Context, dir string, env []string, name string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil && stderr.Len() > 0 {
+		return out, fmt.Errorf("%s: %s", err.Error(), strings.TrimSpace(stderr.String()))
+	}
+	return out, err
+}
+
+// Edit opens text in $EDITOR (vi when unset) and returns the saved result.
+func (that *consolePrompter) Edit(text string) (string, error) {
+	f, err := os.CreateTemp("", "gopi-edit-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+	_, err = f.WriteString(text)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	cmd := exec.Command(editor, f.Name())
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err = cmd.Run(); err != nil {
+		return "", err
+	}
+	raw, err := os.ReadFile(f.Name())
+	return string(raw), err
+}
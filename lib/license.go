@@ -0,0 +1,68 @@
+package lib
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"gov/pkginfo"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// CreateLicense writes the LICENSE file for the SPDX identifier of the package.
+// The common short licenses are embedded with the year and the tenant as
+// copyright holder filled in, the others are fetched from licenseTextURL.
+func (that *Class) CreateLicense(ctx context.Context, root string, force bool) error {
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	if that.License == "" {
+		return errors.New("the package has no license, set one with: gopi set license <SPDX id>")
+	}
+	id := pkginfo.CanonicalLicense(that.License)
+	if id == "" {
+		return fmt.Errorf("a LICENSE can only be generated for a single SPDX license identifier, not %q", that.License)
+	}
+	pth := path.Join(root, "LICENSE")
+	if _, err := that.fs.Stat(pth); err == nil && !force {
+		return fmt.Errorf("a LICENSE already exists in %s, use -force to overwrite it", root)
+	}
+
+	text, err := that.licenseText(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = that.writeFile(pth, text, that.fileMode()); err != nil {
+		return fmt.Errorf("unable to write the LICENSE file in %s: %w", root, err)
+	}
+	fmt.Printf("%s license written to %s\n", id, pth)
+	return nil
+}
+
+func (that *Class) licenseText(ctx context.Context, id string) ([]byte, error) {
+	raw, err := fs.ReadFile(that.config.Templates, path.Join("templates", "licenses", id+".tpl"))
+	if err == nil {
+		tpl, err := template.New(id).Parse(string(raw))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the %s license template: %w", id, err)
+		}
+		var buf bytes.Buffer
+		err = tpl.Execute(&buf, map[string]interface{}{"Year": time.Now().Year(), "Holder": that.Tenant})
+		return buf.Bytes(), err
+	}
+
+	if that.config.LicenseTextURL == "" {
+		return nil, fmt.Errorf("no embedded text for %s and no licenseTextURL configured", id)
+	}
+	url := strings.ReplaceAll(that.config.LicenseTextURL, "{id}", id)
+	text, err := download(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download the %s license text: %w", id, err)
+	}
+	return text, nil
+}
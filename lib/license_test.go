@@ -0,0 +1,34 @@
+package lib
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateLicense_embedded(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	gopi.Tenant, gopi.License = "Acme Inc", "mit"
+	if err := gopi.CreateLicense(context.Background(), tRoot, false); err != nil {
+		t.Fatal(err)
+	}
+	got := string(fsys[path.Join(tRoot, "LICENSE")])
+	if !strings.HasPrefix(got, "MIT License\n\nCopyright (c) "+strconv.Itoa(time.Now().Year())+" Acme Inc\n") {
+		t.Fatal(got)
+	}
+	if err := gopi.CreateLicense(context.Background(), tRoot, false); err == nil {
+		t.Fail()
+	}
+}
+
+func TestCreateLicense_expression(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.License = "MIT OR Apache-2.0"
+	if err := gopi.CreateLicense(context.Background(), tRoot, false); err == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,115 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// LintRule is a consistency check between the package info and the files
+// around it, Name being its stable id.
+type LintRule struct {
+	Name    string
+	Summary string
+	check   func(that *Class, ctx context.Context, root string) []string
+}
+
+// LintFinding is a problem found by a lint rule.
+type LintFinding struct {
+	Rule    string
+	Message string
+}
+
+func (that LintFinding) String() string {
+	return fmt.Sprintf("%s [%s]", that.Message, that.Rule)
+}
+
+// LintRules are the lint rules, in the order they run.
+var LintRules = []LintRule{
+	{"readme-version", "the README mentions the version of pkg.info", lintReadmeVersion},
+	{"repo-remote", "the repo of pkg.info is the origin git remote", lintRepoRemote},
+	{"arch-empty", "pkg.info lists the architectures to build", lintArchEmpty},
+	{"description-length", "the description is at most lint.maxDescription characters", lintDescriptionLength},
+	{"icon-missing", "the README icon file exists and is a supported image", lintIconMissing},
+}
+
+func lintReadmeVersion(that *Class, _ context.Context, root string) []string {
+	raw, err := that.fs.ReadFile(path.Join(root, that.config.ReadmeFile))
+	if err != nil || that.Version == "" || strings.Contains(string(raw), that.Version) {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s does not mention the version %s, run gopi readme", that.config.ReadmeFile, that.Version)}
+}
+
+func lintRepoRemote(that *Class, ctx context.Context, root string) []string {
+	remote := normalizeRepo(that.gitRemote(ctx, root))
+	if remote == "" || that.Repo == "" || strings.EqualFold(remote, normalizeRepo(that.Repo)) {
+		return nil
+	}
+	return []string{fmt.Sprintf("the repo %s is not the origin remote %s, run gopi sync", that.Repo, remote)}
+}
+
+func lintArchEmpty(that *Class, _ context.Context, _ string) []string {
+	if len(that.Platforms()) > 0 {
+		return nil
+	}
+	return []string{"no architecture is listed, the package builds for the local platform only"}
+}
+
+func lintDescriptionLength(that *Class, _ context.Context, _ string) []string {
+	max := that.config.Lint.MaxDescription
+	if n := len([]rune(that.Description)); max > 0 && n > max {
+		return []string{fmt.Sprintf("the description is %d characters long, more than %d", n, max)}
+	}
+	return nil
+}
+
+func lintIconMissing(that *Class, _ context.Context, root string) []string {
+	icon := that.config.IconPath
+	if icon == "" || remoteIcon(icon) {
+		return nil
+	}
+	if _, err := that.CheckIcon(root, icon); err != nil {
+		return []string{err.Error()}
+	}
+	return nil
+}
+
+// Lint runs the lint rules on the package in root but the ones disabled in
+// the lint configuration, enable turning some of them on again and disable
+// turning more off.
+func (that *Class) Lint(ctx context.Context, root string, enable []string, disable []string) ([]LintFinding, error) {
+	off := map[string]bool{}
+	for _, step := range []struct {
+		names []string
+		off   bool
+	}{{that.config.Lint.Disable, true}, {enable, false}, {disable, true}} {
+		for _, name := range step.names {
+			if lintRule(name) == nil {
+				return nil, fmt.Errorf("unknown lint rule %q, see gopi lint -list", name)
+			}
+			off[name] = step.off
+		}
+	}
+
+	var res []LintFinding
+	for _, rule := range LintRules {
+		if off[rule.Name] {
+			continue
+		}
+		for _, msg := range rule.check(that, ctx, root) {
+			res = append(res, LintFinding{Rule: rule.Name, Message: msg})
+		}
+	}
+	return res, nil
+}
+
+func lintRule(name string) *LintRule {
+	for i := range LintRules {
+		if LintRules[i].Name == name {
+			return &LintRules[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package lib
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+)
+
+func lintNames(findings []LintFinding) string {
+	var res []string
+	for _, f := range findings {
+		res = append(res, f.Rule)
+	}
+	return strings.Join(res, " ")
+}
+
+func TestLint(t *testing.T) {
+	fsys := memFS{path.Join(tRoot, "README.md"): []byte("# DEMO 0.9.0\n")}
+	gopi, _ := newTestClassRunner(fsys, fakeRunner{"git remote get-url origin": "git@github.com:acme/other.git\n"})
+	gopi.config.Lint.MaxDescription = 10
+	gopi.Version, gopi.Repo, gopi.Description = "1.0.0", "https://github.com/acme/demo", "A demo that is too long."
+
+	findings, err := gopi.Lint(context.Background(), tRoot, nil, nil)
+	if err != nil || lintNames(findings) != "readme-version repo-remote arch-empty description-length icon-missing" {
+		t.Fatal(findings, err)
+	}
+	if findings[1].String() != "the repo https://github.com/acme/demo is not the origin remote https://github.com/acme/other, run gopi sync [repo-remote]" {
+		t.Fatal(findings[1].String())
+	}
+
+	fsys[path.Join(tRoot, "README.md")] = []byte("# DEMO 1.0.0\n")
+	fsys[path.Join(tRoot, "icon.png")] = []byte("\x89PNG\r\n\x1a\n")
+	gopi.Arch = []string{"linux_amd64"}
+	gopi.config.Lint.Disable = []string{"repo-remote", "description-length"}
+	if findings, err = gopi.Lint(context.Background(), tRoot, []string{"description-length"}, nil); err != nil || lintNames(findings) != "description-length" {
+		t.Fatal(findings, err)
+	}
+	if findings, err = gopi.Lint(context.Background(), tRoot, nil, []string{"arch-empty"}); err != nil || len(findings) != 0 {
+		t.Fatal(findings, err)
+	}
+	if _, err = gopi.Lint(context.Background(), tRoot, nil, []string{"no-such-rule"}); err == nil {
+		t.Fail()
+	}
+}
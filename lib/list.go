@@ -0,0 +1,45 @@
+package lib
+
+import (
+	"gov/pkginfo"
+	"gov/workspace"
+	"path/filepath"
+)
+
+// Listing is a package found by List.
+type Listing struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Tenant  string `json:"tenant"`
+	Path    string `json:"path"`
+	// Error tells why the package info could not be read.
+	Error string `json:"error,omitempty"`
+}
+
+// List finds the package info files under root, see workspace.Discover, and
+// reads their name, version and tenant. Path is the package directory
+// relative to root, an unreadable package info being listed with its error.
+func (that *Class) List(root string) ([]Listing, error) {
+	dirs, err := workspace.Discover(root, that.config.PkgInfoFile)
+	if err != nil {
+		return nil, err
+	}
+	abs, _ := filepath.Abs(root)
+	res := make([]Listing, 0, len(dirs))
+	for _, dir := range dirs {
+		rel, _ := filepath.Rel(abs, dir)
+		l := Listing{Path: filepath.ToSlash(rel)}
+		content, err := that.fs.ReadFile(that.pkgPath(dir))
+		var info *pkginfo.Info
+		if err == nil {
+			info, err = pkginfo.Parse(content)
+		}
+		if err != nil {
+			l.Error = err.Error()
+		} else {
+			l.Name, l.Version, l.Tenant = info.Name, info.Version, info.Tenant
+		}
+		res = append(res, l)
+	}
+	return res, nil
+}
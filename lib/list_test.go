@@ -0,0 +1,34 @@
+package lib
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestList(t *testing.T) {
+	root := t.TempDir()
+	for p, content := range map[string]string{
+		"pkg.info":              "name: platform\nversion: 1.0.0\ntenant: acme\n",
+		"services/api/pkg.info": "name: api\nversion: 0.3.0\ntenant: globex\n",
+		"libs/util/pkg.info":    "name: [broken\n",
+	} {
+		_ = os.MkdirAll(filepath.Join(root, filepath.Dir(p)), 0755)
+		_ = os.WriteFile(filepath.Join(root, p), []byte(content), 0644)
+	}
+	gopi, _ := newTestClass(memFS{})
+	gopi.fs = osFS{}
+	found, err := gopi.List(root)
+	if err != nil || len(found) != 3 {
+		t.Fatal(found, err)
+	}
+	if found[0] != (Listing{Name: "platform", Version: "1.0.0", Tenant: "acme", Path: "."}) {
+		t.Fatal(found[0])
+	}
+	if found[1].Path != "libs/util" || found[1].Error == "" {
+		t.Fatal(found[1])
+	}
+	if found[2] != (Listing{Name: "api", Version: "0.3.0", Tenant: "globex", Path: "services/api"}) {
+		t.Fatal(found[2])
+	}
+}
@@ -1,9 +1,76 @@
 package lib
 
-import "gov/config"
+import (
+	"gov/config"
+	"gov/txn"
+	"gov/validator"
+	"os"
+)
 
-func New(cfg *config.Class) *Class {
-	return &Class{
-		config: *cfg,
+type Option func(*Class)
+
+func WithFS(fs FS) Option {
+	return func(that *Class) {
+		that.fs = fs
+	}
+}
+
+func WithPrompter(p Prompter) Option {
+	return func(that *Class) {
+		that.prompter = p
+	}
+}
+
+func WithRunner(r Runner) Option {
+	return func(that *Class) {
+		that.runner = r
+	}
+}
+
+func WithEnv(getenv func(string) string) Option {
+	return func(that *Class) {
+		that.getenv = getenv
+	}
+}
+
+// WithTransaction stages every write in tx instead of writing it, the caller
+// commits tx once all packages of a workspace succeeded.
+func WithTransaction(tx *txn.Class) Option {
+	return func(that *Class) {
+		that.fs = txFS{FS: that.fs, tx: tx}
+	}
+}
+
+// WithReleased gives the module versions released earlier in a workspace
+// release, by module path: Release moves the dependencies on them to those
+// versions and, as Go expects of nested modules, prefixes the tags with the
+// package directory in the repository.
+func WithReleased(versions map[string]string) Option {
+	return func(that *Class) {
+		that.released = versions
+	}
+}
+
+// WithValidator registers a named validator for the prompts, replacing the
+// built-in of the same name.
+func WithValidator(name string, fn validator.Func) Option {
+	return func(that *Class) {
+		that.validators.Register(name, fn)
+	}
+}
+
+func New(cfg *config.Class, opts ...Option) *Class {
+	this := &Class{
+		config:     *cfg,
+		fs:         osFS{},
+		prompter:   newConsolePrompter(),
+		runner:     execRunner{},
+		getenv:     os.Getenv,
+		validators: validators(),
+	}
+	this.validators.Register("tenant", this.validTenant)
+	for _, opt := range opts {
+		opt(this)
 	}
+	return this
 }
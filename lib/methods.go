@@ -1,120 +1,357 @@
 package lib
 
 import (
+	"bytes"
+	"context"
 	"fmt"
-	"gopkg.in/yaml.v3"
-	"html/template"
-	"log"
+	"gov/pkginfo"
+	"gov/platform"
 	"os"
 	"path"
 	"strings"
+	"text/template"
 )
 
-func (that *Class) PromptPkg(root string) {
+// PromptPkg asks for the package info of root and writes it, confirming the
+// overwrite of an existing file unless force is set.
+func (that *Class) PromptPkg(ctx context.Context, root string, force bool) error {
 
-	var err error
+	// the answers are saved as they come, an interrupted init resumes
+	state, err := that.resumeState(root)
+	if err != nil {
+		return err
+	}
+	ask := func(key string, dst *string, label string, def string, validator string) {
+		if err == nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			return
+		}
+		valid, vErr := that.valid(validator)
+		if vErr != nil {
+			err = vErr
+			return
+		}
+		if saved, ok := state[key]; ok && valid(saved) {
+			*dst = saved
+			return
+		}
+		if *dst, err = that.prompter.Prompt(label, def, valid); err == nil {
+			state[key] = *dst
+			that.saveState(root, state)
+		}
+	}
 
+	// an existing package info is edited: its values are the defaults
+	if err = that.loadExisting(root); err != nil {
+		return err
+	}
+	current := func(value string, def string) string {
+		if value != "" {
+			return value
+		}
+		return def
+	}
+	var maintainers []string
+	for _, m := range that.Maintainers {
+		maintainers = append(maintainers, m.String())
+	}
+
+	modPath := that.goModule(root)
+	repo := normalizeRepo(that.gitRemote(ctx, root))
+	if repo == "" {
+		repo = moduleRepo(modPath)
+	}
+
+	keywords, people := strings.Join(that.Keywords, ", "), strings.Join(maintainers, ", ")
+	that.loadTenants(ctx)
 	fmt.Println("GO pkg.info initializer:")
-	that.Name = prompt("Project name(required):", getValidator("empty"))
-	that.Version = prompt("Project version (is required & has to semver compatible): ", getValidator("semver"))
-	that.Description = prompt("Description of the project (Enter for blank): ", getValidator("none"))
-	that.Tenant = prompt("Tenant to which the project belongs to (required): ", getValidator("empty"))
-	that.Repo = prompt("Repository url of the project (Enter for blank): ", getValidator("none"))
-	res := prompt("Architectures list on which the project should be build (Enter for local only): ", getValidator("none"))
-	that.Arch, err = archValid(res, that.config.ArchList)
+	ask("name", &that.Name, "Project name(required): ", current(that.Name, moduleName(modPath)), "required")
+	ask("version", &that.Version, "Project version (is required & has to semver compatible): ", that.Version, "semver")
+	ask("description", &that.Description, "Description of the project (Enter for blank): ", that.Description, "none")
+	ask("keywords", &keywords, "Keywords, comma separated lowercase words (Enter for none): ", keywords, "keywords")
+	ask("tenant", &that.Tenant, "Tenant to which the project belongs to (required): ", that.Tenant, "tenant")
+	ask("repo", &that.Repo, "Repository url of the project (Enter for blank): ", current(that.Repo, repo), "repo")
+	ask("type", &that.Type, "Package type - cli, library or service: ", current(that.Type, that.guessType(root)), "type")
+	ask("license", &that.License, "License, an SPDX identifier like MIT or Apache-2.0 (Enter for none): ", that.License, "license")
+	ask("maintainers", &people, "Maintainers, comma separated Name <email> (role) (Enter for none): ", people, "maintainers")
+	if err != nil {
+		return err
+	}
+	archLabel := fmt.Sprintf("Architectures on which the project should be built (none for local only, %s): ", that.localArch())
+	arch, ok := state["arch"]
+	if !ok {
+		picked, err := that.prompter.Select(archLabel, that.archList(), that.archOptions())
+		if err != nil {
+			return err
+		}
+		arch = strings.Join(picked, ",")
+	}
+	if that.Arch, err = archValid(arch, that.archList()); err != nil {
+		return err
+	}
+	state["arch"] = arch
+	that.saveState(root, state)
+	that.Repo = normalizeRepo(that.Repo)
+	that.Keywords = pkginfo.SplitList(keywords)
+	that.Maintainers, err = pkginfo.ParseMaintainers(people)
 	if err != nil {
-		log.Fatal(err.Error())
+		return err
 	}
-	existingMessage := fmt.Sprintf("A %s file already exists in the %s directory. Overwrite? ( y/yes to confirm): ",
-		that.config.PkgInfoFile, root)
-	ovr := promptConfirm(existingMessage)
-	if ovr {
-		that.CreatePkg(root)
+	that.defaultArch()
+	if !force && that.checkPkgExists(root) {
+		existingMessage := fmt.Sprintf("A %s file already exists in the %s directory. Overwrite? ( y/yes to confirm): ",
+			that.config.PkgInfoFile, root)
+		ovr, err := that.confirm(existingMessage)
+		if err != nil {
+			return err
+		}
+		if !ovr {
+			that.clearState(root)
+			return nil
+		}
+	}
+	if err = that.CreatePkg(root); err != nil {
+		return err
+	}
+	that.clearState(root)
+	return nil
+}
+
+// archOptions are the architectures of the package as named in the arch list.
+func (that *Class) archOptions() []string {
+	var res []string
+	for _, a := range that.Arch {
+		for _, o := range that.archList() {
+			if platform.Canonical(o) == platform.Canonical(a) {
+				res = append(res, o)
+				break
+			}
+		}
 	}
+	return res
+}
+
+// loadExisting loads the package info of root, as written in the file, when
+// there is one.
+func (that *Class) loadExisting(root string) error {
+	if !that.checkPkgExists(root) {
+		return nil
+	}
+	content, err := that.fs.ReadFile(that.pkgPath(root))
+	if err == nil {
+		err = that.unmarshalPkg(content)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to load %s: %w", that.pkgPath(root), err)
+	}
+	return nil
+}
+
+// defaultArch records the local platform when no architecture is given and
+// recordLocalArch is configured.
+func (that *Class) defaultArch() {
+	if len(that.Arch) > 0 {
+		return
+	}
+	if that.config.RecordLocalArch {
+		that.Arch = []string{that.localArch()}
+		fmt.Printf("No build architecture specified, recording the local platform %s.\n", that.Arch[0])
+	} else {
+		fmt.Println("No build architecture specified, the package builds for the local platform only.")
+	}
+}
+
+// Loaded is the number of package info files read so far.
+func (that *Class) Loaded() int {
+	return that.loaded
+}
+
+func (that *Class) HasPackage(root string) bool {
+	return that.checkPkgExists(root)
 }
 
 func (that *Class) checkPkgExists(root string) bool {
 	if root == "" {
 		root, _ = os.Getwd()
 	}
-	_, err := os.Stat(path.Join(root, that.config.PkgInfoFile))
+	_, err := that.fs.Stat(that.pkgPath(root))
 	return err == nil
 }
 
-func (that *Class) CreatePkg(root string) {
+func (that *Class) CreatePkg(root string) error {
 	if root == "" {
 		root, _ = os.Getwd()
 	}
-	raw, err := yaml.Marshal(that)
+	pth := that.pkgPath(root)
+	if err := that.checkFormat(pth); err != nil {
+		return err
+	}
+	raw, err := that.marshalPkg(pth, that.pkgFormat(pth))
 	if err != nil {
-		log.Fatalf("Unable to stringify the %s`s file content", that.config.PkgInfoFile)
+		return fmt.Errorf("unable to stringify the %s file content: %w", that.config.PkgInfoFile, err)
 	}
-	tmp := fmt.Sprintf("# %s pkg.info file\n\n", that.Name) + string(raw)
-	err = os.WriteFile(that.config.PkgInfoFile, []byte(tmp), 777)
+	err = that.writeFile(pth, raw, that.fileMode())
 	if err != nil {
-		log.Fatalf("Unable to write the %s file.", that.config.PkgInfoFile)
+		return fmt.Errorf("unable to write the %s file: %w", pth, err)
 	}
+	return nil
 }
 
-func (that *Class) GetPackage(root string) {
+func (that *Class) GetPackage(root string) error {
 	if root == "" {
 		root, _ = os.Getwd()
 	}
-	content, err := os.ReadFile(that.config.PkgInfoFile)
+	pth := that.pkgPath(root)
+	content, err := that.fs.ReadFile(pth)
 	if err != nil {
-		log.Fatalf("Unable to read the %s`s file from %s.", that.config.PkgInfoFile, root)
+		return fmt.Errorf("unable to read the %s file from %s: %w", that.config.PkgInfoFile, root, err)
 	}
-	err = yaml.Unmarshal(content, that)
-}
-
-func (that *Class) CreateReadme(root string, silent bool) {
-
-	type TplData struct {
-		Name        string
-		Version     string
-		Description string
-		Icon        string
-	}
-
-	tpl, err := template.New("").Parse(that.config.Tpl)
+	err = that.unmarshalPkg(content)
 	if err != nil {
-		log.Fatal("Unable to parse the README.md template")
+		return fmt.Errorf("unable to parse %s: %w", pth, err)
 	}
+	that.applyEnv()
+	that.loaded++
+	return nil
+}
+
+func (that *Class) CreateReadme(ctx context.Context, root string, silent bool) error {
 	if root == "" {
 		root, _ = os.Getwd()
 	}
+	return that.CreateReadmeAt(ctx, root, path.Join(root, that.config.ReadmeFile), silent)
+}
+
+// CreateReadmeAt writes the README of the package in root to pth instead of
+// the configured readmeFile.
+func (that *Class) CreateReadmeAt(ctx context.Context, root string, pth string, silent bool) error {
 	var iconPath string
+	var err error
 	if !silent {
 		msg := fmt.Sprintf("Repo icon file. Defaults to: %s. (Enter for default)", that.config.IconPath)
-		iconPath = prompt(msg, getValidator("none"))
+		iconPath, err = that.prompter.Prompt(msg, "", that.iconValid(root))
+		if err != nil {
+			return err
+		}
+	}
+
+	raw, err := that.renderReadme(ctx, root, iconPath)
+	if err != nil {
+		return err
+	}
+	if raw, err = that.managedReadme(pth, raw); err != nil {
+		return err
+	}
+	err = that.writeFile(pth, raw, that.fileMode())
+	if err != nil {
+		return fmt.Errorf("unable to write %s, check if you have permissions to do so: %w", pth, err)
+	}
+	return nil
+}
+
+// RenderReadme is the README of the package in root, with the configured icon.
+func (that *Class) RenderReadme(ctx context.Context, root string) ([]byte, error) {
+	return that.renderReadme(ctx, root, "")
+}
+
+// renderReadme executes the README template for the package in root, with
+// the configured icon unless iconPath is given.
+func (that *Class) renderReadme(ctx context.Context, root string, iconPath string) ([]byte, error) {
+	type TplData struct {
+		Name         string
+		Version      string
+		Channel      string
+		Description  string
+		Summary      string
+		Icon         string
+		BuildURL     string
+		QuickStart   string
+		Architecture string
+		API          *API
+		BinaryName   string
+		InstallPath  string
+		Downloads    []Download
+		Badges       []Badge
+		License      string
+		Maintainers  []pkginfo.Maintainer
+		Keywords     []string
+		Dependencies []pkginfo.Dependency
+	}
+
+	funcs := template.FuncMap{
+		"multiline": multiline,
+		"snippet": func(name string) (string, error) {
+			return that.snippet(root, name)
+		},
+	}
+	for name, fn := range templateFuncs {
+		funcs[name] = fn
 	}
 
+	text, err := that.readmeTemplate(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	tpl, err := template.New("").Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the README.md template: %w", err)
+	}
 	if iconPath == "" {
 		iconPath = that.config.IconPath
 	}
+	if iconPath, err = that.iconSrc(root, iconPath); err != nil {
+		return nil, err
+	}
 
+	modPath := that.goModule(root)
 	tplData := TplData{
-		Name:        strings.ToUpper(that.Name),
-		Version:     that.Version,
-		Description: that.Description,
-		Icon:        iconPath,
+		Name:         that.Name,
+		Version:      that.Version,
+		Channel:      that.Channel,
+		Description:  that.Description,
+		Summary:      summarize(that.Description, that.config.SummaryLength),
+		Icon:         iconPath,
+		QuickStart:   that.quickStart(modPath),
+		BinaryName:   that.binaryName(),
+		InstallPath:  that.installPath(),
+		License:      that.License,
+		Maintainers:  that.Maintainers,
+		Keywords:     that.Keywords,
+		Dependencies: that.Dependencies,
 	}
-
-	pth := path.Join(root, that.config.ReadmeFile)
-	fOut, err := os.Create(pth)
-	defer func(f *os.File) {
-		err = f.Close()
-		if err != nil {
-			log.Printf("WARN: Could not close file %s after writing", pth)
-		}
-	}(fOut)
+	tplData.Downloads, err = that.downloads()
 	if err != nil {
-		log.Fatalf("Unable to write %s file in  %s. Check if you have permisssions to do so.",
-			that.config.ReadmeFile, root)
+		return nil, err
 	}
-	err = tpl.Execute(fOut, tplData)
+	tplData.Badges, err = that.badges(root)
 	if err != nil {
-		log.Fatalf("ERROR: while processing README.md template. Reason: %s", err.Error())
+		return nil, err
+	}
+	if that.config.ArchitectureDiagram {
+		tplData.Architecture, err = that.diagram(ctx, root, modPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if that.config.APIDoc {
+		tplData.API, err = that.apiDoc(ctx, root)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if c := that.CI(); c != nil {
+		tplData.BuildURL = c.BuildURL
 	}
 
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = tpl.Execute(&buf, tplData)
+	if err != nil {
+		return nil, fmt.Errorf("while processing README.md template: %w", err)
+	}
+	return []byte(insertTOC(buf.String())), nil
 }
@@ -0,0 +1,744 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"gov/config"
+	"gov/pkginfo"
+	"gov/txn"
+	"io/fs"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+const tRoot = "/project"
+
+var tConfig = []byte(`
+pkgInfoFile: pkg.info
+iconPath: icon.png
+readmeFile: README.md
+summaryLength: 80
+archList:
+    - linux_amd64
+    - darwin_arm64
+`)
+
+const tTpl = "# {{ upper .Name }} {{ .Version }}\n{{ .Summary }}\n![logo]({{ .Icon }})\n"
+
+type memFile struct {
+	name string
+	size int64
+}
+
+func (m memFile) Name() string       { return m.name }
+func (m memFile) Size() int64        { return m.size }
+func (m memFile) Mode() fs.FileMode  { return 0644 }
+func (m memFile) ModTime() time.Time { return time.Time{} }
+func (m memFile) IsDir() bool        { return false }
+func (m memFile) Sys() interface{}   { return nil }
+
+type memFS map[string][]byte
+
+func (m memFS) ReadFile(name string) ([]byte, error) {
+	if b, ok := m[name]; ok {
+		return b, nil
+	}
+	return nil, os.ErrNotExist
+}
+
+func (m memFS) WriteFile(name string, data []byte, _ os.FileMode) error {
+	m[name] = data
+	return nil
+}
+
+func (m memFS) Remove(name string) error {
+	delete(m, name)
+	return nil
+}
+
+func (m memFS) Stat(name string) (os.FileInfo, error) {
+	if b, ok := m[name]; ok {
+		return memFile{name: path.Base(name), size: int64(len(b))}, nil
+	}
+	return nil, os.ErrNotExist
+}
+
+type scriptPrompter struct {
+	answers []string
+	asked   []string
+}
+
+func (s *scriptPrompter) next(label string) string {
+	s.asked = append(s.asked, label)
+	if len(s.answers) == 0 {
+		return ""
+	}
+	a := s.answers[0]
+	s.answers = s.answers[1:]
+	return a
+}
+
+func (s *scriptPrompter) Prompt(label string, def string, valid func(st string) bool) (string, error) {
+	for {
+		if len(s.answers) == 0 {
+			return "", fmt.Errorf("no answer scripted for %q", label)
+		}
+		a := s.next(label)
+		if a == "" && def != "" {
+			a = def
+		}
+		if valid(a) {
+			return strings.TrimSpace(a), nil
+		}
+	}
+}
+
+func (s *scriptPrompter) Confirm(label string) (bool, error) {
+	if len(s.answers) == 0 {
+		return false, fmt.Errorf("no answer scripted for %q", label)
+	}
+	a := s.next(label)
+	return a == "y" || a == "yes", nil
+}
+
+// Select takes a comma separated answer, empty keeping selected.
+func (s *scriptPrompter) Select(label string, options []string, selected []string) ([]string, error) {
+	if len(s.answers) == 0 {
+		return nil, fmt.Errorf("no answer scripted for %q", label)
+	}
+	a := s.next(label)
+	if a == "" {
+		return selected, nil
+	}
+	res := pkginfo.SplitList(a)
+	for _, item := range res {
+		if !contains(options, item) {
+			return nil, fmt.Errorf("%q is not an option of %q", item, label)
+		}
+	}
+	return res, nil
+}
+
+func (s *scriptPrompter) Edit(text string) (string, error) {
+	return s.next("edit"), nil
+}
+
+type fakeRunner map[string]string
+
+func (f fakeRunner) Run(ctx context.Context, dir string, name string, args ...string) ([]byte, error) {
+	return f.RunEnv(ctx, dir, nil, name, args...)
+}
+
+// RunEnv looks commands up with the env prefixed: "GOOS=linux go build".
+func (f fakeRunner) RunEnv(_ context.Context, _ string, env []string, name string, args ...string) ([]byte, error) {
+	cmd := strings.Join(append(append(env, name), args...), " ")
+	if out, ok := f[cmd]; ok {
+		return []byte(out), nil
+	}
+	return nil, fmt.Errorf("%s: not available", cmd)
+}
+
+// recordRunner is a fakeRunner recording the commands it is asked to run.
+type recordRunner struct {
+	fakeRunner
+	calls []string
+}
+
+func (r *recordRunner) Run(ctx context.Context, dir string, name string, args ...string) ([]byte, error) {
+	return r.RunEnv(ctx, dir, nil, name, args...)
+}
+
+func (r *recordRunner) RunEnv(ctx context.Context, dir string, env []string, name string, args ...string) ([]byte, error) {
+	r.calls = append(r.calls, strings.Join(append(append(env, name), args...), " "))
+	return r.fakeRunner.RunEnv(ctx, dir, env, name, args...)
+}
+
+func newTestClass(fsys memFS, answers ...string) (*Class, *scriptPrompter) {
+	return newTestClassRunner(fsys, fakeRunner{}, answers...)
+}
+
+func newTestClassRunner(fsys memFS, r Runner, answers ...string) (*Class, *scriptPrompter) {
+	cfg, err := config.New(tConfig, []byte(tTpl), os.DirFS(".."))
+	if err != nil {
+		panic(err)
+	}
+	p := &scriptPrompter{answers: answers}
+	return New(cfg, WithFS(fsys), WithPrompter(p), WithRunner(r)), p
+}
+
+func TestPromptPkg_creates(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys, "demo", "not-semver", "1.2.0", "A demo.", "Bad Keyword", "cli, tooling", "acme", "junk", "git@github.com:acme/demo.git", "tool", "cli", "NOT-SPDX", "MIT", "Jane <not-an-email>", "Jane Doe <jane@acme.io> (lead)", "linux_amd64", "y")
+	if err := gopi.PromptPkg(context.Background(), tRoot, false); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, ok := fsys[path.Join(tRoot, "pkg.info")]
+	if !ok {
+		t.Fatal("pkg.info not written")
+	}
+	content := string(raw)
+	if !strings.Contains(content, "name: demo") || !strings.Contains(content, "version: 1.2.0") {
+		t.Fail()
+	}
+	if !strings.Contains(content, "- linux_amd64") || !strings.Contains(content, "type: cli") || !strings.Contains(content, "license: MIT") {
+		t.Fail()
+	}
+	if !strings.Contains(content, "repo: https://github.com/acme/demo") {
+		t.Fail()
+	}
+	if !strings.Contains(content, "keywords:\n    - cli\n    - tooling") {
+		t.Fail()
+	}
+	if !strings.Contains(content, "email: jane@acme.io") || !strings.Contains(content, "role: lead") {
+		t.Fail()
+	}
+}
+
+func TestPromptPkg_assume_yes(t *testing.T) {
+	fsys := memFS{}
+	gopi, p := newTestClass(fsys, "demo", "1.2.0", "", "", "acme", "", "cli", "", "", "")
+	gopi.config.AssumeYes = true
+	if err := gopi.PromptPkg(context.Background(), tRoot, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fsys[path.Join(tRoot, "pkg.info")]; !ok || len(p.asked) != 10 {
+		t.Fatal(p.asked)
+	}
+}
+
+func TestPromptPkg_local_arch(t *testing.T) {
+	for _, record := range []bool{true, false} {
+		fsys := memFS{}
+		gopi, _ := newTestClass(fsys, "demo", "1.2.0", "", "", "acme", "", "cli", "", "", "", "y")
+		gopi.config.RecordLocalArch = record
+		if err := gopi.PromptPkg(context.Background(), tRoot, false); err != nil {
+			t.Fatal(err)
+		}
+		recorded := len(gopi.Arch) == 1 && gopi.Arch[0] == runtime.GOOS+"_"+runtime.GOARCH
+		if recorded != record || (!record && len(gopi.Arch) != 0) {
+			t.Fatalf("record %v: arch %v", record, gopi.Arch)
+		}
+	}
+}
+
+func TestPromptPkg_declined(t *testing.T) {
+	pth := path.Join(tRoot, "pkg.info")
+	fsys := memFS{pth: []byte("name: old\n")}
+	gopi, _ := newTestClass(fsys, "demo", "1.2.0", "", "", "acme", "", "", "", "", "linux_amd64", "n")
+	if err := gopi.PromptPkg(context.Background(), tRoot, false); err != nil {
+		t.Fatal(err)
+	}
+	if string(fsys[pth]) != "name: old\n" {
+		t.Fail()
+	}
+}
+
+func TestPromptPkg_edit(t *testing.T) {
+	pth := path.Join(tRoot, "pkg.info")
+	fsys := memFS{pth: []byte("name: demo\nversion: 1.2.0\ndescription: A demo.\nkeywords: [cli, tooling]\ntenant: acme\n" +
+		"repo: https://github.com/acme/demo\ntype: cli\nlicense: MIT\nmaintainers:\n    - name: Jane\n      email: jane@acme.io\n      role: lead\n" +
+		"arch: [linux_amd64]\n")}
+	gopi, _ := newTestClass(fsys, "", "1.3.0", "", "", "", "", "", "", "", "", "y")
+	gopi.config.AutoAccept = true
+	if err := gopi.PromptPkg(context.Background(), tRoot, false); err != nil {
+		t.Fatal(err)
+	}
+	if gopi.Version != "1.3.0" || gopi.Description != "A demo." || len(gopi.Keywords) != 2 || gopi.License != "MIT" {
+		t.Fatal(gopi.Info)
+	}
+	if len(gopi.Maintainers) != 1 || gopi.Maintainers[0].Role != "lead" || len(gopi.Arch) != 1 || gopi.Arch[0] != "linux_amd64" {
+		t.Fatal(gopi.Info)
+	}
+	if !strings.Contains(string(fsys[pth]), "version: 1.3.0") {
+		t.Fail()
+	}
+}
+
+func TestPromptPkg_force(t *testing.T) {
+	pth := path.Join(tRoot, "pkg.info")
+	fsys := memFS{pth: []byte("name: old\n")}
+	gopi, p := newTestClass(fsys, "demo", "1.2.0", "", "", "acme", "", "", "", "", "linux_amd64")
+	gopi.config.AutoAccept = true
+	if err := gopi.PromptPkg(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(fsys[pth]), "name: demo") || len(p.asked) != 10 {
+		t.Fatal(p.asked)
+	}
+}
+
+func TestCreatePkg_roundtrip(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	gopi.Name = "demo"
+	gopi.Version = "0.3.0"
+	gopi.Tenant = "acme"
+	if err := gopi.CreatePkg(tRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	if !gopi.checkPkgExists(tRoot) {
+		t.Fatal("pkg.info not written")
+	}
+	other, _ := newTestClass(fsys)
+	if err := other.GetPackage(tRoot); err != nil {
+		t.Fatal(err)
+	}
+	if other.Name != "demo" || other.Version != "0.3.0" || other.Tenant != "acme" {
+		t.Fail()
+	}
+}
+
+func TestCreateReadme_default_icon(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys, "")
+	gopi.Name = "demo"
+	gopi.Version = "1.0.0"
+	gopi.Description = "Demo project. With details."
+	if err := gopi.CreateReadme(context.Background(), tRoot, false); err != nil {
+		t.Fatal(err)
+	}
+
+	got := string(fsys[path.Join(tRoot, "README.md")])
+	if got != "# DEMO 1.0.0\nDemo project.\n![logo](icon.png)\n" {
+		t.Fatalf("unexpected README: %q", got)
+	}
+}
+
+func TestCreateReadme_silent(t *testing.T) {
+	fsys := memFS{}
+	gopi, p := newTestClass(fsys, "custom.png")
+	gopi.Name = "demo"
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(p.asked) != 0 {
+		t.Fail()
+	}
+	if !strings.Contains(string(fsys[path.Join(tRoot, "README.md")]), "icon.png") {
+		t.Fail()
+	}
+}
+
+func TestCreateReadme_review_partial(t *testing.T) {
+	t.Setenv("CI", "")
+	fsys := memFS{}
+	pth := path.Join(tRoot, "README.md")
+	fsys[pth] = []byte("# OLD 0.9.0\nDemo project.\n![logo](icon.png)\n")
+	gopi, _ := newTestClass(fsys, "n")
+	gopi.Name = "demo"
+	gopi.Version = "1.0.0"
+	gopi.Description = "Demo project."
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(fsys[pth]) != "# OLD 0.9.0\nDemo project.\n![logo](icon.png)\n" {
+		t.Fail()
+	}
+}
+
+func TestCreateReadme_review_edit(t *testing.T) {
+	t.Setenv("CI", "")
+	fsys := memFS{}
+	pth := path.Join(tRoot, "README.md")
+	fsys[pth] = []byte("# OLD 0.9.0\nDemo project.\n![logo](icon.png)\n")
+	gopi, _ := newTestClass(fsys, "e", "# Edited\nDemo project.\n![logo](icon.png)\n")
+	gopi.Name = "demo"
+	gopi.Version = "1.0.0"
+	gopi.Description = "Demo project."
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(fsys[pth]) != "# Edited\nDemo project.\n![logo](icon.png)\n" {
+		t.Fatalf("unexpected README: %q", fsys[pth])
+	}
+}
+
+func TestCreateReadme_auto_accept_in_ci(t *testing.T) {
+	t.Setenv("CI", "true")
+	fsys := memFS{}
+	pth := path.Join(tRoot, "README.md")
+	fsys[pth] = []byte("old\n")
+	gopi, p := newTestClass(fsys)
+	gopi.Name = "demo"
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(p.asked) != 0 || !strings.HasPrefix(string(fsys[pth]), "# DEMO") {
+		t.Fail()
+	}
+}
+
+func TestCreateReadme_snippet(t *testing.T) {
+	fsys := memFS{}
+	fsys["/shared/snippets/support.md"] = []byte("Contact <support@acme.io> & friends\n")
+	gopi, _ := newTestClass(fsys)
+	gopi.config.SnippetsDir = "/shared/snippets"
+	gopi.config.Tpl = "# {{ upper .Name }}\n{{ snippet \"support\" }}\n"
+	gopi.Name = "demo"
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(fsys[path.Join(tRoot, "README.md")]) != "# DEMO\nContact <support@acme.io> & friends\n" {
+		t.Fail()
+	}
+}
+
+func TestSnippet_relative_missing(t *testing.T) {
+	fsys := memFS{}
+	fsys[path.Join(tRoot, "docs/snippets/legal.txt")] = []byte("legal")
+	gopi, _ := newTestClass(fsys)
+	gopi.config.SnippetsDir = "docs/snippets"
+
+	if s, err := gopi.snippet(tRoot, "legal"); err != nil || s != "legal" {
+		t.Fail()
+	}
+	if _, err := gopi.snippet(tRoot, "missing"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestPromptPkg_gomod_defaults(t *testing.T) {
+	fsys := memFS{}
+	fsys[path.Join(tRoot, "go.mod")] = []byte("module github.com/acme/tool/v2\n\ngo 1.19\n")
+	gopi, _ := newTestClass(fsys, "", "1.0.0", "", "", "acme", "", "", "", "", "linux_amd64", "y")
+	if err := gopi.PromptPkg(context.Background(), tRoot, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if gopi.Name != "tool" || gopi.Repo != "https://github.com/acme/tool" {
+		t.Fail()
+	}
+}
+
+func TestBuildVersion_ci(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	WithEnv(func(k string) string {
+		return map[string]string{"GITLAB_CI": "true", "CI_COMMIT_SHA": "deadbeefcafe", "CI_PIPELINE_ID": "77"}[k]
+	})(gopi)
+	gopi.Version = "1.0.0-rc.1"
+	v, err := gopi.BuildVersion()
+	if err != nil || v != "1.0.0-rc.1+sha.deadbee.run.77" {
+		t.Fail()
+	}
+}
+
+func TestQuickStart(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.Name = "tool"
+	gopi.Tenant = "Acme"
+	gopi.Version = "1.2.0"
+
+	gopi.Type = "cli"
+	if !strings.Contains(string(gopi.quickStart("github.com/acme/tool")), "go install github.com/acme/tool@latest") {
+		t.Fail()
+	}
+	gopi.Type = "library"
+	if !strings.Contains(string(gopi.quickStart("github.com/acme/tool")), `import "github.com/acme/tool"`) {
+		t.Fail()
+	}
+	gopi.Type = "service"
+	if !strings.Contains(string(gopi.quickStart("")), "docker run --rm acme/tool:1.2.0") {
+		t.Fail()
+	}
+	gopi.Type = ""
+	if gopi.quickStart("github.com/acme/tool") != "" {
+		t.Fail()
+	}
+}
+
+func TestBump(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.Version = "1.3.0-rc.1+sha.abc"
+	if v, err := gopi.Bump("pre"); err != nil || v != "1.3.0-rc.2" {
+		t.Fail()
+	}
+	if v, err := gopi.Bump("minor"); err != nil || v != "1.4.0" {
+		t.Fail()
+	}
+	if _, err := gopi.Bump("huge"); err == nil || gopi.Version != "1.4.0" {
+		t.Fail()
+	}
+}
+
+func TestCreatePkg_json(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	gopi.config.PkgInfoFormat = "json"
+	gopi.Name = "demo"
+	gopi.Version = "1.0.0"
+	if err := gopi.CreatePkg(tRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, ok := fsys[path.Join(tRoot, "pkg.info.json")]
+	if !ok || !strings.HasPrefix(string(raw), "{\n  \"name\": \"demo\",") {
+		t.Fatalf("unexpected pkg.info.json: %q", raw)
+	}
+	other, _ := newTestClass(fsys)
+	if err := other.GetPackage(tRoot); err != nil {
+		t.Fatal(err)
+	}
+	if other.Name != "demo" || other.Version != "1.0.0" {
+		t.Fail()
+	}
+}
+
+func TestCreatePkg_format_mismatch(t *testing.T) {
+	pth := path.Join(tRoot, "pkg.info")
+	fsys := memFS{pth: []byte("name: demo\nversion: 1.0.0\n")}
+	gopi, _ := newTestClass(fsys)
+	gopi.config.PkgInfoFormat = "json"
+	if err := gopi.CreatePkg(tRoot); err == nil || !strings.Contains(err.Error(), "is yaml") {
+		t.Fatal(err)
+	}
+	if string(fsys[pth]) != "name: demo\nversion: 1.0.0\n" {
+		t.Fail()
+	}
+}
+
+func TestGetPackage_json_content(t *testing.T) {
+	t.Setenv("CI", "true")
+	fsys := memFS{}
+	pth := path.Join(tRoot, "pkg.info")
+	fsys[pth] = []byte(`{"name": "demo", "version": "2.0.0", "arch": ["linux_amd64"]}`)
+	gopi, _ := newTestClass(fsys)
+	if err := gopi.GetPackage(tRoot); err != nil {
+		t.Fatal(err)
+	}
+	if gopi.Version != "2.0.0" || len(gopi.Arch) != 1 {
+		t.Fail()
+	}
+	if err := gopi.CreatePkg(tRoot); err != nil {
+		t.Fatal(err)
+	}
+	if !isJSON(fsys[pth]) {
+		t.Fail()
+	}
+}
+
+func TestValidate_ok(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.Name, gopi.Version, gopi.Tenant = "demo", "1.0.0", "acme"
+	gopi.Repo = "git@github.com:acme/demo.git"
+	gopi.Arch = []string{"linux_amd64"}
+	if d := gopi.Validate(context.Background()); len(d) != 0 {
+		t.Fatalf("unexpected diagnostics %v", d)
+	}
+}
+
+func TestValidate_all_problems(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.Version = "1.0"
+	gopi.Repo = "not a url"
+	gopi.Type = "daemon"
+	gopi.Arch = []string{"linux_amd64", "plan9"}
+
+	var got []string
+	for _, d := range gopi.Validate(context.Background()) {
+		got = append(got, d.Field+"/"+d.Code)
+	}
+	want := "name/required version/semver tenant/required repo/url type/enum arch[1]/arch"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestGetPackage_errors(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	if err := gopi.GetPackage(tRoot); err == nil {
+		t.Fail()
+	}
+	fsys[path.Join(tRoot, "pkg.info")] = []byte("name: [unterminated\n")
+	if err := gopi.GetPackage(tRoot); err == nil || gopi.Loaded() != 0 {
+		t.Fail()
+	}
+}
+
+func TestPromptPkg_input_error(t *testing.T) {
+	gopi, _ := newTestClass(memFS{}, "demo")
+	if err := gopi.PromptPkg(context.Background(), tRoot, false); err == nil {
+		t.Fail()
+	}
+}
+
+func TestCreateReadme_canceled(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := gopi.CreateReadme(ctx, tRoot, true); err != context.Canceled {
+		t.Fail()
+	}
+	if _, ok := fsys[path.Join(tRoot, "README.md")]; ok {
+		t.Fail()
+	}
+}
+
+func TestWriteFile_size_limit(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	gopi.Name = "demo"
+	gopi.config.Limits.MaxFileSize = 10
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err == nil || !strings.Contains(err.Error(), "maxFileSize") {
+		t.Fatal(err)
+	}
+	if _, ok := fsys[path.Join(tRoot, "README.md")]; ok {
+		t.Fail()
+	}
+}
+
+func TestWriteFile_files_limit(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.config.Limits.MaxFiles = 1
+	if err := gopi.writeFile(path.Join(tRoot, "a"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := gopi.writeFile(path.Join(tRoot, "b"), []byte("b"), 0644); err == nil {
+		t.Fail()
+	}
+}
+
+func TestWriteFile_review_answers(t *testing.T) {
+	pth := path.Join(tRoot, "a")
+	fsys := memFS{pth: []byte("old\n")}
+	// substrings of the answer set are asked again
+	gopi, p := newTestClass(fsys, "yn", "na", "y")
+	gopi.getenv = func(string) string { return "" }
+	if err := gopi.writeFile(pth, []byte("new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if string(fsys[pth]) != "new\n" || len(p.asked) != 3 {
+		t.Fatal(string(fsys[pth]), p.asked)
+	}
+}
+
+func TestWriteFile_backup_restore(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	gopi.config.AutoAccept = true
+	gopi.config.Backup.Enabled = true
+	pth := path.Join(tRoot, "README.md")
+	fsys[pth] = []byte("hand edited\n")
+	if err := gopi.writeFile(pth, []byte("generated\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if string(fsys[pth+".bak"]) != "hand edited\n" {
+		t.Fatal("no backup written")
+	}
+	restored, err := gopi.Restore(tRoot)
+	if err != nil || len(restored) != 1 || string(fsys[pth]) != "hand edited\n" {
+		t.Fail()
+	}
+}
+
+func TestRestore_backup_dir(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	gopi.config.AutoAccept = true
+	gopi.config.Backup = config.Backup{Enabled: true, Dir: ".backup"}
+	pth := path.Join(tRoot, "pkg.info")
+	fsys[pth] = []byte("name: old\n")
+	_ = gopi.writeFile(pth, []byte("name: new\n"), 0644)
+	if string(fsys[path.Join(tRoot, ".backup", "pkg.info.bak")]) != "name: old\n" {
+		t.Fatal("backup not in the backup dir")
+	}
+	if _, err := gopi.Restore(tRoot, "pkg.info"); err != nil || string(fsys[pth]) != "name: old\n" {
+		t.Fail()
+	}
+	if _, err := gopi.Restore(tRoot, "README.md"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestCreatePkg_transaction(t *testing.T) {
+	fsys := memFS{}
+	tx := txn.New(t.TempDir())
+	cfg, _ := config.New(tConfig, []byte(tTpl), os.DirFS(".."))
+	gopi := New(cfg, WithFS(fsys), WithTransaction(tx))
+	gopi.Name, gopi.Version = "demo", "1.0.0"
+	if err := gopi.CreatePkg(tRoot); err != nil {
+		t.Fatal(err)
+	}
+	pth := path.Join(tRoot, "pkg.info")
+	if _, ok := fsys[pth]; ok || tx.Len() != 1 {
+		t.Fatal("write not staged")
+	}
+	if err := gopi.GetPackage(tRoot); err != nil || gopi.Name != "demo" {
+		t.Fatal("staged content not readable")
+	}
+}
+
+func TestGetPackage_env_overrides(t *testing.T) {
+	fsys := memFS{path.Join(tRoot, "pkg.info"): []byte("name: demo\nversion: 1.0.0\ntenant: acme\narch:\n    - linux_amd64\n")}
+	gopi, _ := newTestClass(fsys)
+	gopi.getenv = func(k string) string {
+		return map[string]string{"GOPI_PKG_VERSION": "1.3.0-rc.1", "GOPI_PKG_ARCH": "linux_amd64, darwin_arm64"}[k]
+	}
+	if err := gopi.GetPackage(tRoot); err != nil {
+		t.Fatal(err)
+	}
+	if gopi.Version != "1.3.0-rc.1" || len(gopi.Arch) != 2 || gopi.Name != "demo" {
+		t.Fatal(gopi.Info)
+	}
+
+	gopi.Description = "changed"
+	gopi.config.AutoAccept = true
+	if err := gopi.CreatePkg(tRoot); err != nil {
+		t.Fatal(err)
+	}
+	got := string(fsys[path.Join(tRoot, "pkg.info")])
+	if !strings.Contains(got, "version: 1.0.0") || strings.Contains(got, "darwin_arm64") || !strings.Contains(got, "description: changed") {
+		t.Fatal(got)
+	}
+}
+
+func TestWriteFile_backup_dir_disk(t *testing.T) {
+	dir := t.TempDir()
+	backups := path.Join(dir, "backups")
+	gopi, _ := newTestClass(memFS{})
+	gopi.fs = osFS{}
+	gopi.config.AutoAccept = true
+	gopi.config.Backup = config.Backup{Enabled: true, Dir: ".backup"}
+
+	// the relative directory is created next to the file
+	api, web := path.Join(dir, "api", "pkg.info"), path.Join(dir, "web", "pkg.info")
+	for _, pth := range []string{api, web} {
+		_ = os.MkdirAll(path.Dir(pth), 0755)
+		_ = os.WriteFile(pth, []byte("name: "+path.Base(path.Dir(pth))+"\n"), 0644)
+	}
+	if err := gopi.writeFile(api, []byte("name: new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if raw, err := os.ReadFile(path.Join(dir, "api", ".backup", "pkg.info.bak")); err != nil || string(raw) != "name: api\n" {
+		t.Fatal(string(raw), err)
+	}
+
+	// an absolute one keeps a backup per package
+	gopi.config.Backup.Dir = backups
+	for _, pth := range []string{api, web} {
+		if err := gopi.writeFile(pth, []byte("name: newer\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if raw, _ := os.ReadFile(path.Join(backups, dir, "web", "pkg.info.bak")); string(raw) != "name: web\n" {
+		t.Fatal(string(raw))
+	}
+	if _, err := gopi.Restore(path.Join(dir, "api"), "pkg.info"); err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := os.ReadFile(api); string(raw) != "name: new\n" {
+		t.Fatal(string(raw))
+	}
+}
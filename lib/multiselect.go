@@ -0,0 +1,186 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// selectRows is how many options the multi-select list shows at a time.
+const selectRows = 10
+
+var errSelectAborted = errors.New("selection aborted")
+
+// multiSelect is the state of the multi-select list: the option under the
+// cursor, the picked options and the first visible row.
+type multiSelect struct {
+	options []string
+	picked  []bool
+	cursor  int
+	top     int
+}
+
+func newMultiSelect(options []string, selected []string) *multiSelect {
+	that := &multiSelect{options: options, picked: make([]bool, len(options))}
+	for i, o := range options {
+		that.picked[i] = contains(selected, o)
+	}
+	return that
+}
+
+// key applies a key press: arrows (or j/k) move, space toggles, enter
+// confirms and ctrl-c or escape aborts.
+func (that *multiSelect) key(k string) (done bool, err error) {
+	switch k {
+	case "\x1b[A", "k":
+		if that.cursor > 0 {
+			that.cursor--
+		}
+	case "\x1b[B", "j":
+		if that.cursor < len(that.options)-1 {
+			that.cursor++
+		}
+	case " ":
+		if len(that.options) > 0 {
+			that.picked[that.cursor] = !that.picked[that.cursor]
+		}
+	case "\r", "\n":
+		return true, nil
+	case "\x03", "\x1b":
+		return true, errSelectAborted
+	}
+	if that.cursor < that.top {
+		that.top = that.cursor
+	} else if that.cursor >= that.top+selectRows {
+		that.top = that.cursor - selectRows + 1
+	}
+	return false, nil
+}
+
+// render draws the visible rows, one line each.
+func (that *multiSelect) render() []string {
+	var lines []string
+	for i := that.top; i < len(that.options) && i < that.top+selectRows; i++ {
+		pointer, box := "  ", "[ ]"
+		if i == that.cursor {
+			pointer = "> "
+		}
+		if that.picked[i] {
+			box = "[x]"
+		}
+		lines = append(lines, pointer+box+" "+that.options[i])
+	}
+	return lines
+}
+
+func (that *multiSelect) selected() []string {
+	var res []string
+	for i, o := range that.options {
+		if that.picked[i] {
+			res = append(res, o)
+		}
+	}
+	return res
+}
+
+// stty runs stty on the terminal of stdin.
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// Select lets the user pick options with the arrow keys and space, falling
+// back to a numbered list answered with a comma separated line when the
+// terminal can't be switched to raw mode.
+func (that *consolePrompter) Select(label string, options []string, selected []string) ([]string, error) {
+	if !IsTerminal(os.Stdin) {
+		return that.selectLine(label, options, selected)
+	}
+	saved, err := stty("-g")
+	if err == nil {
+		_, err = stty("raw", "-echo")
+	}
+	if err != nil {
+		return that.selectLine(label, options, selected)
+	}
+	defer func() {
+		_, _ = stty(saved)
+	}()
+
+	m := newMultiSelect(options, selected)
+	fmt.Fprintf(that.out, "%s(arrows move, space toggles, enter confirms)\r\n", label)
+	drawn := 0
+	for {
+		if drawn > 0 {
+			fmt.Fprintf(that.out, "\x1b[%dA", drawn)
+		}
+		lines := m.render()
+		for _, line := range lines {
+			fmt.Fprintf(that.out, "\x1b[2K%s\r\n", line)
+		}
+		drawn = len(lines)
+
+		k, err := that.readKey()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read from console: %w", err)
+		}
+		if done, err := m.key(k); done {
+			return m.selected(), err
+		}
+	}
+}
+
+// readKey reads a key press, escape sequences included, in raw mode.
+func (that *consolePrompter) readKey() (string, error) {
+	b, err := that.in.ReadByte()
+	if err != nil || b != 0x1b || that.in.Buffered() < 2 {
+		return string(b), err
+	}
+	seq := []byte{b, 0, 0}
+	seq[1], _ = that.in.ReadByte()
+	seq[2], _ = that.in.ReadByte()
+	return string(seq), nil
+}
+
+// selectLine asks for the options as a comma separated list of names or
+// numbers, until every item is one of options. Enter keeps selected.
+func (that *consolePrompter) selectLine(label string, options []string, selected []string) ([]string, error) {
+	for i, o := range options {
+		fmt.Fprintf(that.out, "%3d. %s\n", i+1, o)
+	}
+	if len(selected) > 0 {
+		label = fmt.Sprintf("%s[%s] ", label, strings.Join(selected, ", "))
+	}
+	for {
+		s, err := that.read(label)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(s) == "" {
+			return selected, nil
+		}
+		var res, unknown []string
+		for _, item := range strings.Split(s, ",") {
+			item = strings.TrimSpace(item)
+			if n, err := strconv.Atoi(item); err == nil && n >= 1 && n <= len(options) {
+				item = options[n-1]
+			}
+			switch {
+			case item == "":
+			case contains(options, item):
+				res = append(res, item)
+			default:
+				unknown = append(unknown, item)
+			}
+		}
+		if len(unknown) == 0 {
+			return res, nil
+		}
+		fmt.Fprintf(that.out, "unknown %s, pick names or numbers from the list\n", strings.Join(unknown, ", "))
+	}
+}
@@ -0,0 +1,53 @@
+package lib
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMultiSelect(t *testing.T) {
+	m := newMultiSelect([]string{"linux_amd64", "darwin_arm64", "windows_amd64"}, []string{"darwin_arm64"})
+	for _, k := range []string{" ", "\x1b[B", "\x1b[B", "\x1b[B", " ", "k", " "} {
+		if done, err := m.key(k); done || err != nil {
+			t.Fatal(k, err)
+		}
+	}
+	if strings.Join(m.render(), "|") != "  [x] linux_amd64|> [ ] darwin_arm64|  [x] windows_amd64" {
+		t.Fatal(m.render())
+	}
+	if done, err := m.key("\r"); !done || err != nil || strings.Join(m.selected(), ",") != "linux_amd64,windows_amd64" {
+		t.Fatal(m.selected(), err)
+	}
+	if done, err := m.key("\x03"); !done || err != errSelectAborted {
+		t.Fail()
+	}
+}
+
+func TestMultiSelect_scroll(t *testing.T) {
+	options := make([]string, 15)
+	for i := range options {
+		options[i] = string(rune('a' + i))
+	}
+	m := newMultiSelect(options, nil)
+	for i := 0; i < 12; i++ {
+		_, _ = m.key("j")
+	}
+	if r := m.render(); len(r) != selectRows || r[0] != "  [ ] d" || r[9] != "> [ ] m" {
+		t.Fatal(r)
+	}
+}
+
+func TestSelectLine(t *testing.T) {
+	var out bytes.Buffer
+	p := &consolePrompter{in: bufio.NewReader(strings.NewReader("wioll, 1\n2, linux_amd64\n")), out: &out}
+	res, err := p.selectLine("Arch: ", []string{"linux_amd64", "darwin_arm64"}, nil)
+	if err != nil || strings.Join(res, ",") != "darwin_arm64,linux_amd64" || !strings.Contains(out.String(), "unknown wioll") {
+		t.Fatal(res, err, out.String())
+	}
+	p = &consolePrompter{in: bufio.NewReader(strings.NewReader("\n")), out: &out}
+	if res, err = p.selectLine("Arch: ", []string{"linux_amd64"}, []string{"linux_amd64"}); err != nil || len(res) != 1 {
+		t.Fatal(res, err)
+	}
+}
@@ -0,0 +1,118 @@
+package lib
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"gov/pkginfo"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// tplExt marks the files of a project template rendered with the answers.
+const tplExt = ".tpl"
+
+// FetchProject makes the project template src available: a local directory
+// as is, a git url cloned (shallow) into a temporary directory, removed by
+// the returned cleanup.
+func (that *Class) FetchProject(ctx context.Context, src string) (string, func(), error) {
+	if fi, err := os.Stat(src); err == nil && fi.IsDir() {
+		return src, func() {}, nil
+	}
+	if !strings.Contains(src, "://") && !pkginfo.ScpLike.MatchString(src) {
+		return "", nil, fmt.Errorf("the project template %s is neither a directory nor a git url", src)
+	}
+	dir, err := os.MkdirTemp("", "gopi-template-*")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() {
+		_ = os.RemoveAll(dir)
+	}
+	if _, err = that.runner.Run(ctx, "", "git", "clone", "--depth", "1", "--", src, dir); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("unable to clone the project template %s: %w", src, err)
+	}
+	return dir, cleanup, nil
+}
+
+// ProjectDefaults loads the package info of the project template in dir as
+// the defaults of init, without its name and repo which belong to each
+// project.
+func (that *Class) ProjectDefaults(dir string) error {
+	tpl := os.DirFS(dir)
+	content, err := fs.ReadFile(tpl, that.config.PkgInfoFile)
+	if err != nil {
+		if content, err = fs.ReadFile(tpl, that.config.PkgInfoFile+jsonExt); err != nil {
+			// a template may only bring files
+			return nil
+		}
+	}
+	info, err := pkginfo.Parse(content)
+	if err != nil {
+		return fmt.Errorf("invalid package info in the project template: %w", err)
+	}
+	info.Name, info.Repo = "", ""
+	that.Info = *info
+	return nil
+}
+
+// InstantiateProject copies the files of the project template in dir to
+// root, but its package info and .git. The *.tpl files are rendered like the
+// scaffold templates and written without the extension; existing files are
+// kept.
+func (that *Class) InstantiateProject(ctx context.Context, dir string, root string) error {
+	tpl := os.DirFS(dir)
+	data := that.scaffoldData()
+	return fs.WalkDir(tpl, ".", func(rel string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if rel == that.config.PkgInfoFile || rel == that.config.PkgInfoFile+jsonExt {
+			return nil
+		}
+		raw, err := fs.ReadFile(tpl, rel)
+		if err != nil {
+			return err
+		}
+		target := path.Join(root, strings.TrimSuffix(rel, tplExt))
+		if strings.HasSuffix(rel, tplExt) {
+			t, err := template.New(rel).Funcs(templateFuncs).Parse(string(raw))
+			if err != nil {
+				return fmt.Errorf("unable to parse the project template %s: %w", rel, err)
+			}
+			var buf bytes.Buffer
+			if err = t.Execute(&buf, data); err != nil {
+				return fmt.Errorf("while processing the project template %s: %w", rel, err)
+			}
+			raw = buf.Bytes()
+		}
+		if _, err = that.fs.Stat(target); err == nil {
+			fmt.Printf("%s already exists, kept\n", target)
+			return nil
+		}
+		// root exists, the subdirectories of the template may not
+		if path.Dir(rel) != "." {
+			if err = os.MkdirAll(path.Dir(target), 0755); err != nil {
+				return err
+			}
+		}
+		if err = that.writeFile(target, raw, that.fileMode()); err != nil {
+			return fmt.Errorf("unable to write %s: %w", target, err)
+		}
+		fmt.Printf("%s written\n", target)
+		return nil
+	})
+}
@@ -0,0 +1,69 @@
+package lib
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProject(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"pkg.info":     "name: template\nversion: 0.1.0\ntenant: acme\nlicense: MIT\nrepo: https://github.com/acme/template\n",
+		"README.tpl":   "# {{ .Name }}\n",
+		"doc.go.tpl":   "// Package {{ .Package }} does things.\npackage {{ .Package }}\n",
+		".git/HEAD":    "ref: refs/heads/main\n",
+		"CONTRIBUTING": "{{ kept as is }}\n",
+	}
+	for name, content := range files {
+		_ = os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755)
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	src, cleanup, err := gopi.FetchProject(context.Background(), dir)
+	if err != nil || src != dir {
+		t.Fatal(err)
+	}
+	cleanup()
+	if err = gopi.ProjectDefaults(dir); err != nil {
+		t.Fatal(err)
+	}
+	if gopi.Name != "" || gopi.Repo != "" || gopi.Tenant != "acme" || gopi.License != "MIT" {
+		t.Fatal(gopi.Info)
+	}
+	gopi.Name = "my-lib"
+	fsys[path.Join(tRoot, "CONTRIBUTING")] = []byte("custom\n")
+	if err = gopi.InstantiateProject(context.Background(), dir, tRoot); err != nil {
+		t.Fatal(err)
+	}
+	if string(fsys[path.Join(tRoot, "README")]) != "# my-lib\n" || !strings.HasSuffix(string(fsys[path.Join(tRoot, "doc.go")]), "package mylib\n") {
+		t.Fatal(fsys)
+	}
+	if string(fsys[path.Join(tRoot, "CONTRIBUTING")]) != "custom\n" {
+		t.Fatal("existing file overwritten")
+	}
+	for _, f := range []string{"pkg.info", ".git/HEAD", "README.tpl"} {
+		if _, ok := fsys[path.Join(tRoot, f)]; ok {
+			t.Fatal(f)
+		}
+	}
+}
+
+func TestFetchProject_git(t *testing.T) {
+	gopi, _ := newTestClassRunner(memFS{}, fakeRunner{})
+	if _, _, err := gopi.FetchProject(context.Background(), "not/a/dir"); err == nil {
+		t.Fatal("expected an error")
+	}
+	// the clone fails, the fake runner knows no command; the source is never
+	// taken for an option
+	_, _, err := gopi.FetchProject(context.Background(), "https://github.com/acme/template.git")
+	if err == nil || !strings.Contains(err.Error(), "unable to clone") || !strings.Contains(err.Error(), "git clone --depth 1 -- https://github.com/acme/template.git ") {
+		t.Fatal(err)
+	}
+}
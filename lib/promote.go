@@ -0,0 +1,84 @@
+package lib
+
+import (
+	"fmt"
+	"gov/version"
+	"strings"
+)
+
+// channelOf is the channel of v on the configured ladder: the first prerelease
+// identifier, or the last channel for a release. "" when it is not a channel.
+func (that *Class) channelOf(v *version.Class) string {
+	ladder := that.config.Channels
+	if len(ladder) == 0 {
+		return ""
+	}
+	if v.Prerelease == "" {
+		return ladder[len(ladder)-1]
+	}
+	first, _, _ := strings.Cut(v.Prerelease, ".")
+	for _, c := range ladder[:len(ladder)-1] {
+		if c == first {
+			return c
+		}
+	}
+	return ""
+}
+
+// Promote moves the version to the next channel of the ladder, or to target
+// when given, which has to be further up: 1.2.0-beta.3 -> 1.2.0-rc.1 -> 1.2.0.
+// A release only moves with a target, which starts a new cycle of the (just
+// bumped) version: 1.3.0 -> 1.3.0-dev.1. The channel is recorded in the
+// package info. It returns the new version.
+func (that *Class) Promote(target string) (string, error) {
+	ladder := that.config.Channels
+	if len(ladder) == 0 {
+		return "", fmt.Errorf("no channels configured")
+	}
+	v, err := version.New(that.Version)
+	if err != nil {
+		return "", fmt.Errorf("the %s version %q can't be promoted: %s", that.config.PkgInfoFile, that.Version, err.Error())
+	}
+	from := -1
+	if c := that.channelOf(v); c != "" {
+		from = indexOf(ladder, c)
+	}
+	release := from == len(ladder)-1
+	if release {
+		if target == "" {
+			return "", fmt.Errorf("%s is already a %s release, bump it and start a new cycle with gopi promote <channel>", that.Version, ladder[from])
+		}
+		from = -1
+	}
+
+	to := from + 1
+	if target != "" {
+		if to = indexOf(ladder, target); to < 0 {
+			return "", fmt.Errorf("unknown channel %q, expected one of %s", target, strings.Join(ladder, ", "))
+		}
+		if to <= from || release && to == len(ladder)-1 {
+			return "", fmt.Errorf("%s is past the %s channel", that.Version, target)
+		}
+	}
+
+	v.Metadata = ""
+	v.Prerelease = ""
+	if to < len(ladder)-1 {
+		v.Prerelease = ladder[to] + ".1"
+	}
+	if _, err = version.New(v.String()); err != nil {
+		return "", fmt.Errorf("invalid channel %q: %s", ladder[to], err.Error())
+	}
+	that.Version = v.String()
+	that.Channel = ladder[to]
+	return that.Version, nil
+}
+
+func indexOf(s []string, str string) int {
+	for i, v := range s {
+		if v == str {
+			return i
+		}
+	}
+	return -1
+}
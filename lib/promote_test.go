@@ -0,0 +1,40 @@
+package lib
+
+import "testing"
+
+func TestPromote(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.config.Channels = []string{"dev", "alpha", "beta", "rc", "stable"}
+	gopi.Version = "1.2.0-alpha.3+build.7"
+	for _, want := range []string{"1.2.0-beta.1", "1.2.0-rc.1", "1.2.0"} {
+		if v, err := gopi.Promote(""); err != nil || v != want {
+			t.Fatal(v, err)
+		}
+	}
+	if gopi.Channel != "stable" {
+		t.Fail()
+	}
+	if _, err := gopi.Promote(""); err == nil {
+		t.Fail()
+	}
+	if _, err := gopi.Bump("minor"); err != nil || gopi.Channel != "stable" {
+		t.Fail()
+	}
+	if v, err := gopi.Promote("dev"); err != nil || v != "1.3.0-dev.1" || gopi.Channel != "dev" {
+		t.Fatal(v, err)
+	}
+}
+
+func TestPromote_target(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.config.Channels = []string{"dev", "alpha", "beta", "rc", "stable"}
+	gopi.Version = "2.0.0-beta.2"
+	for _, target := range []string{"alpha", "beta", "gamma"} {
+		if _, err := gopi.Promote(target); err == nil {
+			t.Fatal(target)
+		}
+	}
+	if v, err := gopi.Promote("stable"); err != nil || v != "2.0.0" {
+		t.Fatal(v, err)
+	}
+}
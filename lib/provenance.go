@@ -0,0 +1,72 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"gov/provenance"
+	"gov/sbom"
+	"path"
+	"time"
+)
+
+// builderID is the configured SLSA builder, else the CI run, else gopi.
+func (that *Class) builderID() string {
+	if that.config.Provenance.BuilderID != "" {
+		return that.config.Provenance.BuilderID
+	}
+	if c := that.CI(); c != nil && c.BuildURL != "" {
+		return c.BuildURL
+	}
+	return "gopi"
+}
+
+// WriteProvenance writes the SLSA provenance of the binaries built since
+// started to the configured file in their directory and returns its path,
+// "" when provenance is not configured.
+func (that *Class) WriteProvenance(ctx context.Context, root string, binaries []string, started time.Time) (string, error) {
+	file := that.config.Provenance.File
+	if file == "" || len(binaries) == 0 {
+		return "", nil
+	}
+
+	var subjects []provenance.Subject
+	for _, b := range binaries {
+		s, err := that.sum("sha256", b)
+		if err != nil {
+			return "", fmt.Errorf("unable to checksum %s: %w", b, err)
+		}
+		subjects = append(subjects, provenance.Subject{Name: path.Base(b), Digest: map[string]string{"sha256": s}})
+	}
+
+	var materials []provenance.Resource
+	if commit := that.commit(ctx, root); commit != "" && that.Repo != "" {
+		materials = append(materials, provenance.Resource{URI: "git+" + normalizeRepo(that.Repo), Digest: map[string]string{"gitCommit": commit}})
+	}
+	if out, err := that.runner.Run(ctx, root, "go", "list", "-m", "all"); err == nil {
+		for _, c := range sbom.ParseModules(out) {
+			materials = append(materials, provenance.Resource{URI: "pkg:golang/" + c.Path + "@" + c.Version})
+		}
+	}
+
+	params := map[string]interface{}{
+		"package": that.Name,
+		"version": that.Version,
+		"targets": that.buildTargets(),
+	}
+	if flags := that.ldflags(ctx, root); flags != nil {
+		params["ldflags"] = flags[1]
+	}
+	st := provenance.New(that.builderID(), subjects, params, materials, started, time.Now())
+	if c := that.CI(); c != nil {
+		st.Predicate.RunDetails.Metadata.InvocationID = c.RunID
+	}
+	raw, err := st.Marshal()
+	if err != nil {
+		return "", err
+	}
+	pth := path.Join(path.Dir(binaries[0]), file)
+	if err = that.fs.WriteFile(pth, raw, that.fileMode()); err != nil {
+		return "", fmt.Errorf("unable to write %s: %w", pth, err)
+	}
+	return pth, nil
+}
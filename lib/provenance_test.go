@@ -0,0 +1,39 @@
+package lib
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteProvenance(t *testing.T) {
+	bin := path.Join(tRoot, "dist", "demo_linux_amd64")
+	fsys := memFS{bin: []byte("elf")}
+	r := fakeRunner{"git rev-parse HEAD": "abc123\n", "go list -m all": "example.com/demo\ngolang.org/x/mod v0.8.0\n"}
+	gopi, _ := newTestClassRunner(fsys, r)
+	gopi.getenv = func(string) string { return "" }
+	gopi.Name, gopi.Version, gopi.Repo = "demo", "1.0.0", "git@github.com:acme/demo.git"
+	if pth, err := gopi.WriteProvenance(context.Background(), tRoot, []string{bin}, time.Now()); err != nil || pth != "" {
+		t.Fatal(pth, err)
+	}
+
+	gopi.config.Provenance.File = "provenance.intoto.json"
+	pth, err := gopi.WriteProvenance(context.Background(), tRoot, []string{bin}, time.Now())
+	if err != nil || pth != path.Join(tRoot, "dist", "provenance.intoto.json") {
+		t.Fatal(pth, err)
+	}
+	got := string(fsys[pth])
+	for _, want := range []string{
+		`"sha256": "780d84b20d7ae7e6292919399348bdbf96025270136198083fc8a4da398b5ca9"`,
+		`"uri": "git+https://github.com/acme/demo"`,
+		`"gitCommit": "abc123"`,
+		`"uri": "pkg:golang/golang.org/x/mod@v0.8.0"`,
+		`"id": "gopi"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatal(want, got)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package lib
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+func (that *Class) guessType(root string) string {
+	if _, err := that.fs.Stat(path.Join(root, "main.go")); err == nil {
+		return "cli"
+	}
+	return "library"
+}
+
+// quickStart renders the getting-started snippet matching the package type:
+// go install for CLIs, go get + import for libraries and docker run for services.
+func (that *Class) quickStart(modPath string) string {
+	var sb strings.Builder
+	switch that.Type {
+	case "cli":
+		if modPath == "" {
+			return ""
+		}
+		sb.WriteString(fmt.Sprintf("```sh\ngo install %s@latest\n%s --help\n```", modPath, that.binaryName()))
+	case "library":
+		if modPath == "" {
+			return ""
+		}
+		sb.WriteString(fmt.Sprintf("```sh\ngo get %s\n```\n\n```go\nimport \"%s\"\n```", modPath, modPath))
+	case "service":
+		image := strings.ToLower(that.Name)
+		if that.Tenant != "" {
+			image = strings.ToLower(that.Tenant) + "/" + image
+		}
+		tag := that.Version
+		if tag == "" {
+			tag = "latest"
+		}
+		sb.WriteString(fmt.Sprintf("```sh\ndocker run --rm %s:%s\n```", image, tag))
+	}
+	return sb.String()
+}
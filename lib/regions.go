@@ -0,0 +1,92 @@
+package lib
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var regionMarker = regexp.MustCompile(`<!-- gopi:(begin|end)(?: ([\w-]+))? -->`)
+
+// region is the content between the begin and end markers of a managed
+// region, named or not.
+type region struct {
+	name       string
+	start, end int
+}
+
+// regions lists the managed regions of md, in order.
+func regions(md string) ([]region, error) {
+	var res []region
+	var open *region
+	for _, m := range regionMarker.FindAllStringSubmatchIndex(md, -1) {
+		kind, name := md[m[2]:m[3]], ""
+		if m[4] >= 0 {
+			name = md[m[4]:m[5]]
+		}
+		switch {
+		case kind == "begin" && open == nil:
+			open = &region{name: name, start: m[1]}
+		case kind == "end" && open != nil && (name == "" || name == open.name):
+			open.end = m[0]
+			res = append(res, *open)
+			open = nil
+		default:
+			return nil, fmt.Errorf("unbalanced gopi:%s marker at offset %d", kind, m[0])
+		}
+	}
+	if open != nil {
+		return nil, fmt.Errorf("gopi:begin marker at offset %d is never closed", open.start)
+	}
+	return res, nil
+}
+
+// mergeRegions replaces the managed regions of the existing README with the
+// same regions of the rendered one, leaving everything else as written by
+// hand. Without regions in rendered, an unnamed region receives the whole
+// rendered README. existing is replaced entirely when it has no regions.
+func mergeRegions(existing string, rendered string) (string, error) {
+	current, err := regions(existing)
+	if err != nil || len(current) == 0 {
+		return rendered, err
+	}
+	fresh, err := regions(rendered)
+	if err != nil {
+		return "", err
+	}
+	content := map[string]string{}
+	for _, r := range fresh {
+		content[r.name] = rendered[r.start:r.end]
+	}
+	if len(fresh) == 0 {
+		content[""] = "\n" + strings.Trim(rendered, "\n") + "\n"
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, r := range current {
+		c, ok := content[r.name]
+		if !ok {
+			continue
+		}
+		sb.WriteString(existing[last:r.start])
+		sb.WriteString(c)
+		last = r.end
+	}
+	sb.WriteString(existing[last:])
+	return sb.String(), nil
+}
+
+// managedReadme merges rendered into the managed regions of the README at pth,
+// if it exists.
+func (that *Class) managedReadme(pth string, rendered []byte) ([]byte, error) {
+	existing, err := that.fs.ReadFile(pth)
+	if err != nil {
+		return rendered, nil
+	}
+	merged, err := mergeRegions(string(existing), string(rendered))
+	if err != nil {
+		return nil, fmt.Errorf("unable to update the managed regions of %s: %w", pth, err)
+	}
+	return []byte(merged), nil
+}
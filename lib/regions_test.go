@@ -0,0 +1,53 @@
+package lib
+
+import (
+	"context"
+	"path"
+	"testing"
+)
+
+func TestMergeRegions_unnamed(t *testing.T) {
+	existing := "# Curated\n<!-- gopi:begin -->\nold\n<!-- gopi:end -->\nHand written.\n"
+	got, err := mergeRegions(existing, "new\n")
+	if err != nil || got != "# Curated\n<!-- gopi:begin -->\nnew\n<!-- gopi:end -->\nHand written.\n" {
+		t.Fatalf("%q %v", got, err)
+	}
+	if got, _ = mergeRegions("plain\n", "new\n"); got != "new\n" {
+		t.Fail()
+	}
+}
+
+func TestMergeRegions_named(t *testing.T) {
+	existing := "<!-- gopi:begin badges -->old<!-- gopi:end -->\nmine\n<!-- gopi:begin license -->old<!-- gopi:end license -->\n<!-- gopi:begin other -->keep<!-- gopi:end -->"
+	rendered := "<!-- gopi:begin license -->MIT<!-- gopi:end -->\n<!-- gopi:begin badges -->b<!-- gopi:end -->\n"
+	got, err := mergeRegions(existing, rendered)
+	if err != nil || got != "<!-- gopi:begin badges -->b<!-- gopi:end -->\nmine\n<!-- gopi:begin license -->MIT<!-- gopi:end license -->\n<!-- gopi:begin other -->keep<!-- gopi:end -->" {
+		t.Fatalf("%q %v", got, err)
+	}
+}
+
+func TestMergeRegions_unbalanced(t *testing.T) {
+	for _, existing := range []string{"<!-- gopi:begin -->", "<!-- gopi:end -->", "<!-- gopi:begin a --><!-- gopi:end b -->"} {
+		if _, err := mergeRegions(existing, "new"); err == nil {
+			t.Fatal(existing)
+		}
+	}
+}
+
+func TestCreateReadme_managed(t *testing.T) {
+	pth := path.Join(tRoot, "README.md")
+	fsys := memFS{pth: []byte("Intro.\n<!-- gopi:begin -->\n<!-- gopi:end -->\nOutro.\n")}
+	gopi, _ := newTestClass(fsys)
+	gopi.config.AutoAccept = true
+	gopi.Name, gopi.Version = "demo", "1.0.0"
+	gopi.config.Tpl = "version {{ .Version }}\n"
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+	if string(fsys[pth]) != "Intro.\n<!-- gopi:begin -->\nversion 1.0.0\n<!-- gopi:end -->\nOutro.\n" {
+		t.Fatalf("%q", fsys[pth])
+	}
+	if stale, err := gopi.ReadmeStale(context.Background(), tRoot); err != nil || stale {
+		t.Fatal(stale, err)
+	}
+}
@@ -0,0 +1,18 @@
+package lib
+
+import (
+	"context"
+	"gov/pkginfo"
+	"gov/registry"
+)
+
+// Fetch retrieves the package info of a tenant/name[@version] identifier
+// from the configured registry, authenticated with $GOPI_REGISTRY_TOKEN when
+// it is set.
+func (that *Class) Fetch(ctx context.Context, id string) (*pkginfo.Info, error) {
+	ref, err := registry.ParseRef(id)
+	if err != nil {
+		return nil, err
+	}
+	return registry.New(that.config.Registry, that.getenv("GOPI_REGISTRY_TOKEN")).Fetch(ctx, ref)
+}
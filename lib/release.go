@@ -0,0 +1,126 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gov/version"
+	"path"
+	"strings"
+)
+
+type releaseStep struct {
+	desc string
+	run  func() error
+}
+
+// Release bumps part of the version and then updates the changelog and the
+// README, commits, tags and, with push, pushes the release. With dryRun it
+// only prints the plan. The files are written without review. It refuses to
+// run in a transaction, the commit would miss the staged files.
+func (that *Class) Release(ctx context.Context, root string, part string, push bool, dryRun bool, opts ...version.IncOption) error {
+	if _, staged := that.fs.(txFS); staged {
+		return errors.New("gopi release commits and tags the written files, it can't stage them in a workspace transaction")
+	}
+	if !that.inGit(ctx, root) {
+		return errors.New("gopi release needs a git repository")
+	}
+	if err := that.Guard(ctx, root); err != nil {
+		return err
+	}
+	if that.released != nil {
+		out, err := that.runner.Run(ctx, root, "git", "rev-parse", "--show-prefix")
+		if err != nil {
+			return fmt.Errorf("unable to locate the package in the repository: %w", err)
+		}
+		that.tagDir = strings.TrimSpace(string(out))
+	}
+	old := that.Version
+	if _, err := that.Bump(part, opts...); err != nil {
+		return err
+	}
+	tag := that.TagName()
+	that.config.AutoAccept = true
+	bumped := fmt.Sprintf("bump %s from %s to %s", that.config.PkgInfoFile, old, that.Version)
+	var moved, requires []string
+	for i, d := range that.Dependencies {
+		if v, ok := that.released[d.Path]; ok && v != d.Version {
+			that.Dependencies[i].Version = v
+			moved = append(moved, d.Path+" "+v)
+			requires = append(requires, "-require="+d.Path+"@"+v)
+		}
+	}
+	if len(moved) > 0 {
+		bumped += ", requiring " + strings.Join(moved, ", ")
+	}
+
+	files := []string{that.PkgFile(root), that.config.ReadmeFile}
+	var steps []releaseStep
+	if gates := that.gates(); len(gates) > 0 {
+		var commands []string
+		for _, g := range gates {
+			commands = append(commands, "go "+strings.Join(g.args, " "))
+		}
+		steps = append(steps, releaseStep{"run the quality gates: " + strings.Join(commands, ", "), func() error {
+			return that.Gate(ctx, root)
+		}})
+	}
+	steps = append(steps, releaseStep{bumped, func() error {
+		return that.CreatePkg(root)
+	}})
+	if _, err := that.fs.Stat(path.Join(root, "go.mod")); err == nil && len(requires) > 0 {
+		files = append(files, "go.mod")
+		steps = append(steps, releaseStep{"require " + strings.Join(moved, ", ") + " in go.mod", func() error {
+			if _, err := that.runner.Run(ctx, root, "go", append([]string{"mod", "edit"}, requires...)...); err != nil {
+				return fmt.Errorf("unable to update go.mod: %w", err)
+			}
+			return nil
+		}})
+	}
+	if that.config.ChangelogFile != "" {
+		files = append(files, that.config.ChangelogFile)
+		steps = append(steps, releaseStep{fmt.Sprintf("add the %s section to %s", tag, that.config.ChangelogFile), func() error {
+			return that.UpdateChangelog(ctx, root)
+		}})
+	}
+	steps = append(steps,
+		releaseStep{"regenerate " + that.config.ReadmeFile, func() error {
+			return that.CreateReadme(ctx, root, true)
+		}},
+		releaseStep{"commit " + strings.Join(files, ", "), func() error {
+			if _, err := that.runner.Run(ctx, root, "git", append([]string{"add", "--"}, files...)...); err != nil {
+				return fmt.Errorf("unable to stage the release: %w", err)
+			}
+			if _, err := that.runner.Run(ctx, root, "git", "commit", "-m", "Release "+tag); err != nil {
+				return fmt.Errorf("unable to commit the release: %w", err)
+			}
+			return nil
+		}},
+		releaseStep{"tag " + tag, func() error {
+			_, err := that.Tag(ctx, root, false)
+			return err
+		}},
+	)
+	if push {
+		steps = append(steps, releaseStep{"push the commit and " + tag, func() error {
+			if _, err := that.runner.Run(ctx, root, "git", "push", "--follow-tags"); err != nil {
+				return fmt.Errorf("unable to push the release: %w", err)
+			}
+			return nil
+		}})
+	}
+
+	for i, s := range steps {
+		fmt.Printf("%d/%d %s\n", i+1, len(steps), s.desc)
+		if dryRun {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.run(); err != nil {
+			return fmt.Errorf("release step %q failed: %w", s.desc, err)
+		}
+	}
+	return nil
+}
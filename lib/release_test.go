@@ -0,0 +1,156 @@
+package lib
+
+import (
+	"context"
+	"gov/txn"
+	"path"
+	"strings"
+	"testing"
+)
+
+func tReleaseRunner() fakeRunner {
+	return fakeRunner{
+		"git rev-parse --is-inside-work-tree":                 "true\n",
+		"git status --porcelain":                              "",
+		"git tag --list v*":                                   "v1.0.0\n",
+		"git log --reverse --pretty=format:%s v1.0.0..HEAD":   "Add deps\nFix build",
+		"git add -- /project/pkg.info README.md CHANGELOG.md": "",
+		"git commit -m Release v1.1.0":                        "",
+		"git tag -a v1.1.0 -m v1.1.0":                         "",
+	}
+}
+
+func TestRelease(t *testing.T) {
+	fsys := memFS{
+		path.Join(tRoot, "pkg.info"):     []byte("name: demo\nversion: 1.0.0\ntenant: acme\n"),
+		path.Join(tRoot, "CHANGELOG.md"): []byte("# Changelog\n\n## v1.0.0 - 2026-01-01\n\n- First\n"),
+	}
+	gopi, _ := newTestClassRunner(fsys, tReleaseRunner())
+	gopi.config.Tag.Prefix = "v"
+	gopi.config.ChangelogFile = "CHANGELOG.md"
+	if err := gopi.GetPackage(tRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := gopi.Release(context.Background(), tRoot, "minor", false, false); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(fsys[path.Join(tRoot, "pkg.info")]), "version: 1.1.0") {
+		t.Fail()
+	}
+	changelog := string(fsys[path.Join(tRoot, "CHANGELOG.md")])
+	if !strings.HasPrefix(changelog, "# Changelog\n\n## v1.1.0 - ") || !strings.Contains(changelog, "- Add deps\n- Fix build\n\n## v1.0.0") {
+		t.Fatal(changelog)
+	}
+	if _, ok := fsys[path.Join(tRoot, "README.md")]; !ok {
+		t.Fail()
+	}
+}
+
+func TestRelease_dry_run(t *testing.T) {
+	fsys := memFS{path.Join(tRoot, "pkg.info"): []byte("name: demo\nversion: 1.0.0\ntenant: acme\n")}
+	gopi, _ := newTestClassRunner(fsys, tReleaseRunner())
+	if err := gopi.GetPackage(tRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := gopi.Release(context.Background(), tRoot, "minor", true, true); err != nil {
+		t.Fatal(err)
+	}
+	if len(fsys) != 1 || !strings.Contains(string(fsys[path.Join(tRoot, "pkg.info")]), "version: 1.0.0") {
+		t.Fail()
+	}
+}
+
+func TestRelease_dirty(t *testing.T) {
+	r := fakeRunner{"git rev-parse --is-inside-work-tree": "true\n", "git status --porcelain": " M main.go\n"}
+	gopi, _ := newTestClassRunner(memFS{}, r)
+	gopi.config.Guards.CleanTree = true
+	gopi.Version = "1.0.0"
+	if err := gopi.Release(context.Background(), tRoot, "patch", false, true); err == nil {
+		t.Fail()
+	}
+}
+
+func TestRelease_gates(t *testing.T) {
+	pth := path.Join(tRoot, "pkg.info")
+	fsys := memFS{pth: []byte("name: demo\nversion: 1.0.0\ntenant: acme\n")}
+	r := tReleaseRunner()
+	r["go vet ./..."] = ""
+	gopi, _ := newTestClassRunner(fsys, r)
+	gopi.config.Gates.Vet, gopi.config.Gates.Test = true, true
+	if err := gopi.GetPackage(tRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := gopi.Release(context.Background(), tRoot, "minor", false, false); err == nil || !strings.Contains(err.Error(), "test") {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(fsys[pth]), "version: 1.0.0") {
+		t.Fatal("released despite a failed gate")
+	}
+
+	// a dry run lists the gates without running them
+	rec := &recordRunner{fakeRunner: tReleaseRunner()}
+	gopi, _ = newTestClassRunner(fsys, rec)
+	gopi.config.Gates.Vet = true
+	if err := gopi.GetPackage(tRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := gopi.Release(context.Background(), tRoot, "minor", false, true); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.calls) == 0 {
+		t.Fatal("no command recorded")
+	}
+	for _, c := range rec.calls {
+		if strings.HasPrefix(c, "go ") {
+			t.Fatal("a dry run ran", c)
+		}
+	}
+}
+
+func TestRelease_transaction(t *testing.T) {
+	fsys := memFS{path.Join(tRoot, "pkg.info"): []byte("name: demo\nversion: 1.0.0\ntenant: acme\n")}
+	gopi, _ := newTestClassRunner(fsys, tReleaseRunner())
+	gopi.fs = txFS{FS: fsys, tx: txn.New(t.TempDir())}
+	if err := gopi.GetPackage(tRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := gopi.Release(context.Background(), tRoot, "minor", false, false); err == nil || !strings.Contains(err.Error(), "transaction") {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(fsys[path.Join(tRoot, "pkg.info")]), "version: 1.0.0") {
+		t.Fail()
+	}
+}
+
+func TestRelease_released_dependencies(t *testing.T) {
+	fsys := memFS{
+		path.Join(tRoot, "pkg.info"): []byte("name: demo\nversion: 1.0.0\ntenant: acme\ndependencies:\n" +
+			"    - path: example.com/util\n      version: v0.1.0\n    - path: example.com/other\n      version: v2.0.0\n"),
+		path.Join(tRoot, "go.mod"): []byte("module example.com/demo\n"),
+	}
+	r := &recordRunner{fakeRunner: fakeRunner{
+		"git rev-parse --is-inside-work-tree":           "true\n",
+		"git status --porcelain":                        "",
+		"git rev-parse --show-prefix":                   "demo/\n",
+		"go mod edit -require=example.com/util@v0.2.0":  "",
+		"git add -- /project/pkg.info README.md go.mod": "",
+		"git commit -m Release demo/v1.1.0":             "",
+		"git tag -a demo/v1.1.0 -m demo/v1.1.0":         "",
+	}}
+	gopi, _ := newTestClassRunner(fsys, r)
+	gopi.config.Tag.Prefix = "v"
+	WithReleased(map[string]string{"example.com/util": "v0.2.0"})(gopi)
+	if err := gopi.GetPackage(tRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := gopi.Release(context.Background(), tRoot, "minor", false, false); err != nil {
+		t.Fatal(err)
+	}
+	got := string(fsys[path.Join(tRoot, "pkg.info")])
+	if !strings.Contains(got, "path: example.com/util\n      version: v0.2.0") || !strings.Contains(got, "version: v2.0.0") {
+		t.Fatal(got)
+	}
+	if !strings.Contains(strings.Join(r.calls, "\n"), "go mod edit -require=example.com/util@v0.2.0") {
+		t.Fatal(r.calls)
+	}
+}
@@ -0,0 +1,107 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"gov/inventory"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// ReportFormats are the formats a Report is written in.
+var ReportFormats = []string{"table", "csv", "json"}
+
+// Report is a table of rows keyed by column, the output of gopi list and
+// gopi inventory query.
+type Report struct {
+	// Columns are the available columns, Default the ones shown unless others
+	// are selected.
+	Columns []string
+	Default []string
+	Rows    []map[string]string
+}
+
+// Write writes the columns of the report, the default ones when empty, as an
+// aligned table, CSV or a JSON array of objects, the keys in column order.
+func (that Report) Write(w io.Writer, format string, columns []string) error {
+	if len(columns) == 0 {
+		columns = that.Default
+	}
+	for _, c := range columns {
+		if !contains(that.Columns, c) {
+			return fmt.Errorf("unknown column %q, expected one of %s", c, strings.Join(that.Columns, ", "))
+		}
+	}
+	switch format {
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
+		for _, row := range that.Rows {
+			fmt.Fprintln(tw, strings.Join(that.values(row, columns), "\t"))
+		}
+		return tw.Flush()
+	case "csv":
+		cw := csv.NewWriter(w)
+		_ = cw.Write(columns)
+		for _, row := range that.Rows {
+			_ = cw.Write(that.values(row, columns))
+		}
+		cw.Flush()
+		return cw.Error()
+	case "json":
+		var buf bytes.Buffer
+		buf.WriteString("[")
+		for i, row := range that.Rows {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n  {")
+			for j, c := range columns {
+				if j > 0 {
+					buf.WriteString(", ")
+				}
+				k, _ := json.Marshal(c)
+				v, _ := json.Marshal(row[c])
+				fmt.Fprintf(&buf, "%s: %s", k, v)
+			}
+			buf.WriteString("}")
+		}
+		if len(that.Rows) > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("]\n")
+		_, err := w.Write(buf.Bytes())
+		return err
+	}
+	return fmt.Errorf("unknown format %q, expected one of %s", format, strings.Join(ReportFormats, ", "))
+}
+
+func (that Report) values(row map[string]string, columns []string) []string {
+	res := make([]string, len(columns))
+	for i, c := range columns {
+		res[i] = row[c]
+	}
+	return res
+}
+
+// ListingReport is the report of gopi list.
+func ListingReport(found []Listing) Report {
+	res := Report{Columns: []string{"name", "version", "tenant", "path", "error"}, Default: []string{"name", "version", "tenant", "path"}}
+	for _, l := range found {
+		res.Rows = append(res.Rows, map[string]string{"name": l.Name, "version": l.Version, "tenant": l.Tenant, "path": l.Path, "error": l.Error})
+	}
+	return res
+}
+
+// InventoryReport is the report of gopi inventory query.
+func InventoryReport(found []inventory.Entry) Report {
+	res := Report{Columns: []string{"name", "version", "tenant", "path", "scanned"}, Default: []string{"name", "version", "tenant", "path", "scanned"}}
+	for _, e := range found {
+		res.Rows = append(res.Rows, map[string]string{"name": e.Name, "version": e.Version, "tenant": e.Tenant, "path": e.Path, "scanned": e.Scanned.Format(time.RFC3339)})
+	}
+	return res
+}
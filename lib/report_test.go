@@ -0,0 +1,58 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func tReport() Report {
+	return ListingReport([]Listing{{Name: "api", Version: "1.0.0", Tenant: "acme", Path: "services/api"},
+		{Name: "cli, tools", Version: "0.1.0", Tenant: "globex", Path: "."}})
+}
+
+func TestReport_table(t *testing.T) {
+	var buf bytes.Buffer
+	if err := tReport().Write(&buf, "table", nil); err != nil {
+		t.Fatal(err)
+	}
+	want := "NAME        VERSION  TENANT  PATH\napi         1.0.0    acme    services/api\ncli, tools  0.1.0    globex  .\n"
+	if buf.String() != want {
+		t.Fatal(buf.String())
+	}
+}
+
+func TestReport_csv(t *testing.T) {
+	var buf bytes.Buffer
+	if err := tReport().Write(&buf, "csv", []string{"tenant", "name"}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "tenant,name\nacme,api\nglobex,\"cli, tools\"\n" {
+		t.Fatal(buf.String())
+	}
+}
+
+func TestReport_json(t *testing.T) {
+	var buf bytes.Buffer
+	if err := tReport().Write(&buf, "json", []string{"version", "name"}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[\n  {\"version\": \"1.0.0\", \"name\": \"api\"},\n  {\"version\": \"0.1.0\", \"name\": \"cli, tools\"}\n]\n" {
+		t.Fatal(buf.String())
+	}
+	var rows []map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil || len(rows) != 2 {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := ListingReport(nil).Write(&buf, "json", nil); err != nil || buf.String() != "[]\n" {
+		t.Fatal(buf.String())
+	}
+}
+
+func TestReport_errors(t *testing.T) {
+	var buf bytes.Buffer
+	if tReport().Write(&buf, "xml", nil) == nil || tReport().Write(&buf, "csv", []string{"scanned"}) == nil {
+		t.Fail()
+	}
+}
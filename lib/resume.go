@@ -0,0 +1,95 @@
+package lib
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// remover is implemented by the file systems able to delete a file.
+type remover interface {
+	Remove(name string) error
+}
+
+// dirMaker is implemented by the file systems with directories to create.
+type dirMaker interface {
+	MkdirAll(name string, perm os.FileMode) error
+}
+
+// privateWriter is implemented by the file systems able to write a file
+// only the user can read, see osFS.WritePrivate.
+type privateWriter interface {
+	WritePrivate(name string, data []byte) error
+}
+
+// statePath is the file keeping the answers of an init of root in progress,
+// one per project directory, in the user cache directory (the .gopi
+// directory of root without one).
+func (that *Class) statePath(root string) string {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		abs = root
+	}
+	sum := sha256.Sum256([]byte(abs))
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(abs, ".gopi", "init.json")
+	}
+	return filepath.Join(dir, "gopi", "init", fmt.Sprintf("%x.json", sum[:8]))
+}
+
+// loadState returns the answers saved by an interrupted init of root, none
+// when there is no usable state.
+func (that *Class) loadState(root string) map[string]string {
+	raw, err := that.fs.ReadFile(that.statePath(root))
+	if err != nil {
+		return nil
+	}
+	state := map[string]string{}
+	if json.Unmarshal(raw, &state) != nil {
+		return nil
+	}
+	return state
+}
+
+// saveState records the answers given so far. It is best effort: failing to
+// save only loses the ability to resume.
+func (that *Class) saveState(root string, state map[string]string) {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+	if w, ok := that.fs.(privateWriter); ok {
+		_ = w.WritePrivate(that.statePath(root), raw)
+	} else {
+		_ = that.fs.WriteFile(that.statePath(root), raw, 0600)
+	}
+}
+
+// clearState forgets the answers of root once the init is over.
+func (that *Class) clearState(root string) {
+	if r, ok := that.fs.(remover); ok {
+		_ = r.Remove(that.statePath(root))
+	}
+}
+
+// resumeState offers to resume an interrupted init of root, returning the
+// answers to reuse.
+func (that *Class) resumeState(root string) (map[string]string, error) {
+	saved := that.loadState(root)
+	if len(saved) == 0 {
+		return map[string]string{}, nil
+	}
+	label := fmt.Sprintf("An interrupted init of %s left %d answers. Resume where it left off? ( y/yes to confirm): ", root, len(saved))
+	resume, err := that.confirm(label)
+	if err != nil {
+		return nil, err
+	}
+	if !resume {
+		that.clearState(root)
+		return map[string]string{}, nil
+	}
+	return saved, nil
+}
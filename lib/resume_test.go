@@ -0,0 +1,76 @@
+package lib
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPromptPkg_resume(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys, "demo", "1.2.0", "A demo.", "", "acme")
+	if err := gopi.PromptPkg(context.Background(), tRoot, false); err == nil {
+		t.Fatal("expected the init to be interrupted")
+	}
+	if len(gopi.loadState(tRoot)) != 5 {
+		t.Fatal(gopi.loadState(tRoot))
+	}
+
+	gopi, p := newTestClass(fsys, "y", "", "cli", "", "", "")
+	if err := gopi.PromptPkg(context.Background(), tRoot, false); err != nil {
+		t.Fatal(err)
+	}
+	if gopi.Name != "demo" || gopi.Description != "A demo." || gopi.Tenant != "acme" || gopi.Type != "cli" || len(p.asked) != 6 {
+		t.Fatal(gopi.Info, p.asked)
+	}
+	if _, ok := fsys[path.Join(tRoot, "pkg.info")]; !ok {
+		t.Fatal("pkg.info not written")
+	}
+	if gopi.loadState(tRoot) != nil {
+		t.Fatal("state kept after init")
+	}
+}
+
+func TestPromptPkg_resume_declined(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys, "demo")
+	_ = gopi.PromptPkg(context.Background(), tRoot, false)
+
+	gopi, _ = newTestClass(fsys, "n", "other", "1.0.0", "", "", "acme", "", "cli", "", "", "")
+	if err := gopi.PromptPkg(context.Background(), tRoot, false); err != nil {
+		t.Fatal(err)
+	}
+	if gopi.Name != "other" {
+		t.Fatal(gopi.Name)
+	}
+}
+
+func TestSaveState_private(t *testing.T) {
+	cache := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cache)
+	t.Setenv("HOME", cache)
+	gopi, _ := newTestClass(memFS{})
+	gopi.fs = osFS{}
+	pth := gopi.statePath(tRoot)
+	if !strings.HasPrefix(pth, cache) {
+		t.Skip("no user cache directory in", cache)
+	}
+	// a link planted in place of the state is replaced, not followed
+	victim := filepath.Join(t.TempDir(), "victim")
+	_ = os.WriteFile(victim, []byte("keep"), 0644)
+	_ = os.MkdirAll(filepath.Dir(pth), 0700)
+	if err := os.Symlink(victim, pth); err != nil {
+		t.Skip(err)
+	}
+	gopi.saveState(tRoot, map[string]string{"name": "demo"})
+	if raw, _ := os.ReadFile(victim); string(raw) != "keep" {
+		t.Fatal(string(raw))
+	}
+	fi, err := os.Lstat(pth)
+	if err != nil || fi.Mode()&os.ModeSymlink != 0 || fi.Mode().Perm() != 0600 || gopi.loadState(tRoot)["name"] != "demo" {
+		t.Fatal(fi, err)
+	}
+}
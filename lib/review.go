@@ -0,0 +1,148 @@
+package lib
+
+import (
+	"bytes"
+	"fmt"
+	"gov/diff"
+	"os"
+	"path"
+	"strings"
+)
+
+const reviewHelp = `y - apply this hunk
+n - keep the current lines
+a - apply this and all remaining hunks
+d - keep the current lines for this and all remaining hunks
+e - edit this hunk in $EDITOR
+`
+
+// fileMode is the configured permission of generated files, 0644 by default.
+func (that *Class) fileMode() os.FileMode {
+	if that.config.FileMode == 0 {
+		return 0644
+	}
+	return os.FileMode(that.config.FileMode)
+}
+
+// WriteOutput writes a file asked for on the command line, like gopi sbom -o,
+// with the configured fileMode.
+func (that *Class) WriteOutput(pth string, data []byte) error {
+	if err := that.fs.WriteFile(pth, data, that.fileMode()); err != nil {
+		return fmt.Errorf("unable to write %s: %w", pth, err)
+	}
+	return nil
+}
+
+// confirm asks the user to confirm label, yes without asking when assumeYes
+// is configured.
+func (that *Class) confirm(label string) (bool, error) {
+	if that.config.AssumeYes {
+		fmt.Println(label + "yes")
+		return true, nil
+	}
+	return that.prompter.Confirm(label)
+}
+
+func (that *Class) autoAccept() bool {
+	return that.config.AutoAccept || that.getenv("CI") != ""
+}
+
+// writeFile writes a generated file. When it replaces an existing file the
+// changes are shown as a colored diff and applied hunk by hunk, unless
+// auto-accept is configured (or running in CI).
+func (that *Class) writeFile(pth string, data []byte, perm os.FileMode) error {
+	if err := that.checkLimits(pth, data); err != nil {
+		return err
+	}
+	old, err := that.fs.ReadFile(pth)
+	if err == nil && bytes.Equal(old, data) {
+		fmt.Printf("%s is up to date.\n", pth)
+		return nil
+	}
+	if err == nil && !that.autoAccept() {
+		d := diff.New(old, data)
+		data, err = that.review(pth, d)
+		if err != nil {
+			return err
+		}
+	}
+	if old != nil && that.config.Backup.Enabled && !bytes.Equal(old, data) {
+		backup := that.backupPath(pth)
+		if err = that.mkdirAll(path.Dir(backup)); err == nil {
+			err = that.fs.WriteFile(backup, old, perm)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to back up %s: %w", pth, err)
+		}
+	}
+	if err = that.fs.WriteFile(pth, data, perm); err != nil {
+		return err
+	}
+	that.written++
+	return nil
+}
+
+// checkLimits guards against template bugs producing huge outputs or
+// writing an unexpected number of files.
+func (that *Class) checkLimits(pth string, data []byte) error {
+	limits := that.config.Limits
+	if limits.MaxFileSize > 0 && int64(len(data)) > limits.MaxFileSize {
+		return fmt.Errorf("%s would be %d bytes, over the %d bytes limit; check the template or raise limits.maxFileSize in .gopi.yaml",
+			pth, len(data), limits.MaxFileSize)
+	}
+	if limits.MaxFiles > 0 && that.written >= limits.MaxFiles {
+		return fmt.Errorf("refusing to write %s, this run already wrote %d files; check the command or raise limits.maxFiles in .gopi.yaml",
+			pth, that.written)
+	}
+	return nil
+}
+
+func (that *Class) review(pth string, d *diff.Class) ([]byte, error) {
+	color := that.getenv("NO_COLOR") == ""
+	valid := func(st string) bool {
+		switch strings.TrimSpace(st) {
+		case "y", "n", "a", "d", "e", "?":
+			return true
+		}
+		return false
+	}
+
+	fmt.Printf("Changes to %s:\n", pth)
+	all := ""
+	for i := 0; i < len(d.Hunks); i++ {
+		h := d.Hunks[i]
+		if all != "" {
+			h.Accepted = all == "a"
+			continue
+		}
+
+		fmt.Print(d.Render(h, color))
+		label := fmt.Sprintf("(%d/%d) Apply this hunk to %s [y,n,a,d,e,?]? ", i+1, len(d.Hunks), pth)
+		answer, err := that.prompter.Prompt(label, "", valid)
+		if err != nil {
+			return nil, err
+		}
+		switch answer {
+		case "y":
+			h.Accepted = true
+		case "a", "d":
+			all = answer
+			h.Accepted = all == "a"
+		case "e":
+			edited, err := that.prompter.Edit(strings.Join(d.Lines(h), ""))
+			if err != nil {
+				fmt.Printf("Unable to edit the hunk: %s\n", err.Error())
+				i--
+				continue
+			}
+			h.Replacement = strings.SplitAfter(edited, "\n")
+			if h.Replacement[len(h.Replacement)-1] == "" {
+				h.Replacement = h.Replacement[:len(h.Replacement)-1]
+			}
+		case "?":
+			fmt.Print(reviewHelp)
+			i--
+		}
+	}
+	return d.Apply(), nil
+}
@@ -0,0 +1,73 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"gov/sbom"
+	"time"
+)
+
+func (that *Class) sbomDocument(root string) *sbom.Document {
+	return &sbom.Document{
+		Name:    that.Name,
+		Module:  that.goModule(root),
+		Version: that.Version,
+		Type:    that.Type,
+		License: that.License,
+		Repo:    that.Repo,
+		Created: time.Now(),
+	}
+}
+
+// SBOM describes the module in root and every module of its build list, as
+// selected by `go list -m all`, in the given format.
+func (that *Class) SBOM(ctx context.Context, root string, format string) ([]byte, error) {
+	out, err := that.runner.Run(ctx, root, "go", "list", "-m", "all")
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the modules of %s: %w", root, err)
+	}
+	doc := that.sbomDocument(root)
+	doc.Components = sbom.ParseModules(out)
+	return sbom.Marshal(doc, format)
+}
+
+// BinarySBOM describes a built binary from the module information go embeds
+// in it, which only lists the modules actually linked.
+func (that *Class) BinarySBOM(root string, pth string, format string) ([]byte, error) {
+	raw, err := that.fs.ReadFile(pth)
+	if err != nil {
+		return nil, err
+	}
+	doc := that.sbomDocument(root)
+	var module string
+	module, _, doc.Components, err = sbom.FromBinary(raw)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the build info of %s: %w", pth, err)
+	}
+	if module != "" {
+		doc.Module = module
+	}
+	return sbom.Marshal(doc, format)
+}
+
+// WriteBinarySBOMs writes <binary>.<format>.json next to each binary when
+// buildSBOM is configured. It returns the written paths.
+func (that *Class) WriteBinarySBOMs(root string, binaries []string) ([]string, error) {
+	format := that.config.BuildSBOM
+	if format == "" {
+		return nil, nil
+	}
+	var written []string
+	for _, b := range binaries {
+		raw, err := that.BinarySBOM(root, b, format)
+		if err != nil {
+			return written, err
+		}
+		pth := b + "." + format + ".json"
+		if err = that.fs.WriteFile(pth, raw, that.fileMode()); err != nil {
+			return written, fmt.Errorf("unable to write %s: %w", pth, err)
+		}
+		written = append(written, pth)
+	}
+	return written, nil
+}
@@ -0,0 +1,41 @@
+package lib
+
+import (
+	"context"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestSBOM(t *testing.T) {
+	fsys := memFS{path.Join(tRoot, "go.mod"): []byte("module example.com/demo\n")}
+	r := fakeRunner{"go list -m all": "example.com/demo\ngolang.org/x/mod v0.8.0\n"}
+	gopi, _ := newTestClassRunner(fsys, r)
+	gopi.Name, gopi.Version = "demo", "1.0.0"
+	raw, err := gopi.SBOM(context.Background(), tRoot, "spdx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), `"referenceLocator": "pkg:golang/example.com/demo@1.0.0"`) ||
+		!strings.Contains(string(raw), `"name": "golang.org/x/mod"`) {
+		t.Fatal(string(raw))
+	}
+}
+
+func TestWriteOutput(t *testing.T) {
+	dir := t.TempDir()
+	gopi, _ := newTestClass(memFS{})
+	gopi.fs = osFS{}
+	gopi.config.FileMode = 0600
+	pth := path.Join(dir, "sbom.json")
+	if err := gopi.WriteOutput(pth, []byte("{}\n")); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Stat(pth); err != nil || fi.Mode().Perm() != 0600 {
+		t.Fatal(fi, err)
+	}
+	if gopi.WriteOutput(path.Join(dir, "missing", "sbom.json"), nil) == nil {
+		t.Fail()
+	}
+}
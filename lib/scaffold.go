@@ -0,0 +1,119 @@
+package lib
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+var notIdent = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// goIdent turns name into a go package name: my-tool is mytool.
+func goIdent(name string) string {
+	id := notIdent.ReplaceAllString(strings.ToLower(name), "")
+	if id == "" || (id[0] >= '0' && id[0] <= '9') {
+		id = "pkg" + id
+	}
+	return id
+}
+
+// scaffoldTemplate is the scaffold template kind (main, library or
+// gitignore): the file or url configured in scaffold, else the embedded one.
+func (that *Class) scaffoldTemplate(ctx context.Context, root string, kind string, embedded string) (string, error) {
+	if src := that.config.Scaffold[kind]; src != "" {
+		return that.loadTemplate(ctx, root, src)
+	}
+	raw, err := fs.ReadFile(that.config.Templates, path.Join("templates", "scaffold", embedded))
+	return string(raw), err
+}
+
+// scaffoldData is what the scaffold and project templates are rendered with.
+func (that *Class) scaffoldData() map[string]interface{} {
+	buildDir := that.config.BuildDir
+	if path.IsAbs(buildDir) || strings.HasPrefix(buildDir, "~") || strings.HasPrefix(buildDir, "..") {
+		buildDir = ""
+	}
+	return map[string]interface{}{
+		"Name":        that.Name,
+		"Version":     that.Version,
+		"Description": that.Description,
+		"Summary":     summarize(that.Description, that.config.SummaryLength),
+		"Tenant":      that.Tenant,
+		"Repo":        that.Repo,
+		"License":     that.License,
+		"Type":        that.Type,
+		"Package":     goIdent(that.Name),
+		"BinaryName":  that.binaryName(),
+		"BuildDir":    buildDir,
+	}
+}
+
+// Scaffold makes the package in root a project that compiles: a go.mod, a
+// main.go (a <name>.go library skeleton for libraries), a .gitignore, the
+// LICENSE and a Makefile. Existing files are kept.
+func (that *Class) Scaffold(ctx context.Context, root string) error {
+	if that.goModule(root) == "" {
+		module := strings.TrimPrefix(strings.TrimPrefix(normalizeRepo(that.Repo), "https://"), "http://")
+		if module == "" {
+			module = that.Name
+		}
+		if _, err := that.runner.Run(ctx, root, "go", "mod", "init", module); err != nil {
+			return fmt.Errorf("unable to create go.mod: %w", err)
+		}
+		fmt.Printf("go.mod written for module %s\n", module)
+	}
+
+	kind, file := "main", "main.go"
+	if that.Type == "library" {
+		kind, file = "library", goIdent(that.Name)+".go"
+	}
+	data := that.scaffoldData()
+	for _, f := range [][3]string{{kind, kind + ".go.tpl", file}, {"gitignore", "gitignore.tpl", ".gitignore"}} {
+		pth := path.Join(root, f[2])
+		if _, err := that.fs.Stat(pth); err == nil {
+			fmt.Printf("%s already exists, kept\n", pth)
+			continue
+		}
+		text, err := that.scaffoldTemplate(ctx, root, f[0], f[1])
+		if err != nil {
+			return fmt.Errorf("unable to load the %s scaffold template: %w", f[0], err)
+		}
+		tpl, err := template.New(f[0]).Funcs(templateFuncs).Parse(text)
+		if err != nil {
+			return fmt.Errorf("unable to parse the %s scaffold template: %w", f[0], err)
+		}
+		var buf bytes.Buffer
+		if err = tpl.Execute(&buf, data); err != nil {
+			return fmt.Errorf("while processing the %s scaffold template: %w", f[0], err)
+		}
+		out := buf.Bytes()
+		if path.Ext(pth) == ".go" {
+			if out, err = format.Source(out); err != nil {
+				return fmt.Errorf("the %s scaffold template produced invalid go code: %w", f[0], err)
+			}
+		}
+		if err = that.writeFile(pth, out, that.fileMode()); err != nil {
+			return fmt.Errorf("unable to write %s: %w", pth, err)
+		}
+		fmt.Printf("%s written to %s\n", f[2], pth)
+	}
+
+	if that.License != "" {
+		if _, err := that.fs.Stat(path.Join(root, "LICENSE")); err != nil {
+			if err = that.CreateLicense(ctx, root, false); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := that.fs.Stat(that.generatedPath(root, "makefile")); err == nil {
+		fmt.Printf("%s already exists, kept\n", that.generatedPath(root, "makefile"))
+		return nil
+	}
+	return that.Generate(ctx, root, "makefile", false)
+}
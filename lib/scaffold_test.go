@@ -0,0 +1,50 @@
+package lib
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestScaffold(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClassRunner(fsys, fakeRunner{"go mod init github.com/acme/demo": ""})
+	gopi.Name, gopi.Version, gopi.Tenant, gopi.Type, gopi.License = "demo", "1.0.0", "acme", "cli", "MIT"
+	gopi.Description = "A demo tool. It does things."
+	gopi.Repo = "git@github.com:acme/demo.git"
+	if err := gopi.Scaffold(context.Background(), tRoot); err != nil {
+		t.Fatal(err)
+	}
+	main := string(fsys[path.Join(tRoot, "main.go")])
+	if !strings.HasPrefix(main, "// Command demo: A demo tool.\npackage main\n") || !strings.Contains(main, `"demo 1.0.0"`) {
+		t.Fatal(main)
+	}
+	if !strings.Contains(string(fsys[path.Join(tRoot, ".gitignore")]), "/bin/\n") {
+		t.Fatal(string(fsys[path.Join(tRoot, ".gitignore")]))
+	}
+	for _, f := range []string{"LICENSE", "Makefile"} {
+		if _, ok := fsys[path.Join(tRoot, f)]; !ok {
+			t.Fatal(f)
+		}
+	}
+
+	// existing files are kept, a library gets a skeleton
+	fsys[path.Join(tRoot, "go.mod")] = []byte("module github.com/acme/demo\n")
+	fsys[path.Join(tRoot, ".gitignore")] = []byte("custom\n")
+	gopi.Type, gopi.Name = "library", "my-lib"
+	gopi.config.Scaffold = map[string]string{"library": "tpl/lib.tpl"}
+	fsys[path.Join(tRoot, "tpl", "lib.tpl")] = []byte("package {{ .Package }}\n")
+	if err := gopi.Scaffold(context.Background(), tRoot); err != nil {
+		t.Fatal(err)
+	}
+	if string(fsys[path.Join(tRoot, "mylib.go")]) != "package mylib\n" || string(fsys[path.Join(tRoot, ".gitignore")]) != "custom\n" {
+		t.Fatal(string(fsys[path.Join(tRoot, "mylib.go")]))
+	}
+}
+
+func TestGoIdent(t *testing.T) {
+	if goIdent("My-Tool") != "mytool" || goIdent("3d") != "pkg3d" || goIdent("--") != "pkg" {
+		t.Fail()
+	}
+}
@@ -0,0 +1,60 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gov/config"
+)
+
+// signer is the signing configuration of the package tenant, nil when its
+// artifacts are not signed.
+func (that *Class) signer() *config.Signer {
+	for _, key := range []string{that.Tenant, "*"} {
+		if s, ok := that.config.Signing[key]; ok && s.Method != "" {
+			return &s
+		}
+	}
+	return nil
+}
+
+// Sign writes a detached signature next to each file, <file>.sig, plus the
+// signing certificate <file>.pem for keyless cosign. It returns the written
+// paths, none when the tenant has no signing configured. It refuses to sign
+// in a transaction, the files are only staged and not on disk yet.
+func (that *Class) Sign(ctx context.Context, root string, files []string) ([]string, error) {
+	s := that.signer()
+	if s == nil {
+		return nil, nil
+	}
+	if _, staged := that.fs.(txFS); staged {
+		return nil, errors.New("the checksums, SBOMs and provenance are only staged in a workspace run, build without -all to sign them")
+	}
+	var written []string
+	for _, f := range files {
+		var args []string
+		outputs := []string{f + ".sig"}
+		switch s.Method {
+		case "gpg":
+			args = []string{"--batch", "--yes", "--detach-sign", "--output", f + ".sig"}
+			if s.Key != "" {
+				args = append(args, "--local-user", s.Key)
+			}
+		case "cosign":
+			args = []string{"sign-blob", "--yes", "--output-signature", f + ".sig"}
+			if s.Key != "" {
+				args = append(args, "--key", s.Key)
+			} else {
+				args = append(args, "--output-certificate", f+".pem")
+				outputs = append(outputs, f+".pem")
+			}
+		default:
+			return written, fmt.Errorf("unknown signing method %q for tenant %s, expected gpg or cosign", s.Method, that.Tenant)
+		}
+		if _, err := that.runner.Run(ctx, root, s.Method, append(args, f)...); err != nil {
+			return written, fmt.Errorf("unable to sign %s with %s: %w", f, s.Method, err)
+		}
+		written = append(written, outputs...)
+	}
+	return written, nil
+}
@@ -0,0 +1,35 @@
+package lib
+
+import (
+	"context"
+	"gov/config"
+	"gov/txn"
+	"testing"
+)
+
+func TestSign(t *testing.T) {
+	r := fakeRunner{
+		"gpg --batch --yes --detach-sign --output /dist/a.sig --local-user ABCD /dist/a":                 "",
+		"cosign sign-blob --yes --output-signature /dist/a.sig --output-certificate /dist/a.pem /dist/a": "",
+	}
+	gopi, _ := newTestClassRunner(memFS{}, r)
+	gopi.Tenant = "acme"
+	if sigs, err := gopi.Sign(context.Background(), tRoot, []string{"/dist/a"}); err != nil || sigs != nil {
+		t.Fatal(sigs, err)
+	}
+
+	gopi.config.Signing = map[string]config.Signer{"acme": {Method: "gpg", Key: "ABCD"}, "*": {Method: "cosign"}}
+	if sigs, err := gopi.Sign(context.Background(), tRoot, []string{"/dist/a"}); err != nil || len(sigs) != 1 {
+		t.Fatal(sigs, err)
+	}
+	gopi.Tenant = "other"
+	if sigs, err := gopi.Sign(context.Background(), tRoot, []string{"/dist/a"}); err != nil || len(sigs) != 2 || sigs[1] != "/dist/a.pem" {
+		t.Fatal(sigs, err)
+	}
+
+	// the staged files of a transaction can't be signed
+	gopi.fs = txFS{FS: memFS{}, tx: txn.New(t.TempDir())}
+	if sigs, err := gopi.Sign(context.Background(), tRoot, []string{"/dist/a"}); err == nil || sigs != nil {
+		t.Fatal(sigs, err)
+	}
+}
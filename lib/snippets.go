@@ -0,0 +1,47 @@
+package lib
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+var snippetExt = []string{"", ".md", ".tpl", ".txt"}
+
+func (that *Class) snippetsDir(root string) string {
+	return resolveDir(root, that.config.SnippetsDir)
+}
+
+// resolveDir expands a leading ~/ and makes dir relative to root.
+func resolveDir(root string, dir string) string {
+	if strings.HasPrefix(dir, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = path.Join(home, dir[2:])
+		}
+	}
+	if !path.IsAbs(dir) {
+		dir = path.Join(root, dir)
+	}
+	return dir
+}
+
+// snippet loads a shared, centrally maintained text block by name from the
+// configured snippets directory, e.g. {{ snippet "support" }} -> support.md
+func (that *Class) snippet(root string, name string) (string, error) {
+	if that.config.SnippetsDir == "" {
+		return "", fmt.Errorf("snippet %q requested but no snippetsDir is configured", name)
+	}
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid snippet name %q", name)
+	}
+
+	dir := that.snippetsDir(root)
+	for _, ext := range snippetExt {
+		raw, err := that.fs.ReadFile(path.Join(dir, name+ext))
+		if err == nil {
+			return strings.TrimRight(string(raw), "\n"), nil
+		}
+	}
+	return "", fmt.Errorf("snippet %q not found in %s", name, dir)
+}
@@ -1,13 +1,26 @@
 package lib
 
-import "gov/config"
+import (
+	"gov/config"
+	"gov/pkginfo"
+	"gov/validator"
+)
 
 type Class struct {
-	Name        string   `yaml:"name"`
-	Version     string   `yaml:"version"`
-	Description string   `yaml:"description"`
-	Tenant      string   `yaml:"tenant"`
-	Repo        string   `yaml:"repo"`
-	Arch        []string `yaml:"arch"`
-	config      config.Class
+	pkginfo.Info  `yaml:",inline"`
+	config        config.Class
+	fs            FS
+	prompter      Prompter
+	runner        Runner
+	getenv        func(string) string
+	loaded        int
+	written       int
+	stored        pkginfo.Info
+	overrides     map[string]string
+	platforms     []string
+	validators    *validator.Class
+	tenantList    []string
+	tenantsLoaded bool
+	released      map[string]string
+	tagDir        string
 }
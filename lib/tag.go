@@ -0,0 +1,61 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"gov/version"
+	"strings"
+	"text/template"
+)
+
+// tagPrefix starts the version tags of the package: the configured prefix,
+// after the package directory in a workspace release (api/v).
+func (that *Class) tagPrefix() string {
+	return that.tagDir + that.config.Tag.Prefix
+}
+
+// TagName is the git tag of the package version, e.g. v1.2.0.
+func (that *Class) TagName() string {
+	return that.tagPrefix() + that.Version
+}
+
+func (that *Class) tagMessage(tag string) (string, error) {
+	tpl, err := template.New("tag").Option("missingkey=error").Parse(that.config.Tag.Message)
+	if err != nil {
+		return "", fmt.Errorf("invalid tag message template: %w", err)
+	}
+	var sb strings.Builder
+	err = tpl.Execute(&sb, map[string]string{"Name": that.Name, "Version": that.Version, "Tenant": that.Tenant, "Tag": tag})
+	if err != nil {
+		return "", fmt.Errorf("while processing the tag message template: %w", err)
+	}
+	if strings.TrimSpace(sb.String()) == "" {
+		return tag, nil
+	}
+	return sb.String(), nil
+}
+
+// Tag creates an annotated git tag for the package version at the HEAD of
+// root. An existing tag is only moved with force. It returns the tag name.
+func (that *Class) Tag(ctx context.Context, root string, force bool) (string, error) {
+	if _, err := version.New(that.Version); err != nil {
+		return "", fmt.Errorf("the %s version %q can't be tagged: %s", that.config.PkgInfoFile, that.Version, err.Error())
+	}
+	tag := that.TagName()
+	msg, err := that.tagMessage(tag)
+	if err != nil {
+		return "", err
+	}
+	if _, err = that.runner.Run(ctx, root, "git", "rev-parse", "-q", "--verify", "refs/tags/"+tag); err == nil && !force {
+		return "", fmt.Errorf("tag %s already exists, use -force to move it", tag)
+	}
+
+	args := []string{"tag", "-a", tag, "-m", msg}
+	if force {
+		args = append(args, "-f")
+	}
+	if _, err = that.runner.Run(ctx, root, "git", args...); err != nil {
+		return "", fmt.Errorf("unable to create the tag %s: %w", tag, err)
+	}
+	return tag, nil
+}
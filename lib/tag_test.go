@@ -0,0 +1,29 @@
+package lib
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTag(t *testing.T) {
+	r := fakeRunner{"git tag -a v1.2.0 -m demo v1.2.0": ""}
+	gopi, _ := newTestClassRunner(memFS{}, r)
+	gopi.Name, gopi.Version = "demo", "1.2.0"
+	gopi.config.Tag.Prefix = "v"
+	gopi.config.Tag.Message = "{{ .Name }} {{ .Tag }}"
+	if tag, err := gopi.Tag(context.Background(), tRoot, false); err != nil || tag != "v1.2.0" {
+		t.Fatal(tag, err)
+	}
+}
+
+func TestTag_exists(t *testing.T) {
+	r := fakeRunner{"git rev-parse -q --verify refs/tags/1.2.0": "abc123\n", "git tag -a 1.2.0 -m 1.2.0 -f": ""}
+	gopi, _ := newTestClassRunner(memFS{}, r)
+	gopi.Version = "1.2.0"
+	if _, err := gopi.Tag(context.Background(), tRoot, false); err == nil {
+		t.Fail()
+	}
+	if _, err := gopi.Tag(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,77 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// defaultSections are the README sections of a package declaring a readme
+// without listing its sections, the layout of the embedded README template.
+var defaultSections = []string{"header", "badges", "description", "usage", "architecture", "dependencies", "maintainers", "license"}
+
+// readmeTemplate is the README template: the sections of the readme in
+// pkg.info when declared, else the configured readmeTemplate file, relative to
+// root, or url when set and the embedded one otherwise.
+func (that *Class) readmeTemplate(ctx context.Context, root string) (string, error) {
+	if that.Readme != nil {
+		return that.composeReadme(ctx, root)
+	}
+	if that.config.ReadmeTemplate == "" {
+		return that.config.Tpl, nil
+	}
+	text, err := that.loadTemplate(ctx, root, that.config.ReadmeTemplate)
+	if err != nil {
+		return "", fmt.Errorf("unable to load the README template: %w", err)
+	}
+	return text, nil
+}
+
+// loadTemplate reads the template file src, relative to root, or downloads it
+// when src is an http(s) url.
+func (that *Class) loadTemplate(ctx context.Context, root string, src string) (string, error) {
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		raw, err := download(ctx, src)
+		return string(raw), err
+	}
+	raw, err := that.fs.ReadFile(resolveDir(root, src))
+	return string(raw), err
+}
+
+// readmeSection is the template of the named README section: the file
+// overriding it in pkg.info, else the embedded one.
+func (that *Class) readmeSection(ctx context.Context, root string, name string) (string, error) {
+	if src := that.Readme.Templates[name]; src != "" {
+		text, err := that.loadTemplate(ctx, root, src)
+		if err != nil {
+			return "", fmt.Errorf("unable to load the %s README section: %w", name, err)
+		}
+		return text, nil
+	}
+	raw, err := fs.ReadFile(that.config.Templates, path.Join("templates", "readme", name+".tpl"))
+	if err != nil {
+		return "", fmt.Errorf("unknown README section %q, add a template for it to readme.templates in pkg.info", name)
+	}
+	return string(raw), nil
+}
+
+// composeReadme assembles the README template from the sections of the
+// readme in pkg.info, each defined as its own template and executed in order.
+func (that *Class) composeReadme(ctx context.Context, root string) (string, error) {
+	sections := that.Readme.Sections
+	if len(sections) == 0 {
+		sections = defaultSections
+	}
+	var defs, body strings.Builder
+	for _, name := range sections {
+		text, err := that.readmeSection(ctx, root, name)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&defs, "{{ define %q }}%s{{ end }}", "section/"+name, text)
+		fmt.Fprintf(&body, "{{ template %q . }}", "section/"+name)
+	}
+	return defs.String() + body.String(), nil
+}
@@ -0,0 +1,107 @@
+package lib
+
+import (
+	"context"
+	"gov/pkginfo"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestCreateReadme_template_file(t *testing.T) {
+	fsys := memFS{path.Join(tRoot, "docs", "readme.tpl"): []byte("custom {{ upper .Name }}\n")}
+	gopi, _ := newTestClass(fsys)
+	gopi.Name = "demo"
+	gopi.config.ReadmeTemplate = "docs/readme.tpl"
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+	if string(fsys[path.Join(tRoot, "README.md")]) != "custom DEMO\n" {
+		t.Fail()
+	}
+}
+
+func TestCreateReadme_template_url(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/readme.tpl" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("remote {{ .Version }}\n"))
+	}))
+	defer srv.Close()
+
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	gopi.Version = "1.0.0"
+	gopi.config.ReadmeTemplate = srv.URL + "/readme.tpl"
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+	if string(fsys[path.Join(tRoot, "README.md")]) != "remote 1.0.0\n" {
+		t.Fail()
+	}
+	gopi.config.ReadmeTemplate = srv.URL + "/missing.tpl"
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateReadme_sections_default(t *testing.T) {
+	tpl, err := os.ReadFile("../readme.tpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	gopi.Name, gopi.Version, gopi.Description, gopi.License = "demo", "1.0.0", "A demo package.", "MIT"
+	gopi.Maintainers = []pkginfo.Maintainer{{Name: "Jane", Email: "jane@acme.io"}}
+	gopi.config.Tpl = string(tpl)
+	if err = gopi.CreateReadme(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+	monolithic := string(fsys[path.Join(tRoot, "README.md")])
+
+	gopi.Readme = &pkginfo.Readme{}
+	if err = gopi.CreateReadme(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(fsys[path.Join(tRoot, "README.md")]); got != monolithic {
+		t.Fatalf("%q\n%q", got, monolithic)
+	}
+}
+
+func TestCreateReadme_sections(t *testing.T) {
+	fsys := memFS{path.Join(tRoot, "docs", "usage.tpl"): []byte("Run {{ upper .Name }}.\n")}
+	gopi, _ := newTestClass(fsys)
+	gopi.Name, gopi.License = "demo", "MIT"
+	gopi.Readme = &pkginfo.Readme{Sections: []string{"license", "usage"}, Templates: map[string]string{"usage": "docs/usage.tpl"}}
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+	if string(fsys[path.Join(tRoot, "README.md")]) != "\n## License\n\nMIT\n\nRun DEMO.\n" {
+		t.Fatalf("%q", fsys[path.Join(tRoot, "README.md")])
+	}
+
+	gopi.Readme.Sections = append(gopi.Readme.Sections, "faq")
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err == nil || !strings.Contains(err.Error(), `"faq"`) {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateReadmeAt(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	gopi.Name = "demo"
+	gopi.config.Tpl = "# {{ .Name }}\n"
+	pth := path.Join(tRoot, "docs", "README.md")
+	if err := gopi.CreateReadmeAt(context.Background(), tRoot, pth, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fsys[path.Join(tRoot, "README.md")]; ok || string(fsys[pth]) != "# demo\n" {
+		t.Fail()
+	}
+}
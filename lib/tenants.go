@@ -0,0 +1,66 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"gov/validator"
+	"os"
+	"strings"
+)
+
+// loadTenants fetches the tenants listed at tenantsURL, a yaml (or json) list
+// or one tenant per line, once. When the fetch fails it warns and keeps to
+// the tenants of config.yaml.
+func (that *Class) loadTenants(ctx context.Context) {
+	if that.config.TenantsURL == "" || that.tenantsLoaded {
+		return
+	}
+	that.tenantsLoaded = true
+	that.tenantList = that.config.Tenants
+	raw, err := download(ctx, that.config.TenantsURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to fetch the tenant list, using the configured tenants: %s\n", err.Error())
+		return
+	}
+	var listed []string
+	if yaml.Unmarshal(raw, &listed) != nil {
+		listed = nil
+		for _, line := range strings.Split(string(raw), "\n") {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				listed = append(listed, line)
+			}
+		}
+	}
+	that.tenantList = append(append([]string{}, that.config.Tenants...), listed...)
+}
+
+// tenants are the valid tenants: the tenants of config.yaml and, once
+// loadTenants ran, the ones listed at tenantsURL. It is nil when neither is
+// configured, any tenant being valid then.
+func (that *Class) tenants() []string {
+	if that.tenantsLoaded {
+		return that.tenantList
+	}
+	return that.config.Tenants
+}
+
+// validTenant rejects a blank tenant, and one missing from the tenant list
+// suggesting the closest known tenant.
+func (that *Class) validTenant(st string) error {
+	if err := validator.Required(st); err != nil {
+		return err
+	}
+	list := that.tenants()
+	if len(list) == 0 {
+		return nil
+	}
+	st = strings.TrimSpace(st)
+	if contains(list, st) {
+		return nil
+	}
+	if s := validator.Suggest(st, list); s != "" {
+		return fmt.Errorf("%q is not a known tenant, did you mean %q?", st, s)
+	}
+	return fmt.Errorf("%q is not a known tenant, expected one of %s", st, strings.Join(list, ", "))
+}
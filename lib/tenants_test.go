@@ -0,0 +1,84 @@
+package lib
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidTenant(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	if gopi.validTenant("anyone") != nil || gopi.validTenant(" ") == nil {
+		t.Fail()
+	}
+	gopi.config.Tenants = []string{"acme", "globex"}
+	if gopi.validTenant("acme") != nil {
+		t.Fail()
+	}
+	if err := gopi.validTenant("acne"); err == nil || err.Error() != `"acne" is not a known tenant, did you mean "acme"?` {
+		t.Fatal(err)
+	}
+	if err := gopi.validTenant("umbrella"); err == nil || !strings.Contains(err.Error(), "expected one of acme, globex") {
+		t.Fatal(err)
+	}
+}
+
+func TestTenants_url(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte("# tenants\ninitech\n\numbrella\n"))
+	}))
+	defer srv.Close()
+
+	gopi, _ := newTestClass(memFS{})
+	gopi.config.Tenants = []string{"acme"}
+	gopi.config.TenantsURL = srv.URL
+	gopi.loadTenants(context.Background())
+	if list := gopi.tenants(); strings.Join(list, ",") != "acme,initech,umbrella" {
+		t.Fatal(list)
+	}
+	gopi.loadTenants(context.Background())
+	if calls != 1 {
+		t.Fail()
+	}
+
+	// a failed fetch is not retried, the configured tenants are used
+	gopi, _ = newTestClass(memFS{})
+	gopi.config.Tenants = []string{"acme"}
+	gopi.config.TenantsURL = srv.URL + "/missing\x7f"
+	gopi.loadTenants(context.Background())
+	if gopi.validTenant("acme") != nil || gopi.validTenant("initech") == nil || !gopi.tenantsLoaded {
+		t.Fail()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	gopi, _ = newTestClass(memFS{})
+	gopi.config.TenantsURL = srv.URL
+	gopi.loadTenants(ctx)
+	if calls != 1 || gopi.tenants() != nil {
+		t.Fail()
+	}
+}
+
+func TestValidate_tenant(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.config.Tenants = []string{"acme"}
+	gopi.Name, gopi.Version, gopi.Tenant = "demo", "1.0.0", "acmee"
+	d := gopi.Validate(context.Background())
+	if len(d) != 1 || d[0].Field != "tenant" || d[0].Code != "unknown" || !strings.Contains(d[0].Message, `did you mean "acme"`) {
+		t.Fatal(d)
+	}
+}
+
+func TestInitPkg_tenant(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.config.Tenants = []string{"acme"}
+	err := gopi.InitPkg(context.Background(), tRoot, map[string]string{"name": "demo", "version": "1.0.0", "tenant": "acne"}, false)
+	if err == nil || !strings.Contains(err.Error(), `did you mean "acme"`) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,82 @@
+package lib
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// tocStart and tocEnd enclose the table of contents of the README, written by
+// the toc template helper and filled in after rendering.
+const (
+	tocStart = "<!-- toc -->"
+	tocEnd   = "<!-- /toc -->"
+)
+
+var atxHeading = regexp.MustCompile(`^(#{2,4})\s+(.+?)\s*#*\s*$`)
+
+// toc marks where the table of contents goes, e.g. {{ toc }}.
+func toc() string {
+	return tocStart + "\n" + tocEnd
+}
+
+// anchor is the GitHub anchor of a heading: lower case, punctuation dropped
+// and spaces turned into dashes.
+func anchor(heading string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			sb.WriteRune(r)
+		case r == ' ':
+			sb.WriteRune('-')
+		}
+	}
+	return sb.String()
+}
+
+// insertTOC fills the table of contents between the toc markers of md with
+// the level 2 to 4 headings that follow it, outside of code blocks. md is
+// returned as is when it has no markers.
+func insertTOC(md string) string {
+	start := strings.Index(md, tocStart)
+	if start < 0 {
+		return md
+	}
+	end := strings.Index(md[start:], tocEnd)
+	if end < 0 {
+		return md
+	}
+	end += start
+
+	var sb strings.Builder
+	seen := map[string]int{}
+	fence := ""
+	for _, line := range strings.Split(md[end:], "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		m := atxHeading.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		id := anchor(m[2])
+		if n := seen[id]; n > 0 {
+			seen[id]++
+			id = fmt.Sprintf("%s-%d", id, n)
+		} else {
+			seen[id] = 1
+		}
+		fmt.Fprintf(&sb, "%s- [%s](#%s)\n", strings.Repeat("  ", len(m[1])-2), m[2], id)
+	}
+	return md[:start] + tocStart + "\n" + sb.String() + md[end:]
+}
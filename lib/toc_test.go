@@ -0,0 +1,32 @@
+package lib
+
+import (
+	"context"
+	"path"
+	"testing"
+)
+
+func TestInsertTOC(t *testing.T) {
+	md := "# Tool\n" + tocStart + "\nstale\n" + tocEnd + "\n## Quick start\n```sh\n## not a heading\n```\n### Build & test\n## Quick start\n"
+	want := "# Tool\n" + tocStart + "\n- [Quick start](#quick-start)\n  - [Build & test](#build--test)\n- [Quick start](#quick-start-1)\n" +
+		tocEnd + "\n## Quick start\n```sh\n## not a heading\n```\n### Build & test\n## Quick start\n"
+	if got := insertTOC(md); got != want {
+		t.Fatalf("%q", got)
+	}
+	if insertTOC("## A\n") != "## A\n" {
+		t.Fail()
+	}
+}
+
+func TestCreateReadme_toc(t *testing.T) {
+	fsys := memFS{}
+	gopi, _ := newTestClass(fsys)
+	gopi.License = "MIT"
+	gopi.config.Tpl = "{{ toc }}\n## License\n\n{{ .License }}\n"
+	if err := gopi.CreateReadme(context.Background(), tRoot, true); err != nil {
+		t.Fatal(err)
+	}
+	if string(fsys[path.Join(tRoot, "README.md")]) != tocStart+"\n- [License](#license)\n"+tocEnd+"\n## License\n\nMIT\n" {
+		t.Fatalf("%q", fsys[path.Join(tRoot, "README.md")])
+	}
+}
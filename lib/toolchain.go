@@ -0,0 +1,117 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"gov/platform"
+	"runtime"
+	"strings"
+)
+
+// ToolCheck is the outcome of one toolchain prerequisite for a build target.
+type ToolCheck struct {
+	Target string
+	Tool   string
+	OK     bool
+	Detail string
+	Hint   string
+}
+
+var zigArch = map[string]string{"amd64": "x86_64", "arm64": "aarch64", "386": "x86", "arm": "arm"}
+
+var zigOS = map[string]string{"linux": "linux-gnu", "darwin": "macos", "windows": "windows-gnu"}
+
+// localArch is the running platform as goos/goarch, or as the archList entry
+// naming it when one is configured (linux_amd64, windows).
+func (that *Class) localArch() string {
+	target := runtime.GOOS + "/" + runtime.GOARCH
+	for _, a := range that.config.ArchList {
+		if platform.Canonical(a) == target {
+			return a
+		}
+	}
+	return target
+}
+
+// archList is what architectures are validated against: the configured
+// archList when set, otherwise every platform of `go tool dist list`, or of
+// the embedded snapshot when no go toolchain is available.
+func (that *Class) archList() []string {
+	if len(that.config.ArchList) > 0 {
+		return that.config.ArchList
+	}
+	if that.platforms == nil {
+		out, err := that.runner.Run(context.Background(), "", "go", "tool", "dist", "list")
+		if that.platforms = platform.Parse(out); err != nil || len(that.platforms) == 0 {
+			that.platforms = platform.Known()
+		}
+	}
+	return that.platforms
+}
+
+// VerifyToolchain checks that everything needed to build the package for its
+// architectures is installed: the go toolchain, a C cross compiler for each
+// foreign target when cgo is enabled and docker (buildx for several linux
+// targets) for services.
+func (that *Class) VerifyToolchain(ctx context.Context, root string) []ToolCheck {
+	var res []ToolCheck
+
+	out, err := that.runner.Run(ctx, root, "go", "env", "GOHOSTOS", "GOHOSTARCH")
+	host := strings.Fields(string(out))
+	if err != nil || len(host) != 2 {
+		return append(res, ToolCheck{Target: "host", Tool: "go",
+			Hint: "install Go from https://go.dev/dl/ and make sure it is on PATH"})
+	}
+	hostTarget := host[0] + "_" + host[1]
+	res = append(res, ToolCheck{Target: "host", Tool: "go", OK: true, Detail: hostTarget})
+
+	cgo := that.getenv("CGO_ENABLED") == "1"
+	_, zigErr := that.runner.Run(ctx, root, "zig", "version")
+	for _, target := range that.Platforms() {
+		goos, goarch := platform.Split(target)
+		// an arch entry may turn cgo on or off for its target
+		cgoTarget := cgo
+		if t := that.Target(target); t.CGO != nil {
+			cgoTarget = *t.CGO
+		}
+		if !cgoTarget || goos+"_"+goarch == hostTarget {
+			continue
+		}
+		check := ToolCheck{Target: target, Tool: "cc"}
+		ccVar := fmt.Sprintf("CC_FOR_%s_%s", goos, goarch)
+		switch {
+		case that.getenv(ccVar) != "":
+			check.OK, check.Detail = true, that.getenv(ccVar)
+		case zigErr == nil && zigArch[goarch] != "" && zigOS[goos] != "":
+			check.Hint = fmt.Sprintf("zig is installed, set %s=\"zig cc -target %s-%s\"", ccVar, zigArch[goarch], zigOS[goos])
+		default:
+			check.Hint = fmt.Sprintf("cgo is enabled: install a C cross compiler for %s/%s (zig is the simplest, https://ziglang.org) and set %s, or build with CGO_ENABLED=0", goos, goarch, ccVar)
+		}
+		res = append(res, check)
+	}
+
+	if that.Type == "service" {
+		out, err = that.runner.Run(ctx, root, "docker", "version", "--format", "{{.Server.Version}}")
+		check := ToolCheck{Target: "container", Tool: "docker", OK: err == nil, Detail: strings.TrimSpace(string(out))}
+		if err != nil {
+			check.Hint = "install docker (https://docs.docker.com/get-docker/) and make sure the daemon is running"
+		}
+		res = append(res, check)
+
+		linux := 0
+		for _, target := range that.Platforms() {
+			if goos, _ := platform.Split(target); goos == "linux" {
+				linux++
+			}
+		}
+		if linux > 1 {
+			_, err = that.runner.Run(ctx, root, "docker", "buildx", "version")
+			check = ToolCheck{Target: "container", Tool: "buildx", OK: err == nil}
+			if err != nil {
+				check.Hint = "multi-arch images need docker buildx: https://docs.docker.com/build/install-buildx/"
+			}
+			res = append(res, check)
+		}
+	}
+	return res
+}
@@ -0,0 +1,45 @@
+package lib
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestVerifyToolchain_no_go(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	res := gopi.VerifyToolchain(context.Background(), tRoot)
+	if len(res) != 1 || res[0].OK || res[0].Tool != "go" {
+		t.Fail()
+	}
+}
+
+func TestVerifyToolchain_cross_cgo(t *testing.T) {
+	r := fakeRunner{"go env GOHOSTOS GOHOSTARCH": "linux\namd64\n", "zig version": "0.11.0"}
+	gopi, _ := newTestClassRunner(memFS{}, r)
+	gopi.getenv = func(k string) string {
+		return map[string]string{"CGO_ENABLED": "1", "CC_FOR_darwin_arm64": "o64-clang"}[k]
+	}
+	gopi.Arch = []string{"linux_amd64", "linux_arm64", "darwin_arm64"}
+	res := gopi.VerifyToolchain(context.Background(), tRoot)
+	if len(res) != 3 {
+		t.Fatal(res)
+	}
+	if res[1].Target != "linux_arm64" || res[1].OK || !strings.Contains(res[1].Hint, "zig cc -target aarch64-linux-gnu") {
+		t.Fail()
+	}
+	if res[2].Target != "darwin_arm64" || !res[2].OK {
+		t.Fail()
+	}
+}
+
+func TestVerifyToolchain_service_docker(t *testing.T) {
+	r := fakeRunner{"go env GOHOSTOS GOHOSTARCH": "linux amd64", "docker version --format {{.Server.Version}}": "24.0.7\n"}
+	gopi, _ := newTestClassRunner(memFS{}, r)
+	gopi.Type = "service"
+	gopi.Arch = []string{"linux_amd64", "linux_arm64"}
+	res := gopi.VerifyToolchain(context.Background(), tRoot)
+	if len(res) != 3 || !res[1].OK || res[1].Detail != "24.0.7" || res[2].Tool != "buildx" || res[2].OK {
+		t.Fatal(res)
+	}
+}
@@ -0,0 +1,69 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"gov/platform"
+)
+
+// Compression is the outcome of compressing one binary with UPX.
+type Compression struct {
+	Target string
+	Binary string
+	Before int64
+	After  int64
+	Err    error
+}
+
+func (that Compression) String() string {
+	if that.Err != nil {
+		return fmt.Sprintf("%s: %s", that.Binary, that.Err.Error())
+	}
+	saved := 0.0
+	if that.Before > 0 {
+		saved = 100 * float64(that.Before-that.After) / float64(that.Before)
+	}
+	return fmt.Sprintf("%s: %d -> %d bytes (-%.1f%%)", that.Binary, that.Before, that.After, saved)
+}
+
+func (that *Class) fileSize(pth string) int64 {
+	fi, err := that.fs.Stat(pth)
+	if err != nil {
+		return 0
+	}
+	return fi.Size()
+}
+
+// Compress runs UPX on the binaries of the successful builds whose target is
+// listed in the upx configuration, when upx is installed.
+func (that *Class) Compress(ctx context.Context, results []BuildResult) []Compression {
+	var picked []BuildResult
+	for _, r := range results {
+		if r.Err == nil && platform.Contains(that.config.UPX.Targets, r.Target) {
+			picked = append(picked, r)
+		}
+	}
+	if len(picked) == 0 {
+		return nil
+	}
+	if _, err := that.runner.Run(ctx, "", "upx", "--version"); err != nil {
+		fmt.Println("upx is not installed, the binaries are not compressed.")
+		return nil
+	}
+	flags := that.config.UPX.Flags
+	if len(flags) == 0 {
+		flags = []string{"--best"}
+	}
+
+	var res []Compression
+	for _, r := range picked {
+		c := Compression{Target: r.Target, Binary: r.Binary, Before: that.fileSize(r.Binary)}
+		args := append(append([]string{"-q"}, flags...), r.Binary)
+		if _, err := that.runner.Run(ctx, "", "upx", args...); err != nil {
+			c.Err = fmt.Errorf("upx failed: %w", err)
+		}
+		c.After = that.fileSize(r.Binary)
+		res = append(res, c)
+	}
+	return res
+}
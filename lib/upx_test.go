@@ -0,0 +1,40 @@
+package lib
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCompress(t *testing.T) {
+	fsys := memFS{"/project/dist/demo_linux_arm64": []byte("elf-binary"), "/project/dist/demo_darwin_arm64": []byte("macho")}
+	results := []BuildResult{
+		{Target: "linux/arm64", Binary: "/project/dist/demo_linux_arm64"},
+		{Target: "darwin/arm64", Binary: "/project/dist/demo_darwin_arm64"},
+		{Target: "linux/amd64", Binary: "/project/dist/demo_linux_amd64", Err: context.Canceled},
+	}
+	gopi, _ := newTestClassRunner(fsys, fakeRunner{})
+	gopi.config.UPX.Targets = []string{"linux_arm64", "linux/amd64"}
+	if res := gopi.Compress(context.Background(), results); res != nil {
+		t.Fatal("compressed without upx", res)
+	}
+
+	r := fakeRunner{"upx --version": "upx 4.2.1", "upx -q --best /project/dist/demo_linux_arm64": ""}
+	gopi, _ = newTestClassRunner(fsys, r)
+	gopi.config.UPX.Targets = []string{"linux_arm64", "linux/amd64"}
+	res := gopi.Compress(context.Background(), results)
+	if len(res) != 1 || res[0].Err != nil || res[0].Before != 10 || res[0].Target != "linux/arm64" {
+		t.Fatal(res)
+	}
+
+	gopi.config.UPX.Flags = []string{"--lzma"}
+	if res = gopi.Compress(context.Background(), results); len(res) != 1 || res[0].Err == nil {
+		t.Fatal(res)
+	}
+}
+
+func TestCompression_String(t *testing.T) {
+	c := Compression{Binary: "demo", Before: 200, After: 50}
+	if c.String() != "demo: 200 -> 50 bytes (-75.0%)" {
+		t.Fatal(c.String())
+	}
+}
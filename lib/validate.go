@@ -0,0 +1,29 @@
+package lib
+
+import (
+	"context"
+	"gov/pkginfo"
+	"strings"
+)
+
+// Diagnostic is a single problem found in the package info.
+type Diagnostic = pkginfo.Diagnostic
+
+// Validate checks the loaded package info and reports every problem at once,
+// the tenant against the configured tenant list too.
+func (that *Class) Validate(ctx context.Context) []Diagnostic {
+	res := pkginfo.Validate(&that.Info, that.archList()...)
+	if strings.TrimSpace(that.Tenant) != "" {
+		that.loadTenants(ctx)
+		if err := that.validTenant(that.Tenant); err != nil {
+			res = append(res, Diagnostic{Field: "tenant", Code: "unknown", Message: err.Error()})
+			pkginfo.SortDiagnostics(res)
+		}
+	}
+	return res
+}
+
+// Set assigns a package info field from its string form, see pkginfo.SetField.
+func (that *Class) Set(field string, value string) error {
+	return pkginfo.SetField(&that.Info, field, value, that.archList()...)
+}
@@ -0,0 +1,125 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"github.com/fsnotify/fsnotify"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// watched are the files the README of root is generated from: the package
+// info and the local template files, the urls are not watched.
+func (that *Class) watched(root string) []string {
+	srcs := []string{that.config.ReadmeTemplate}
+	if that.Readme != nil {
+		for _, src := range that.Readme.Templates {
+			srcs = append(srcs, src)
+		}
+	}
+	res := []string{that.pkgPath(root)}
+	for _, src := range srcs {
+		if src != "" && !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+			res = append(res, resolveDir(root, src))
+		}
+	}
+	sort.Strings(res[1:])
+	return res
+}
+
+// snapshot is the content of the watched files, missing ones being empty.
+func (that *Class) snapshot(files []string) map[string]string {
+	res := map[string]string{}
+	for _, f := range files {
+		raw, _ := that.fs.ReadFile(f)
+		res[f] = string(raw)
+	}
+	return res
+}
+
+// WatchReadme regenerates the README of the package in root every time the
+// package info or a template file changes, once no change came for debounce,
+// until ctx is done. A broken template is reported and watched for a fix.
+func (that *Class) WatchReadme(ctx context.Context, root string, debounce time.Duration) error {
+	// nobody reviews the changes of every save
+	that.config.AutoAccept = true
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("unable to watch %s: %w", root, err)
+	}
+	defer w.Close()
+
+	// the directories are watched, editors replace the files they save
+	var last map[string]string
+	var files []string
+	dirs := map[string]bool{}
+	refresh := func() {
+		files = that.watched(root)
+		for _, f := range files {
+			if dir := path.Dir(f); !dirs[dir] {
+				if err := w.Add(dir); err != nil {
+					fmt.Fprintf(os.Stderr, "unable to watch %s: %s\n", dir, err.Error())
+					continue
+				}
+				dirs[dir] = true
+			}
+		}
+		if now := that.snapshot(files); !sameSnapshot(last, now) {
+			last = now
+			if err := that.regenerateReadme(ctx, root); err != nil {
+				fmt.Fprintln(os.Stderr, err.Error())
+			} else {
+				fmt.Fprintf(os.Stderr, "%s regenerated, watching %s\n", that.config.ReadmeFile, strings.Join(files, ", "))
+			}
+		}
+	}
+	refresh()
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev := <-w.Events:
+			if contains(files, path.Clean(ev.Name)) {
+				timer.Reset(debounce)
+			}
+		case err := <-w.Errors:
+			fmt.Fprintln(os.Stderr, err.Error())
+		case <-timer.C:
+			refresh()
+		}
+	}
+}
+
+// regenerateReadme reloads the package info and writes the README again.
+func (that *Class) regenerateReadme(ctx context.Context, root string) error {
+	content, err := that.fs.ReadFile(that.pkgPath(root))
+	if err == nil {
+		err = that.unmarshalPkg(content)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to load %s: %w", that.pkgPath(root), err)
+	}
+	that.applyEnv()
+	// every regeneration is a run of its own for limits.maxFiles
+	that.written = 0
+	return that.CreateReadme(ctx, root, true)
+}
+
+func sameSnapshot(a map[string]string, b map[string]string) bool {
+	if a == nil || len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package lib
+
+import (
+	"context"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatched(t *testing.T) {
+	gopi, _ := newTestClass(memFS{})
+	gopi.config.ReadmeTemplate = "https://example.com/readme.tpl"
+	if got := strings.Join(gopi.watched(tRoot), " "); got != "/project/pkg.info" {
+		t.Fatal(got)
+	}
+	gopi.config.ReadmeTemplate = "docs/readme.tpl"
+	if got := strings.Join(gopi.watched(tRoot), " "); got != "/project/pkg.info /project/docs/readme.tpl" {
+		t.Fatal(got)
+	}
+}
+
+func TestWatchReadme_regenerates(t *testing.T) {
+	root := t.TempDir()
+	pkg, readme := path.Join(root, "pkg.info"), path.Join(root, "README.md")
+	if err := os.WriteFile(pkg, []byte("name: demo\nversion: 1.0.0\ntenant: acme\narch: [linux_amd64]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gopi, _ := newTestClass(memFS{})
+	gopi.fs = osFS{}
+	gopi.config.Limits.MaxFiles = 1
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- gopi.WatchReadme(ctx, root, 5*time.Millisecond) }()
+
+	waitFor := func(prefix string) {
+		for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+			if raw, _ := os.ReadFile(readme); strings.HasPrefix(string(raw), prefix) {
+				return
+			}
+		}
+		t.Fatalf("the README was not regenerated with %q", prefix)
+	}
+	waitFor("# DEMO 1.0.0")
+	// saves past limits.maxFiles keep regenerating
+	for _, v := range []string{"1.1.0", "1.2.0"} {
+		if err := os.WriteFile(pkg, []byte("name: demo\nversion: "+v+"\ntenant: acme\narch: [linux_amd64]\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		waitFor("# DEMO " + v)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	fsys := memFS{path.Join(tRoot, "pkg.info"): []byte("name: demo\n")}
+	gopi, _ := newTestClass(fsys)
+	files := gopi.watched(tRoot)
+	before := gopi.snapshot(files)
+	if !sameSnapshot(before, gopi.snapshot(files)) {
+		t.Fail()
+	}
+	fsys[path.Join(tRoot, "pkg.info")] = []byte("name: other\n")
+	if sameSnapshot(before, gopi.snapshot(files)) {
+		t.Fail()
+	}
+}
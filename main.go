@@ -1,12 +1,21 @@
 package main
 
 import (
-	_ "embed"
+	"context"
+	"embed"
 	"flag"
 	"fmt"
 	"gov/config"
 	"gov/lib"
+	"gov/metrics"
+	"gov/pkginfo"
+	"log"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 )
 
 //go:embed config.yaml
@@ -16,39 +25,155 @@ var rawConfig []byte
 //go:embed readme.tpl
 var rawTpl []byte
 
+//go:embed templates
+var templates embed.FS
+
 var initPkg bool
 var readMe bool
+var format string
+var noDiscover bool
+var pkgDir string
+var all bool
+var tplSrc string
+var yes bool
 
 const usageInitPkg = "Interactively creates a pkg.info file in the current directory"
-const usageReadme = "Validates the (if exists) pkg.info file in the current directory"
+const usageReadme = "Generates the README of the package from its pkg.info file"
+const usageNoDiscover = "Only look for the pkg.info file in the current directory, not in its parents"
+const usagePackage = "Run the command for the package in this directory of the workspace"
+const usageAll = "Run the command for every package of the workspace under the current directory (gopi -all release releases them in dependency order)"
+const usageTemplate = "README template file or url to use instead of the configured one"
+const usageYes = "Answer yes to every confirmation and apply regenerated files without review"
+const usageFormat = "Format of the pkg.info file: yaml or json (defaults to the existing file's format)"
 
 func init() {
 	flag.BoolVar(&initPkg, "init", false, usageInitPkg)
 	flag.BoolVar(&initPkg, "i", false, usageInitPkg+" (shorthand)")
 	flag.BoolVar(&readMe, "readme", false, usageReadme)
 	flag.BoolVar(&readMe, "rm", false, usageReadme+" (shorthand)")
+	flag.StringVar(&format, "format", "", usageFormat)
+	flag.BoolVar(&noDiscover, "no-discover", false, usageNoDiscover)
+	flag.StringVar(&pkgDir, "package", "", usagePackage)
+	flag.BoolVar(&all, "all", false, usageAll)
+	flag.StringVar(&tplSrc, "template", "", usageTemplate)
+	flag.BoolVar(&yes, "yes", false, usageYes)
+	flag.BoolVar(&yes, "y", false, usageYes+" (shorthand)")
 }
 
 func main() {
-	fmt.Println("GOPI - Go package info utility")
+	fmt.Fprintln(os.Stderr, "GOPI - Go package info utility")
 
 	flag.Parse()
 
 	root, _ := os.Getwd()
-	cfg := config.New(rawConfig, rawTpl)
+	cfg, err := config.New(rawConfig, rawTpl, templates)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	err = cfg.Overlay(config.UserFile())
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	// like git, use the nearest package in a parent directory; init and import always work in place
+	if pkgDir != "" {
+		root, err = filepath.Abs(pkgDir)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+	} else if !noDiscover && !all && !initPkg && flag.Arg(0) != "init" && flag.Arg(0) != "import" {
+		if dir, ok := pkginfo.Find(root, cfg.PkgInfoFile); ok {
+			root = dir
+		}
+	}
+	err = cfg.Overlay(config.ProjectFile(root))
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	if format != "" {
+		cfg.PkgInfoFormat = format
+	}
+	if yes {
+		cfg.AssumeYes, cfg.AutoAccept = true, true
+	}
+	// nobody can review the changes without a terminal
+	if !lib.IsTerminal(os.Stdin) {
+		cfg.AutoAccept = true
+	}
+	if tplSrc != "" {
+		cfg.ReadmeTemplate = tplSrc
+		if !strings.Contains(tplSrc, "://") {
+			cfg.ReadmeTemplate, _ = filepath.Abs(tplSrc)
+		}
+	}
 	gopi := lib.New(cfg)
 
-	if initPkg {
-		gopi.PromptPkg(root)
-		os.Exit(0)
+	name, args := "", []string(nil)
+	switch {
+	case initPkg:
+		name = "init"
+	case flag.NArg() > 0:
+		name, args = flag.Arg(0), flag.Args()[1:]
+	case readMe:
+		name = "readme"
+	default:
+		names := make([]string, 0, len(commands))
+		for n := range commands {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(os.Stderr, "usage: gopi [flags] command [args], commands: %s\n", strings.Join(names, ", "))
+		flag.PrintDefaults()
+		os.Exit(2)
 	}
 
-	gopi.GetPackage(root)
+	cmd, ok := commands[name]
+	if !ok {
+		log.Fatalf("Unknown command %s", name)
+	}
+	cmd = withHooks(name, cmd)
 
-	if readMe {
-		gopi.CreateReadme(root, false)
-		os.Exit(0)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	// the first Ctrl-C cancels ctx, a second one kills gopi, blocked at a
+	// prompt say
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	start := time.Now()
+	loaded := 0
+	switch {
+	case all && name == "release":
+		loaded, err = releaseAll(ctx, cfg, cmd, root, args)
+	case all:
+		loaded, err = runAll(ctx, cfg, cmd, root, args)
+	default:
+		err = cmd(ctx, gopi, root, args)
+		loaded = gopi.Loaded()
 	}
+	stop()
 
-	fmt.Printf("No options selected please visit %s for usage information", gopi.Repo)
+	m := metrics.New(cfg.Metrics)
+	if m.Enabled() {
+		mErr := m.Record(metrics.Run{Command: name, Duration: time.Since(start), Success: err == nil, Packages: loaded})
+		if mErr != nil {
+			log.Printf("WARN: %s", mErr.Error())
+		}
+	}
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+}
+
+// withHooks runs the pre-<name> hook of the package before cmd and its
+// post-<name> hook after it succeeded.
+func withHooks(name string, cmd command) command {
+	return func(ctx context.Context, gopi *lib.Class, root string, args []string) error {
+		if err := gopi.RunHook(ctx, root, "pre-"+name); err != nil {
+			return err
+		}
+		if err := cmd(ctx, gopi, root, args); err != nil {
+			return err
+		}
+		return gopi.RunHook(ctx, root, "post-"+name)
+	}
 }
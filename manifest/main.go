@@ -0,0 +1,128 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Formats are the supported manifest formats.
+var Formats = []string{"package.json", "pyproject", "cargo"}
+
+// Files are the conventional file names of the formats.
+var Files = map[string]string{"package.json": "package.json", "pyproject": "pyproject.toml", "cargo": "Cargo.toml"}
+
+var isPrerelease = regexp.MustCompile(`^(\d+\.\d+\.\d+)-(alpha|beta|rc|a|b)\.?(\d+)$`)
+
+// Marshal renders doc as a package.json, a pyproject.toml or a Cargo.toml.
+func Marshal(doc *Document, format string) ([]byte, error) {
+	switch format {
+	case "package.json":
+		return npm(doc)
+	case "pyproject":
+		return pyproject(doc), nil
+	case "cargo":
+		return cargo(doc), nil
+	}
+	return nil, fmt.Errorf("unknown manifest format %q, expected one of %s", format, strings.Join(Formats, ", "))
+}
+
+func npm(doc *Document) ([]byte, error) {
+	pkg := npmPackage{Name: strings.ToLower(doc.Name), Version: doc.Version, Description: doc.Description, License: doc.License}
+	for i, a := range doc.Authors {
+		if i == 0 {
+			pkg.Author = &npmPerson{Name: a.Name, Email: a.Email}
+			continue
+		}
+		pkg.Contributors = append(pkg.Contributors, npmPerson{Name: a.Name, Email: a.Email})
+	}
+	if doc.Repo != "" {
+		pkg.Repository = &npmRepository{Type: "git", URL: doc.Repo}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(pkg); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func pyproject(doc *Document) []byte {
+	var b strings.Builder
+	b.WriteString("[project]\n")
+	fmt.Fprintf(&b, "name = %s\n", quote(doc.Name))
+	fmt.Fprintf(&b, "version = %s\n", quote(pep440(doc.Version)))
+	if doc.Description != "" {
+		fmt.Fprintf(&b, "description = %s\n", quote(doc.Description))
+	}
+	if doc.License != "" {
+		fmt.Fprintf(&b, "license = { text = %s }\n", quote(doc.License))
+	}
+	if len(doc.Authors) > 0 {
+		b.WriteString("authors = [\n")
+		for _, a := range doc.Authors {
+			if a.Email == "" {
+				fmt.Fprintf(&b, "    { name = %s },\n", quote(a.Name))
+				continue
+			}
+			fmt.Fprintf(&b, "    { name = %s, email = %s },\n", quote(a.Name), quote(a.Email))
+		}
+		b.WriteString("]\n")
+	}
+	if doc.Repo != "" {
+		fmt.Fprintf(&b, "\n[project.urls]\nRepository = %s\n", quote(doc.Repo))
+	}
+	return []byte(b.String())
+}
+
+func cargo(doc *Document) []byte {
+	var b strings.Builder
+	b.WriteString("[package]\n")
+	fmt.Fprintf(&b, "name = %s\n", quote(strings.ToLower(doc.Name)))
+	fmt.Fprintf(&b, "version = %s\n", quote(doc.Version))
+	b.WriteString("edition = \"2021\"\n")
+	if doc.Description != "" {
+		fmt.Fprintf(&b, "description = %s\n", quote(doc.Description))
+	}
+	if doc.License != "" {
+		fmt.Fprintf(&b, "license = %s\n", quote(doc.License))
+	}
+	if len(doc.Authors) > 0 {
+		authors := make([]string, len(doc.Authors))
+		for i, a := range doc.Authors {
+			authors[i] = a.Name
+			if a.Email != "" {
+				authors[i] += " <" + a.Email + ">"
+			}
+			authors[i] = quote(authors[i])
+		}
+		fmt.Fprintf(&b, "authors = [%s]\n", strings.Join(authors, ", "))
+	}
+	if doc.Repo != "" {
+		fmt.Fprintf(&b, "repository = %s\n", quote(doc.Repo))
+	}
+	return []byte(b.String())
+}
+
+// pep440 turns the alpha, beta and rc semver pre-releases into their python
+// spelling, 1.2.0-rc.1 is 1.2.0rc1. Other versions are kept.
+func pep440(v string) string {
+	m := isPrerelease.FindStringSubmatch(v)
+	if m == nil {
+		return v
+	}
+	return m[1] + map[string]string{"alpha": "a", "a": "a", "beta": "b", "b": "b", "rc": "rc"}[m[2]] + m[3]
+}
+
+// quote is st as a TOML basic string, whose escapes are the JSON ones.
+func quote(st string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(st)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
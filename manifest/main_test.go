@@ -0,0 +1,73 @@
+package manifest
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func tDoc() *Document {
+	return &Document{Name: "Demo", Version: "1.2.0-rc.1", Description: `A "demo" tool.`, Repo: "https://github.com/acme/demo", License: "MIT",
+		Authors: []Author{{Name: "Jane Doe", Email: "jane@acme.io"}, {Name: "John Roe"}}}
+}
+
+func TestMarshal_npm(t *testing.T) {
+	raw, err := Marshal(tDoc(), "package.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pkg npmPackage
+	if err = json.Unmarshal(raw, &pkg); err != nil {
+		t.Fatal(err)
+	}
+	if pkg.Name != "demo" || pkg.Version != "1.2.0-rc.1" || pkg.Author.Email != "jane@acme.io" || len(pkg.Contributors) != 1 || pkg.Repository.URL != "https://github.com/acme/demo" {
+		t.Fatal(string(raw))
+	}
+}
+
+func TestMarshal_pyproject(t *testing.T) {
+	raw, _ := Marshal(tDoc(), "pyproject")
+	want := `[project]
+name = "Demo"
+version = "1.2.0rc1"
+description = "A \"demo\" tool."
+license = { text = "MIT" }
+authors = [
+    { name = "Jane Doe", email = "jane@acme.io" },
+    { name = "John Roe" },
+]
+
+[project.urls]
+Repository = "https://github.com/acme/demo"
+`
+	if string(raw) != want {
+		t.Fatal(string(raw))
+	}
+}
+
+func TestMarshal_cargo(t *testing.T) {
+	doc := tDoc()
+	doc.Repo, doc.Description = "", ""
+	raw, _ := Marshal(doc, "cargo")
+	want := `[package]
+name = "demo"
+version = "1.2.0-rc.1"
+edition = "2021"
+license = "MIT"
+authors = ["Jane Doe <jane@acme.io>", "John Roe"]
+`
+	if string(raw) != want {
+		t.Fatal(string(raw))
+	}
+}
+
+func TestMarshal_unknown(t *testing.T) {
+	if _, err := Marshal(tDoc(), "gemspec"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestPep440(t *testing.T) {
+	if pep440("1.0.0-beta.2") != "1.0.0b2" || pep440("1.0.0-dev") != "1.0.0-dev" || pep440("1.0.0") != "1.0.0" {
+		t.Fail()
+	}
+}
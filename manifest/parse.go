@@ -0,0 +1,168 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// isPerson is the "Name <email> (url)" form of the npm and cargo authors.
+var isPerson = regexp.MustCompile(`^([^<(]*?)\s*(?:<([^>]*)>)?\s*(?:\(([^)]*)\))?$`)
+
+var isPep440 = regexp.MustCompile(`^(\d+\.\d+\.\d+)(a|b|rc)(\d+)$`)
+
+// FormatOf is the format of the manifest file pth: package.json, Cargo.toml
+// or pyproject.toml.
+func FormatOf(pth string) (string, error) {
+	for format, file := range Files {
+		if strings.EqualFold(path.Base(pth), file) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown manifest %s, expected a package.json, pyproject.toml or Cargo.toml", pth)
+}
+
+// Parse reads the fields of a manifest of the given format the package info
+// has an equivalent for, the missing ones being left empty.
+func Parse(raw []byte, format string) (*Document, error) {
+	var doc map[string]interface{}
+	var err error
+	if format == "package.json" {
+		err = json.Unmarshal(raw, &doc)
+	} else {
+		doc, err = parseTOML(raw)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the %s manifest: %w", format, err)
+	}
+	switch format {
+	case "package.json":
+		return fromNpm(doc), nil
+	case "pyproject":
+		return fromPyproject(doc), nil
+	case "cargo":
+		return fromCargo(doc), nil
+	}
+	return nil, fmt.Errorf("unknown manifest format %q, expected one of %s", format, strings.Join(Formats, ", "))
+}
+
+func fromNpm(doc map[string]interface{}) *Document {
+	res := &Document{Name: str(doc["name"]), Version: str(doc["version"]), Description: str(doc["description"])}
+	// scoped packages are named after their last part
+	if i := strings.LastIndex(res.Name, "/"); i >= 0 {
+		res.Name = res.Name[i+1:]
+	}
+	res.License = str(doc["license"])
+	if t, ok := doc["license"].(map[string]interface{}); ok {
+		res.License = str(t["type"])
+	}
+	res.Repo = npmRepo(doc["repository"])
+	for _, key := range []string{"author", "contributors", "maintainers"} {
+		res.Authors = appendAuthors(res.Authors, doc[key])
+	}
+	return res
+}
+
+// npmRepo is the url of the repository field, a url or a shorthand like
+// github:acme/tool or acme/tool.
+func npmRepo(v interface{}) string {
+	repo := str(v)
+	if t, ok := v.(map[string]interface{}); ok {
+		repo = str(t["url"])
+	}
+	repo = strings.TrimPrefix(repo, "git+")
+	for prefix, host := range map[string]string{"github:": "github.com", "gitlab:": "gitlab.com", "bitbucket:": "bitbucket.org"} {
+		if strings.HasPrefix(repo, prefix) {
+			return "https://" + host + "/" + strings.TrimPrefix(repo, prefix)
+		}
+	}
+	if repo != "" && !strings.Contains(repo, ":") && strings.Count(repo, "/") == 1 {
+		return "https://github.com/" + repo
+	}
+	return repo
+}
+
+func fromCargo(doc map[string]interface{}) *Document {
+	pkg, _ := doc["package"].(map[string]interface{})
+	return &Document{
+		Name:        str(pkg["name"]),
+		Version:     str(pkg["version"]),
+		Description: str(pkg["description"]),
+		Repo:        str(pkg["repository"]),
+		License:     str(pkg["license"]),
+		Authors:     appendAuthors(nil, pkg["authors"]),
+	}
+}
+
+func fromPyproject(doc map[string]interface{}) *Document {
+	project, _ := doc["project"].(map[string]interface{})
+	if project == nil {
+		// poetry keeps the metadata in its own table
+		tool, _ := doc["tool"].(map[string]interface{})
+		project, _ = tool["poetry"].(map[string]interface{})
+	}
+	res := &Document{Name: str(project["name"]), Version: semver(str(project["version"])), Description: str(project["description"])}
+	res.License = str(project["license"])
+	if t, ok := project["license"].(map[string]interface{}); ok {
+		res.License = str(t["text"])
+	}
+	res.Repo = str(project["repository"])
+	if urls, ok := project["urls"].(map[string]interface{}); ok && res.Repo == "" {
+		for key, v := range urls {
+			if k := strings.ToLower(key); k == "repository" || k == "source" || k == "source code" {
+				res.Repo = str(v)
+			}
+		}
+	}
+	for _, key := range []string{"authors", "maintainers"} {
+		res.Authors = appendAuthors(res.Authors, project[key])
+	}
+	return res
+}
+
+// appendAuthors adds the people of v to authors: a "Name <email>" string, a
+// {name, email} table or a list of them, skipping the ones already listed.
+func appendAuthors(authors []Author, v interface{}) []Author {
+	var a Author
+	switch t := v.(type) {
+	case []interface{}:
+		for _, item := range t {
+			authors = appendAuthors(authors, item)
+		}
+		return authors
+	case map[string]interface{}:
+		a = Author{Name: str(t["name"]), Email: str(t["email"])}
+	case string:
+		m := isPerson.FindStringSubmatch(strings.TrimSpace(t))
+		if m == nil {
+			return authors
+		}
+		a = Author{Name: m[1], Email: m[2]}
+	}
+	if a.Name == "" {
+		return authors
+	}
+	for _, b := range authors {
+		if b == a {
+			return authors
+		}
+	}
+	return append(authors, a)
+}
+
+// semver turns the python pre-releases back into semver, 1.2.0rc1 is
+// 1.2.0-rc.1; see pep440.
+func semver(v string) string {
+	m := isPep440.FindStringSubmatch(v)
+	if m == nil {
+		return v
+	}
+	return m[1] + "-" + map[string]string{"a": "alpha", "b": "beta", "rc": "rc"}[m[2]] + "." + m[3]
+}
+
+func str(v interface{}) string {
+	st, _ := v.(string)
+	return strings.TrimSpace(st)
+}
@@ -0,0 +1,73 @@
+package manifest
+
+import (
+	"testing"
+)
+
+func TestFormatOf(t *testing.T) {
+	if f, err := FormatOf("/x/Cargo.toml"); err != nil || f != "cargo" {
+		t.Fail()
+	}
+	if f, err := FormatOf("pyproject.toml"); err != nil || f != "pyproject" {
+		t.Fail()
+	}
+	if _, err := FormatOf("setup.py"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestParse_npm(t *testing.T) {
+	doc, err := Parse([]byte(`{"name": "@acme/demo", "version": "1.2.0", "license": "MIT", "repository": "github:acme/demo",
+		"author": "Jane Doe <jane@acme.io> (https://jane.dev)", "contributors": [{"name": "John Roe", "email": "john@acme.io"}, "Jane Doe <jane@acme.io>"]}`), "package.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Name != "demo" || doc.Repo != "https://github.com/acme/demo" || len(doc.Authors) != 2 || doc.Authors[0] != (Author{"Jane Doe", "jane@acme.io"}) {
+		t.Fatal(doc)
+	}
+	doc, _ = Parse([]byte(`{"name": "demo", "repository": {"type": "git", "url": "git+https://gitlab.com/acme/demo.git"}, "license": {"type": "ISC"}}`), "package.json")
+	if doc.Repo != "https://gitlab.com/acme/demo.git" || doc.License != "ISC" {
+		t.Fatal(doc)
+	}
+}
+
+func TestParse_cargo(t *testing.T) {
+	doc, err := Parse([]byte("[package]\nname = \"demo\"\nversion = \"0.3.1\"\nlicense = \"MIT OR Apache-2.0\"\nauthors = [\"Jane Doe <jane@acme.io>\", \"John Roe\"]\nrepository = \"https://github.com/acme/demo\"\n"), "cargo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Version != "0.3.1" || doc.License != "MIT OR Apache-2.0" || len(doc.Authors) != 2 || doc.Authors[1] != (Author{Name: "John Roe"}) {
+		t.Fatal(doc)
+	}
+}
+
+func TestParse_pyproject(t *testing.T) {
+	doc, err := Parse([]byte("[project]\nname = \"demo\"\nversion = \"1.2.0rc1\"\nlicense = { text = \"MIT\" }\nauthors = [{ name = \"Jane Doe\", email = \"jane@acme.io\" }]\n\n[project.urls]\nSource = \"https://github.com/acme/demo\"\n"), "pyproject")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Version != "1.2.0-rc.1" || doc.License != "MIT" || doc.Repo != "https://github.com/acme/demo" || len(doc.Authors) != 1 {
+		t.Fatal(doc)
+	}
+	// poetry
+	doc, _ = Parse([]byte("[tool.poetry]\nname = \"demo\"\nversion = \"0.1.0\"\nauthors = [\"Jane Doe <jane@acme.io>\"]\nrepository = \"https://github.com/acme/demo\"\n"), "pyproject")
+	if doc.Name != "demo" || doc.Repo != "https://github.com/acme/demo" || doc.Authors[0].Email != "jane@acme.io" {
+		t.Fatal(doc)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range Formats {
+		raw, err := Marshal(tDoc(), format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc, err := Parse(raw, format)
+		if err != nil {
+			t.Fatal(format, err)
+		}
+		if doc.Version != "1.2.0-rc.1" || doc.Description != `A "demo" tool.` || doc.License != "MIT" || len(doc.Authors) != 2 || doc.Repo != tDoc().Repo {
+			t.Fatal(format, doc)
+		}
+	}
+}
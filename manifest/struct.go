@@ -0,0 +1,38 @@
+package manifest
+
+// Document is what the exported manifests describe, the package info fields
+// the other ecosystems have an equivalent for.
+type Document struct {
+	Name        string
+	Version     string
+	Description string
+	Repo        string
+	License     string
+	Authors     []Author
+}
+
+// Author is a maintainer of the package.
+type Author struct {
+	Name  string
+	Email string
+}
+
+type npmPackage struct {
+	Name         string         `json:"name"`
+	Version      string         `json:"version"`
+	Description  string         `json:"description,omitempty"`
+	License      string         `json:"license,omitempty"`
+	Author       *npmPerson     `json:"author,omitempty"`
+	Contributors []npmPerson    `json:"contributors,omitempty"`
+	Repository   *npmRepository `json:"repository,omitempty"`
+}
+
+type npmPerson struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+type npmRepository struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
@@ -0,0 +1,304 @@
+package manifest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// tomlParser reads the subset of TOML the manifests are written in: tables,
+// arrays of tables, dotted keys, strings, arrays and inline tables. Numbers,
+// booleans and dates are kept as their text.
+type tomlParser struct {
+	src  []rune
+	pos  int
+	line int
+}
+
+func parseTOML(raw []byte) (map[string]interface{}, error) {
+	p := &tomlParser{src: []rune(string(raw)), line: 1}
+	root := map[string]interface{}{}
+	current := root
+	for {
+		p.skipBlank(true)
+		if p.eof() {
+			return root, nil
+		}
+		var err error
+		if p.peek() == '[' {
+			current, err = p.header(root)
+		} else {
+			err = p.keyValue(current)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", p.line, err)
+		}
+		p.skipBlank(false)
+		if !p.eof() && p.peek() != '\n' {
+			return nil, fmt.Errorf("line %d: unexpected %q", p.line, p.peek())
+		}
+	}
+}
+
+func (that *tomlParser) eof() bool {
+	return that.pos >= len(that.src)
+}
+
+func (that *tomlParser) peek() rune {
+	return that.src[that.pos]
+}
+
+func (that *tomlParser) next() rune {
+	r := that.src[that.pos]
+	that.pos++
+	if r == '\n' {
+		that.line++
+	}
+	return r
+}
+
+// skipBlank skips spaces and comments, and the line ends too when newlines.
+func (that *tomlParser) skipBlank(newlines bool) {
+	for !that.eof() {
+		switch r := that.peek(); {
+		case r == ' ' || r == '\t' || r == '\r' || (newlines && r == '\n'):
+			that.next()
+		case r == '#':
+			for !that.eof() && that.peek() != '\n' {
+				that.next()
+			}
+		default:
+			return
+		}
+	}
+}
+
+// header reads a [table] or [[array.of.tables]] line, returning the table
+// the following keys belong to.
+func (that *tomlParser) header(root map[string]interface{}) (map[string]interface{}, error) {
+	that.next()
+	array := !that.eof() && that.peek() == '['
+	if array {
+		that.next()
+	}
+	keys, err := that.key()
+	if err != nil {
+		return nil, err
+	}
+	closing := "]"
+	if array {
+		closing = "]]"
+	}
+	for _, r := range closing {
+		if that.eof() || that.next() != r {
+			return nil, fmt.Errorf("unterminated table header")
+		}
+	}
+	parent, err := table(root, keys[:len(keys)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := keys[len(keys)-1]
+	if !array {
+		return table(parent, []string{last})
+	}
+	list, _ := parent[last].([]interface{})
+	t := map[string]interface{}{}
+	parent[last] = append(list, t)
+	return t, nil
+}
+
+// table is the table at keys under t, created when missing; the last table
+// of an array of tables stands for the array.
+func table(t map[string]interface{}, keys []string) (map[string]interface{}, error) {
+	for _, k := range keys {
+		switch v := t[k].(type) {
+		case nil:
+			sub := map[string]interface{}{}
+			t[k], t = sub, sub
+		case map[string]interface{}:
+			t = v
+		case []interface{}:
+			last, ok := v[len(v)-1].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s is not a table", k)
+			}
+			t = last
+		default:
+			return nil, fmt.Errorf("%s is not a table", k)
+		}
+	}
+	return t, nil
+}
+
+// key reads a dotted key, its parts bare or quoted.
+func (that *tomlParser) key() ([]string, error) {
+	var keys []string
+	for {
+		that.skipBlank(false)
+		if that.eof() {
+			return nil, fmt.Errorf("missing key")
+		}
+		var k string
+		if r := that.peek(); r == '"' || r == '\'' {
+			v, err := that.str()
+			if err != nil {
+				return nil, err
+			}
+			k = v
+		} else {
+			start := that.pos
+			for !that.eof() && isBare(that.peek()) {
+				that.next()
+			}
+			if k = string(that.src[start:that.pos]); k == "" {
+				return nil, fmt.Errorf("invalid key at %q", that.peek())
+			}
+		}
+		keys = append(keys, k)
+		that.skipBlank(false)
+		if that.eof() || that.peek() != '.' {
+			return keys, nil
+		}
+		that.next()
+	}
+}
+
+func isBare(r rune) bool {
+	return r == '_' || r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// keyValue reads key = value into t.
+func (that *tomlParser) keyValue(t map[string]interface{}) error {
+	keys, err := that.key()
+	if err != nil {
+		return err
+	}
+	if that.eof() || that.next() != '=' {
+		return fmt.Errorf("missing = after %s", strings.Join(keys, "."))
+	}
+	that.skipBlank(false)
+	v, err := that.value()
+	if err != nil {
+		return err
+	}
+	if t, err = table(t, keys[:len(keys)-1]); err != nil {
+		return err
+	}
+	t[keys[len(keys)-1]] = v
+	return nil
+}
+
+func (that *tomlParser) value() (interface{}, error) {
+	if that.eof() {
+		return nil, fmt.Errorf("missing value")
+	}
+	switch that.peek() {
+	case '"', '\'':
+		return that.str()
+	case '[':
+		that.next()
+		var list []interface{}
+		for {
+			that.skipBlank(true)
+			if that.eof() {
+				return nil, fmt.Errorf("unterminated array")
+			}
+			if that.peek() == ']' {
+				that.next()
+				return list, nil
+			}
+			v, err := that.value()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+			that.skipBlank(true)
+			if !that.eof() && that.peek() == ',' {
+				that.next()
+			}
+		}
+	case '{':
+		that.next()
+		t := map[string]interface{}{}
+		for {
+			that.skipBlank(false)
+			if that.eof() {
+				return nil, fmt.Errorf("unterminated inline table")
+			}
+			if that.peek() == '}' {
+				that.next()
+				return t, nil
+			}
+			if err := that.keyValue(t); err != nil {
+				return nil, err
+			}
+			that.skipBlank(false)
+			if !that.eof() && that.peek() == ',' {
+				that.next()
+			}
+		}
+	}
+	start := that.pos
+	for !that.eof() && !strings.ContainsRune(",]}# \t\r\n", that.peek()) {
+		that.next()
+	}
+	return strings.TrimSpace(string(that.src[start:that.pos])), nil
+}
+
+// str reads a basic "..." or literal '...' string, multi-line ones too.
+func (that *tomlParser) str() (string, error) {
+	quote := that.next()
+	multi := that.pos+1 < len(that.src) && that.src[that.pos] == quote && that.src[that.pos+1] == quote
+	if multi {
+		that.pos += 2
+		// a newline right after the opening quotes is trimmed
+		if !that.eof() && that.peek() == '\n' {
+			that.next()
+		}
+	}
+	var sb strings.Builder
+	for {
+		if that.eof() {
+			return "", fmt.Errorf("unterminated string")
+		}
+		r := that.next()
+		switch {
+		case r == quote && !multi:
+			return sb.String(), nil
+		case r == quote && that.pos+1 < len(that.src) && that.src[that.pos] == quote && that.src[that.pos+1] == quote:
+			that.pos += 2
+			return sb.String(), nil
+		case r == '\n' && !multi:
+			return "", fmt.Errorf("unterminated string")
+		case r == '\\' && quote == '"':
+			if err := that.escape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+}
+
+func (that *tomlParser) escape(sb *strings.Builder) error {
+	if that.eof() {
+		return fmt.Errorf("unterminated string")
+	}
+	r := that.next()
+	if simple, ok := map[rune]rune{'b': '\b', 't': '\t', 'n': '\n', 'f': '\f', 'r': '\r', '"': '"', '\\': '\\'}[r]; ok {
+		sb.WriteRune(simple)
+		return nil
+	}
+	size := map[rune]int{'u': 4, 'U': 8}[r]
+	if size == 0 || that.pos+size > len(that.src) {
+		return fmt.Errorf("invalid escape \\%c", r)
+	}
+	code, err := strconv.ParseUint(string(that.src[that.pos:that.pos+size]), 16, 32)
+	if err != nil {
+		return fmt.Errorf("invalid escape \\%c", r)
+	}
+	that.pos += size
+	sb.WriteRune(rune(code))
+	return nil
+}
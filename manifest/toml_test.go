@@ -0,0 +1,59 @@
+package manifest
+
+import (
+	"testing"
+)
+
+func TestParseTOML(t *testing.T) {
+	doc, err := parseTOML([]byte(`# a manifest
+title = "demo" # trailing comment
+[package]
+name = 'demo'
+version.workspace = true
+tags = [
+    "a\tb",  # first
+    "é",
+]
+authors = [{ name = "Jane", email = "jane@acme.io" }]
+notes = """
+multi "line"
+"""
+
+[[bin]]
+name = "one"
+[[bin]]
+name = "two"
+[project.urls]
+"Source Code" = "https://github.com/acme/demo"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg := doc["package"].(map[string]interface{})
+	if doc["title"] != "demo" || pkg["name"] != "demo" || pkg["version"].(map[string]interface{})["workspace"] != "true" {
+		t.Fatal(doc)
+	}
+	if tags := pkg["tags"].([]interface{}); len(tags) != 2 || tags[0] != "a\tb" || tags[1] != "é" {
+		t.Fatal(tags)
+	}
+	if a := pkg["authors"].([]interface{})[0].(map[string]interface{}); a["email"] != "jane@acme.io" {
+		t.Fatal(a)
+	}
+	if pkg["notes"] != "multi \"line\"\n" {
+		t.Fatalf("%q", pkg["notes"])
+	}
+	if bins := doc["bin"].([]interface{}); len(bins) != 2 || bins[1].(map[string]interface{})["name"] != "two" {
+		t.Fatal(bins)
+	}
+	if doc["project"].(map[string]interface{})["urls"].(map[string]interface{})["Source Code"] != "https://github.com/acme/demo" {
+		t.Fatal(doc["project"])
+	}
+}
+
+func TestParseTOML_errors(t *testing.T) {
+	for _, src := range []string{"name = \"demo", "[package", "name demo", "a = [1, 2", "a = 1 b = 2", `a = "\x"`} {
+		if _, err := parseTOML([]byte(src)); err == nil {
+			t.Fatalf("%q parsed", src)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var notMetricChar = regexp.MustCompile("[^a-zA-Z0-9_]+")
+
+func boolValue(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func statsdPayload(prefix string, run Run) []byte {
+	name := prefix + "." + notMetricChar.ReplaceAllString(run.Command, "_")
+	outcome := "failure"
+	if run.Success {
+		outcome = "success"
+	}
+	lines := []string{
+		fmt.Sprintf("%s.duration:%d|ms", name, run.Duration.Milliseconds()),
+		fmt.Sprintf("%s.%s:1|c", name, outcome),
+		fmt.Sprintf("%s.packages:%d|c", name, run.Packages),
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+func promPayload(prefix string, run Run, now time.Time) []byte {
+	name := notMetricChar.ReplaceAllString(prefix, "_") + "_command"
+	var sb strings.Builder
+	write := func(metric string, help string, value interface{}) {
+		sb.WriteString(fmt.Sprintf("# HELP %s_%s %s\n# TYPE %s_%s gauge\n%s_%s %v\n",
+			name, metric, help, name, metric, name, metric, value))
+	}
+	write("duration_seconds", "Duration of the last run.", run.Duration.Seconds())
+	write("success", "1 if the last run succeeded.", boolValue(run.Success))
+	write("packages", "Packages processed by the last run.", run.Packages)
+	write("last_run_timestamp_seconds", "Unix time the last run finished.", now.Unix())
+	return []byte(sb.String())
+}
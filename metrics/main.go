@@ -0,0 +1,17 @@
+package metrics
+
+import (
+	"gov/config"
+	"net/http"
+	"time"
+)
+
+func New(cfg config.Metrics) *Class {
+	if cfg.Prefix == "" {
+		cfg.Prefix = "gopi"
+	}
+	return &Class{
+		config: cfg,
+		client: &http.Client{Timeout: 2 * time.Second},
+	}
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+func (that *Class) Enabled() bool {
+	return that.config.StatsD != "" || that.config.Pushgateway != ""
+}
+
+// Record emits the run to every configured sink. Metrics are best effort:
+// failures are returned for logging but must never fail the command itself.
+func (that *Class) Record(run Run) error {
+	var errs []string
+	if that.config.StatsD != "" {
+		if err := that.statsd(run); err != nil {
+			errs = append(errs, "statsd: "+err.Error())
+		}
+	}
+	if that.config.Pushgateway != "" {
+		if err := that.push(run); err != nil {
+			errs = append(errs, "pushgateway: "+err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("unable to emit metrics (%s)", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (that *Class) statsd(run Run) error {
+	conn, err := net.DialTimeout("udp", that.config.StatsD, time.Second)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	_, err = conn.Write(statsdPayload(that.config.Prefix, run))
+	return err
+}
+
+func (that *Class) push(run Run) error {
+	endpoint := fmt.Sprintf("%s/metrics/job/%s/command/%s",
+		strings.TrimSuffix(that.config.Pushgateway, "/"), url.PathEscape(that.config.Prefix), url.PathEscape(run.Command))
+	resp, err := that.client.Post(endpoint, "text/plain; version=0.0.4", bytes.NewReader(promPayload(that.config.Prefix, run, time.Now())))
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"gov/config"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var tRun = Run{Command: "ci-detect", Duration: 1500 * time.Millisecond, Success: true, Packages: 1}
+
+func TestStatsdPayload(t *testing.T) {
+	got := string(statsdPayload("gopi", tRun))
+	if got != "gopi.ci_detect.duration:1500|ms\ngopi.ci_detect.success:1|c\ngopi.ci_detect.packages:1|c" {
+		t.Fatalf("unexpected payload %q", got)
+	}
+}
+
+func TestRecord_statsd(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("udp not available")
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	m := New(config.Metrics{StatsD: conn.LocalAddr().String()})
+	if err = m.Record(tRun); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 512)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil || !strings.HasPrefix(string(buf[:n]), "gopi.ci_detect.duration:1500|ms") {
+		t.Fail()
+	}
+}
+
+func TestRecord_pushgateway(t *testing.T) {
+	var path, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		path, body = r.URL.Path, string(raw)
+	}))
+	defer srv.Close()
+
+	m := New(config.Metrics{Pushgateway: srv.URL + "/", Prefix: "acme-gopi"})
+	if err := m.Record(tRun); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/metrics/job/acme-gopi/command/ci-detect" {
+		t.Fail()
+	}
+	if !strings.Contains(body, "acme_gopi_command_duration_seconds 1.5\n") || !strings.Contains(body, "acme_gopi_command_success 1\n") {
+		t.Fail()
+	}
+}
+
+func TestRecord_disabled(t *testing.T) {
+	m := New(config.Metrics{})
+	if m.Enabled() || m.Record(tRun) != nil {
+		t.Fail()
+	}
+}
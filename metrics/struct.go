@@ -0,0 +1,20 @@
+package metrics
+
+import (
+	"gov/config"
+	"net/http"
+	"time"
+)
+
+type Class struct {
+	config config.Metrics
+	client *http.Client
+}
+
+// Run describes one gopi command execution.
+type Run struct {
+	Command  string
+	Duration time.Duration
+	Success  bool
+	Packages int
+}
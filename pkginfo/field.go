@@ -0,0 +1,154 @@
+package pkginfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var isFieldStep = regexp.MustCompile(`^([A-Za-z_]+)((?:\[\d+\])*)$`)
+
+var isIndex = regexp.MustCompile(`\[(\d+)\]`)
+
+// Field returns the value at path, a field name optionally prefixed with a dot
+// and followed by list indexes: version, .tenant, .arch[0]. Lists resolve to
+// their items, one per line.
+func Field(info *Info, path string) (string, error) {
+	raw, err := json.Marshal(info)
+	if err != nil {
+		return "", err
+	}
+	var cur interface{}
+	if err = json.Unmarshal(raw, &cur); err != nil {
+		return "", err
+	}
+
+	for _, step := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		m := isFieldStep.FindStringSubmatch(step)
+		if m == nil {
+			return "", fmt.Errorf("invalid field path %q", path)
+		}
+		obj, ok := cur.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("%s: %q is not an object", path, m[1])
+		}
+		if cur, ok = obj[m[1]]; !ok {
+			return "", fmt.Errorf("unknown field %q", m[1])
+		}
+		for _, idx := range isIndex.FindAllStringSubmatch(m[2], -1) {
+			lst, ok := cur.([]interface{})
+			if !ok {
+				return "", fmt.Errorf("%s: %q is not a list", path, m[1])
+			}
+			i, _ := strconv.Atoi(idx[1])
+			if i >= len(lst) {
+				return "", fmt.Errorf("%s: index %d out of range, %s has %d item(s)", path, i, m[1], len(lst))
+			}
+			cur = lst[i]
+		}
+	}
+	return format(cur)
+}
+
+func format(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return t, nil
+	case []interface{}:
+		lines := make([]string, len(t))
+		for i, item := range t {
+			st, err := format(item)
+			if err != nil {
+				return "", err
+			}
+			lines[i] = st
+		}
+		return strings.Join(lines, "\n"), nil
+	}
+	raw, err := json.Marshal(v)
+	return string(raw), err
+}
+
+// SetField assigns value to a top level field, arch taking a comma separated
+// list, and validates the result for that field.
+func SetField(info *Info, field string, value string, archList ...string) error {
+	next := *info
+	field = strings.TrimPrefix(field, ".")
+	switch field {
+	case "name":
+		next.Name = value
+	case "version":
+		next.Version = value
+	case "channel":
+		next.Channel = value
+	case "description":
+		next.Description = value
+	case "tenant":
+		next.Tenant = value
+	case "repo":
+		next.Repo = value
+	case "type":
+		next.Type = value
+	case "license":
+		next.License = value
+	case "maintainers":
+		m, err := ParseMaintainers(value)
+		if err != nil {
+			return err
+		}
+		next.Maintainers = m
+	case "keywords":
+		next.Keywords = SplitList(value)
+	case "arch":
+		next.Arch = SplitList(value)
+	default:
+		return fmt.Errorf("unknown field %q, expected one of %s", field, strings.Join(fieldOrder, ", "))
+	}
+
+	var problems []string
+	for _, d := range Validate(&next, archList...) {
+		if base, _, _ := strings.Cut(d.Field, "["); base == field {
+			problems = append(problems, d.Message)
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid %s: %s", field, strings.Join(problems, "; "))
+	}
+	*info = next
+	return nil
+}
+
+// SplitList splits a comma separated list, dropping blank items.
+func SplitList(value string) []string {
+	var res []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+var isMaintainer = regexp.MustCompile(`^([^<>()]+?)\s*<([^<>\s]+)>\s*(?:\(([^()]+)\))?$`)
+
+// ParseMaintainers reads a comma separated list of "Name <email> (role)"
+// entries, the role being optional.
+func ParseMaintainers(value string) ([]Maintainer, error) {
+	var res []Maintainer
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		m := isMaintainer.FindStringSubmatch(entry)
+		if m == nil {
+			return nil, fmt.Errorf("invalid maintainer %q, expected Name <email> (role)", entry)
+		}
+		res = append(res, Maintainer{Name: m[1], Email: m[2], Role: strings.TrimSpace(m[3])})
+	}
+	return res, nil
+}
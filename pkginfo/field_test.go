@@ -0,0 +1,95 @@
+package pkginfo
+
+import "testing"
+
+func TestField(t *testing.T) {
+	info := tInfo()
+	info.Arch = []string{"linux_amd64", "darwin_arm64"}
+	for path, want := range map[string]string{
+		"version":  "1.2.3",
+		".tenant":  "mtag",
+		".arch[1]": "darwin_arm64",
+		"arch":     "linux_amd64\ndarwin_arm64",
+	} {
+		if got, err := Field(info, path); err != nil || got != want {
+			t.Fatalf("%s: %q %v", path, got, err)
+		}
+	}
+}
+
+func TestField_errors(t *testing.T) {
+	for _, path := range []string{"owner", ".arch[5]", ".name[0]", "arch.x", "a b"} {
+		if _, err := Field(tInfo(), path); err == nil {
+			t.Fatalf("%q accepted", path)
+		}
+	}
+}
+
+func TestSetField(t *testing.T) {
+	info := tInfo()
+	if err := SetField(info, "arch", "linux_amd64, darwin_arm64", "linux_amd64", "darwin_arm64"); err != nil || len(info.Arch) != 2 {
+		t.Fatal(err)
+	}
+	if err := SetField(info, ".description", "New text"); err != nil || info.Description != "New text" {
+		t.Fail()
+	}
+	for field, value := range map[string]string{"version": "1.x", "name": "", "type": "plugin", "owner": "me", "arch": "plan9"} {
+		if err := SetField(info, field, value, "linux_amd64"); err == nil {
+			t.Fatalf("%s=%q accepted", field, value)
+		}
+	}
+	if info.Version != "1.2.3" || info.Name != "gopi" {
+		t.Fail()
+	}
+}
+
+func TestParseMaintainers(t *testing.T) {
+	m, err := ParseMaintainers("Jane Doe <jane@acme.io> (lead), Bob <bob@acme.io>")
+	if err != nil || len(m) != 2 || m[0].Role != "lead" || m[1].Name != "Bob" || m[1].Role != "" {
+		t.Fatal(m, err)
+	}
+	if _, err = ParseMaintainers("jane@acme.io"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestValidate_maintainers(t *testing.T) {
+	info := tInfo()
+	info.Maintainers = []Maintainer{{Name: "Jane", Email: "jane@acme.io"}, {Email: "Bob <bob@acme.io>"}}
+	d := Validate(info)
+	if len(d) != 2 || d[0].Field != "maintainers[1].email" || d[1].Field != "maintainers[1].name" {
+		t.Fatal(d)
+	}
+	if v, err := Field(info, ".maintainers[0].email"); err != nil || v != "jane@acme.io" {
+		t.Fail()
+	}
+}
+
+func TestValidate_keywords(t *testing.T) {
+	info := tInfo()
+	info.Keywords = []string{"cli", "Bad Word", "cli"}
+	d := Validate(info)
+	if len(d) != 2 || d[0].Field != "keywords[1]" || d[1].Field != "keywords[2]" {
+		t.Fatal(d)
+	}
+	if err := SetField(info, "keywords", "cli, tooling"); err != nil || len(info.Keywords) != 2 {
+		t.Fatal(info.Keywords, err)
+	}
+}
+
+func TestValidate_readme(t *testing.T) {
+	info := tInfo()
+	info.Readme = &Readme{Sections: []string{"header", "Usage", "header"}, Templates: map[string]string{"usage": " "}}
+	d := Validate(info)
+	if len(d) != 3 || d[0].Field != "readme.sections[1]" || d[1].Field != "readme.sections[2]" || d[2].Field != "readme.templates[usage]" {
+		t.Fatal(d)
+	}
+}
+
+func TestValidate_hooks(t *testing.T) {
+	info := Info{Name: "demo", Version: "1.0.0", Tenant: "acme", Hooks: Hooks{"post-readme": "prettier --write README.md", "after-build": "x", "pre-release": " "}}
+	d := Validate(&info)
+	if len(d) != 2 || d[0].Field != "hooks[after-build]" || d[0].Code != "format" || d[1].Field != "hooks[pre-release]" || d[1].Code != "required" {
+		t.Fatal(d)
+	}
+}
@@ -0,0 +1,94 @@
+package pkginfo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"gov/txn"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Types are the accepted values of Info.Type.
+var Types = []string{"cli", "library", "service"}
+
+// Load reads and parses the manifest at path, in either yaml or json.
+func Load(path string) (*Info, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", path, err)
+	}
+	return info, nil
+}
+
+// Find walks up from dir to the nearest directory holding the manifest name
+// (or its .json variant) and reports whether one was found.
+func Find(dir string, name string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		for _, p := range []string{name, name + ".json"} {
+			if fi, err := os.Stat(filepath.Join(dir, p)); err == nil && !fi.IsDir() {
+				return dir, true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// Parse decodes manifest content, detecting json by its leading brace.
+func Parse(content []byte) (*Info, error) {
+	var info Info
+	var err error
+	if IsJSON(content) {
+		err = json.Unmarshal(content, &info)
+	} else {
+		err = yaml.Unmarshal(content, &info)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Save writes info to path, as json when path ends in .json and yaml otherwise.
+func Save(path string, info *Info) error {
+	format := "yaml"
+	if strings.HasSuffix(path, ".json") {
+		format = "json"
+	}
+	raw, err := Marshal(info, format)
+	if err != nil {
+		return err
+	}
+	return txn.WriteFile(path, raw, 0644)
+}
+
+// Marshal encodes info as "yaml" or "json", the way gopi writes pkg.info.
+func Marshal(info *Info, format string) ([]byte, error) {
+	switch format {
+	case "json":
+		raw, err := json.MarshalIndent(info, "", "  ")
+		return append(raw, '\n'), err
+	case "yaml":
+		raw, err := yaml.Marshal(info)
+		return append([]byte(fmt.Sprintf("# %s pkg.info file\n\n", info.Name)), raw...), err
+	}
+	return nil, fmt.Errorf("unknown package info format %q, expected yaml or json", format)
+}
+
+func IsJSON(content []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(content), []byte("{"))
+}
@@ -0,0 +1,97 @@
+package pkginfo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func tInfo() *Info {
+	return &Info{
+		Name:    "gopi",
+		Version: "1.2.3",
+		Tenant:  "mtag",
+		Repo:    "https://github.com/mtag-io/gopi",
+		Type:    "cli",
+		Arch:    []string{"linux/amd64"},
+	}
+}
+
+func TestSaveLoad_yaml(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), "pkg.info")
+	if err := Save(pth, tInfo()); err != nil {
+		t.Fatal(err)
+	}
+	info, err := Load(pth)
+	if err != nil || info.Name != "gopi" || info.Version != "1.2.3" || len(info.Arch) != 1 {
+		t.Fail()
+	}
+}
+
+func TestSaveLoad_json(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), "pkg.info.json")
+	if err := Save(pth, tInfo()); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := os.ReadFile(pth)
+	if !IsJSON(raw) {
+		t.Fail()
+	}
+	info, err := Load(pth)
+	if err != nil || info.Type != "cli" {
+		t.Fail()
+	}
+}
+
+func TestLoad_invalid(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), "pkg.info")
+	_ = os.WriteFile(pth, []byte("name: [unterminated"), 0644)
+	if _, err := Load(pth); err == nil {
+		t.Fail()
+	}
+}
+
+func TestValidate_ok(t *testing.T) {
+	if d := Validate(tInfo(), "linux/amd64"); len(d) != 0 {
+		t.Fatal(d)
+	}
+}
+
+func TestValidate_problems(t *testing.T) {
+	info := &Info{Version: "1.x", Repo: "nowhere", Type: "plugin", Arch: []string{"plan9/mips"}}
+	var fields []string
+	for _, d := range Validate(info, "linux/amd64") {
+		fields = append(fields, d.Field)
+	}
+	want := []string{"name", "version", "tenant", "repo", "type", "arch[0]"}
+	if len(fields) != len(want) {
+		t.Fatal(fields)
+	}
+	for i := range want {
+		if fields[i] != want[i] {
+			t.Fatal(fields)
+		}
+	}
+}
+
+func TestValidate_arch_unchecked(t *testing.T) {
+	info := tInfo()
+	info.Arch = []string{"plan9/mips"}
+	if d := Validate(info); len(d) != 0 {
+		t.Fail()
+	}
+}
+
+func TestFind_parent(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "cmd", "foo")
+	_ = os.MkdirAll(sub, 0755)
+	_ = os.WriteFile(filepath.Join(root, "pkg.info.json"), []byte("{}"), 0644)
+	dir, ok := Find(sub, "pkg.info")
+	if !ok || dir != root {
+		t.Fail()
+	}
+	if _, ok = Find(sub, "missing.info"); ok {
+		t.Fail()
+	}
+}
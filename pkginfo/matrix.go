@@ -0,0 +1,53 @@
+package pkginfo
+
+import (
+	"gov/platform"
+	"strings"
+)
+
+// excluded tells whether the exclude entry rule, os/arch with * matching
+// anything, covers target.
+func excluded(rule string, target string) bool {
+	ruleOS, ruleArch, _ := strings.Cut(rule, "/")
+	goos, goarch := platform.Split(target)
+	return (ruleOS == "*" || ruleOS == goos) && (ruleArch == "*" || ruleArch == goarch)
+}
+
+// Targets expands the matrix into goos/goarch platforms, in the order of its
+// os and arch lists, then the include entries. Like in CI matrices the
+// exclude entries only remove combinations, never an include.
+func (that *Matrix) Targets() []string {
+	if that == nil {
+		return nil
+	}
+	var res []string
+	for _, goos := range that.OS {
+		for _, goarch := range that.Arch {
+			target, keep := goos+"/"+goarch, true
+			for _, rule := range that.Exclude {
+				keep = keep && !excluded(rule, target)
+			}
+			if keep && !platform.Contains(res, target) {
+				res = append(res, target)
+			}
+		}
+	}
+	for _, inc := range that.Include {
+		if !platform.Contains(res, inc) {
+			res = append(res, platform.Canonical(inc))
+		}
+	}
+	return res
+}
+
+// Platforms are the build targets of the package: its arch entries, then the
+// targets of its matrix not already listed.
+func (that *Info) Platforms() []string {
+	res := append([]string(nil), that.Arch...)
+	for _, t := range that.Matrix.Targets() {
+		if !platform.Contains(res, t) {
+			res = append(res, t)
+		}
+	}
+	return res
+}
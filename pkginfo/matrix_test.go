@@ -0,0 +1,49 @@
+package pkginfo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatrix_Targets(t *testing.T) {
+	m := &Matrix{
+		OS:      []string{"linux", "darwin", "windows"},
+		Arch:    []string{"amd64", "arm64", "386"},
+		Include: []string{"linux_riscv64", "darwin/386"},
+		Exclude: []string{"darwin/386", "windows/*", "*/arm64"},
+	}
+	got := strings.Join(m.Targets(), " ")
+	if got != "linux/amd64 linux/386 darwin/amd64 linux/riscv64 darwin/386" {
+		t.Fatal(got)
+	}
+	var none *Matrix
+	if none.Targets() != nil {
+		t.Fail()
+	}
+}
+
+func TestPlatforms(t *testing.T) {
+	info, err := Parse([]byte("arch: [linux_amd64]\nmatrix:\n    os: [linux, darwin]\n    arch: [amd64, arm64]\n    exclude: [darwin/amd64]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(info.Platforms(), " "); got != "linux_amd64 linux/arm64 darwin/arm64" {
+		t.Fatal(got)
+	}
+}
+
+func TestValidate_matrix(t *testing.T) {
+	info := tInfo()
+	info.Matrix = &Matrix{OS: []string{"linux", "plan9"}, Exclude: []string{"darwin"}}
+	var fields []string
+	for _, d := range Validate(info, "linux/amd64") {
+		fields = append(fields, d.Field+":"+d.Code)
+	}
+	if strings.Join(fields, " ") != "matrix.arch:required matrix.exclude[0]:format" {
+		t.Fatal(fields)
+	}
+	info.Matrix = &Matrix{OS: []string{"linux", "plan9"}, Arch: []string{"amd64"}}
+	if d := Validate(info, "linux/amd64"); len(d) != 1 || d[0].Field != "matrix" || !strings.Contains(d[0].Message, "plan9/amd64") {
+		t.Fatal(d)
+	}
+}
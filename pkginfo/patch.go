@@ -0,0 +1,89 @@
+package pkginfo
+
+import (
+	"bytes"
+	"gopkg.in/yaml.v3"
+	"reflect"
+	"regexp"
+)
+
+var isIndented = regexp.MustCompile(`(?m)^( +)\S`)
+
+// Patch writes info over the yaml manifest original, keeping its comments,
+// key order and indentation. Only the values that changed are touched, new
+// fields are appended and fields that became empty (omitempty) are removed.
+func Patch(original []byte, info *Info) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(original, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return Marshal(info, "yaml")
+	}
+	var fresh yaml.Node
+	if err := fresh.Encode(info); err != nil {
+		return nil, err
+	}
+
+	m := doc.Content[0]
+	present := map[string]bool{}
+	for i := 0; i+1 < len(fresh.Content); i += 2 {
+		key, val := fresh.Content[i], fresh.Content[i+1]
+		present[key.Value] = true
+		j := indexOf(m, key.Value)
+		if j < 0 {
+			m.Content = append(m.Content, key, val)
+			continue
+		}
+		old := m.Content[j+1]
+		if sameValue(old, val) {
+			continue
+		}
+		if old.Kind == yaml.ScalarNode && val.Kind == yaml.ScalarNode {
+			old.Value, old.Tag = val.Value, val.Tag
+			if val.Style != 0 || old.Style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+				old.Style = val.Style
+			}
+			continue
+		}
+		val.HeadComment, val.LineComment, val.FootComment = old.HeadComment, old.LineComment, old.FootComment
+		m.Content[j+1] = val
+	}
+	for _, field := range fieldOrder {
+		if j := indexOf(m, field); j >= 0 && !present[field] {
+			m.Content = append(m.Content[:j], m.Content[j+2:]...)
+		}
+	}
+
+	indent := 4
+	if loc := isIndented.FindSubmatch(original); loc != nil {
+		indent = len(loc[1])
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(indent)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func indexOf(m *yaml.Node, key string) int {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func sameValue(a *yaml.Node, b *yaml.Node) bool {
+	var va, vb interface{}
+	if a.Decode(&va) != nil || b.Decode(&vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,54 @@
+package pkginfo
+
+import (
+	"strings"
+	"testing"
+)
+
+const tCommented = `# gopi package info, maintained by the platform team
+
+name: gopi # the binary name too
+# bump with gopi bump
+version: 1.2.3
+description: |
+  Package info utility.
+tenant: mtag
+repo: https://github.com/mtag-io/gopi
+type: cli
+arch:
+  - linux_amd64 # CI runners
+`
+
+func TestPatch_preserves_comments(t *testing.T) {
+	info, err := Parse([]byte(tCommented))
+	if err != nil {
+		t.Fatal(err)
+	}
+	info.Version = "1.3.0"
+	raw, err := Patch([]byte(tCommented), info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Replace(tCommented, "version: 1.2.3", "version: 1.3.0", 1)
+	if string(raw) != want {
+		t.Fatalf("unexpected patch result:\n%s", raw)
+	}
+}
+
+func TestPatch_add_remove(t *testing.T) {
+	info, _ := Parse([]byte(tCommented))
+	info.Type = ""
+	info.Arch = append(info.Arch, "darwin_arm64")
+	raw, err := Patch([]byte(tCommented), info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(raw)
+	if strings.Contains(got, "type:") || !strings.Contains(got, "  - darwin_arm64") || !strings.Contains(got, "# bump with gopi bump") {
+		t.Fatalf("unexpected patch result:\n%s", got)
+	}
+	back, err := Parse(raw)
+	if err != nil || len(back.Arch) != 2 || back.Name != "gopi" {
+		t.Fail()
+	}
+}
@@ -0,0 +1,96 @@
+package pkginfo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// spdxLicenses are the SPDX identifiers accepted in the license field; custom
+// licenses use the LicenseRef- prefix.
+var spdxLicenses = []string{
+	"0BSD", "AFL-3.0", "AGPL-3.0-only", "AGPL-3.0-or-later", "Apache-1.1", "Apache-2.0",
+	"Artistic-2.0", "BSD-1-Clause", "BSD-2-Clause", "BSD-2-Clause-Patent", "BSD-3-Clause",
+	"BSD-3-Clause-Clear", "BSD-4-Clause", "BSL-1.0", "CC-BY-4.0", "CC-BY-SA-4.0", "CC0-1.0",
+	"CDDL-1.0", "CDDL-1.1", "ECL-2.0", "EPL-1.0", "EPL-2.0", "EUPL-1.1", "EUPL-1.2",
+	"GPL-2.0-only", "GPL-2.0-or-later", "GPL-3.0-only", "GPL-3.0-or-later", "ISC",
+	"LGPL-2.1-only", "LGPL-2.1-or-later", "LGPL-3.0-only", "LGPL-3.0-or-later", "MIT",
+	"MIT-0", "MPL-1.1", "MPL-2.0", "MS-PL", "MS-RL", "MulanPSL-2.0", "NCSA", "OFL-1.1",
+	"OSL-3.0", "PostgreSQL", "Python-2.0", "UPL-1.0", "Unlicense", "Vim", "WTFPL", "Zlib",
+}
+
+var spdxExceptions = []string{
+	"Classpath-exception-2.0", "GCC-exception-3.1", "LLVM-exception", "Linux-syscall-note",
+}
+
+var spdxToken = regexp.MustCompile(`\(|\)|[^\s()]+`)
+
+var isLicenseRef = regexp.MustCompile(`^(?:DocumentRef-[\w.-]+:)?LicenseRef-[\w.-]+$`)
+
+// ValidLicense checks an SPDX license expression: known identifiers (with an
+// optional trailing +), LicenseRef- custom ids, AND / OR / WITH and parentheses.
+func ValidLicense(expr string) error {
+	tokens := spdxToken.FindAllString(expr, -1)
+	if len(tokens) == 0 {
+		return fmt.Errorf("empty license expression")
+	}
+	depth := 0
+	expectID := true
+	for i, tok := range tokens {
+		switch {
+		case tok == "(":
+			if !expectID {
+				return fmt.Errorf("unexpected ( in %q", expr)
+			}
+			depth++
+		case tok == ")":
+			if expectID || depth == 0 {
+				return fmt.Errorf("unexpected ) in %q", expr)
+			}
+			depth--
+		case tok == "AND" || tok == "OR":
+			if expectID {
+				return fmt.Errorf("unexpected %s in %q", tok, expr)
+			}
+			expectID = true
+		case tok == "WITH":
+			if expectID || i+1 >= len(tokens) || !containsFold(spdxExceptions, tokens[i+1]) {
+				return fmt.Errorf("WITH must be followed by a known SPDX exception in %q", expr)
+			}
+		case i > 0 && tokens[i-1] == "WITH":
+		default:
+			if !expectID {
+				return fmt.Errorf("missing AND / OR before %q", tok)
+			}
+			if !containsFold(spdxLicenses, strings.TrimSuffix(tok, "+")) && !isLicenseRef.MatchString(tok) {
+				return fmt.Errorf("%q is not a known SPDX license identifier", tok)
+			}
+			expectID = false
+		}
+	}
+	if expectID || depth != 0 {
+		return fmt.Errorf("incomplete license expression %q", expr)
+	}
+	return nil
+}
+
+// SPDX identifiers are matched case-insensitively.
+func containsFold(s []string, str string) bool {
+	for _, v := range s {
+		if strings.EqualFold(v, str) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanonicalLicense returns the SPDX spelling of a single license identifier,
+// or "" when id is not a known one.
+func CanonicalLicense(id string) string {
+	for _, v := range spdxLicenses {
+		if strings.EqualFold(v, id) {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,17 @@
+package pkginfo
+
+import "testing"
+
+func TestValidLicense(t *testing.T) {
+	for _, expr := range []string{"MIT", "apache-2.0", "MIT OR Apache-2.0", "(MIT AND BSD-3-Clause) OR GPL-2.0-or-later",
+		"GPL-2.0-only WITH Classpath-exception-2.0", "LicenseRef-Acme-Proprietary", "MPL-1.1+"} {
+		if err := ValidLicense(expr); err != nil {
+			t.Fatalf("%q: %v", expr, err)
+		}
+	}
+	for _, expr := range []string{"", "MITT", "MIT Apache-2.0", "MIT OR", "(MIT", "MIT)", "GPL-2.0-only WITH nothing", "OR MIT"} {
+		if ValidLicense(expr) == nil {
+			t.Fatalf("%q accepted", expr)
+		}
+	}
+}
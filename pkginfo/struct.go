@@ -0,0 +1,76 @@
+package pkginfo
+
+import "fmt"
+
+// Info is the content of a pkg.info (or pkg.info.json) manifest.
+type Info struct {
+	Name         string       `yaml:"name" json:"name"`
+	Version      string       `yaml:"version" json:"version"`
+	Channel      string       `yaml:"channel,omitempty" json:"channel,omitempty"`
+	Description  string       `yaml:"description" json:"description"`
+	Keywords     []string     `yaml:"keywords,omitempty" json:"keywords,omitempty"`
+	Tenant       string       `yaml:"tenant" json:"tenant"`
+	Repo         string       `yaml:"repo" json:"repo"`
+	Type         string       `yaml:"type,omitempty" json:"type,omitempty"`
+	License      string       `yaml:"license,omitempty" json:"license,omitempty"`
+	Maintainers  []Maintainer `yaml:"maintainers,omitempty" json:"maintainers,omitempty"`
+	Dependencies []Dependency `yaml:"dependencies,omitempty" json:"dependencies,omitempty"`
+	Arch         []string     `yaml:"arch" json:"arch"`
+	Matrix       *Matrix      `yaml:"matrix,omitempty" json:"matrix,omitempty"`
+	Readme       *Readme      `yaml:"readme,omitempty" json:"readme,omitempty"`
+	Hooks        Hooks        `yaml:"hooks,omitempty" json:"hooks,omitempty"`
+	// Targets are the settings of the structured arch entries, by platform.
+	Targets map[string]Target `yaml:"-" json:"-"`
+}
+
+type Maintainer struct {
+	Name  string `yaml:"name" json:"name"`
+	Email string `yaml:"email" json:"email"`
+	Role  string `yaml:"role,omitempty" json:"role,omitempty"`
+}
+
+// String is the maintainer as "Name <email> (role)", the role being optional.
+func (that Maintainer) String() string {
+	if that.Role == "" {
+		return fmt.Sprintf("%s <%s>", that.Name, that.Email)
+	}
+	return fmt.Sprintf("%s <%s> (%s)", that.Name, that.Email, that.Role)
+}
+
+// Dependency is a module required directly by the package.
+type Dependency struct {
+	Path    string `yaml:"path" json:"path"`
+	Version string `yaml:"version" json:"version"`
+}
+
+// Matrix lists build targets the way CI matrices do: every os with every
+// arch, plus the include entries, minus the exclude ones. An exclude entry is
+// os/arch, either side possibly *.
+type Matrix struct {
+	OS      []string `yaml:"os,omitempty" json:"os,omitempty"`
+	Arch    []string `yaml:"arch,omitempty" json:"arch,omitempty"`
+	Include []string `yaml:"include,omitempty" json:"include,omitempty"`
+	Exclude []string `yaml:"exclude,omitempty" json:"exclude,omitempty"`
+}
+
+// Readme is how the README of the package is assembled: the named section
+// templates in order, and the project files overriding some of them.
+type Readme struct {
+	Sections  []string          `yaml:"sections,omitempty" json:"sections,omitempty"`
+	Templates map[string]string `yaml:"templates,omitempty" json:"templates,omitempty"`
+}
+
+// Hooks are shell commands run before or after a gopi command, keyed by
+// pre-<command> or post-<command>, e.g. post-readme.
+type Hooks map[string]string
+
+// Diagnostic is a single problem found in the package info.
+type Diagnostic struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (that Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s [%s]", that.Field, that.Message, that.Code)
+}
@@ -0,0 +1,174 @@
+package pkginfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"gov/platform"
+)
+
+// Target is the build configuration of one arch entry. In pkg.info an entry
+// is either the plain platform string or a mapping with the platform under
+// target and its settings:
+//
+//	arch:
+//	    - linux/amd64
+//	    - target: linux/arm64
+//	      tags: [netgo]
+//	      cgo: false
+//	      env: {GOARM64: v8.2}
+//	      ldflags: -s -w
+type Target struct {
+	Platform string            `yaml:"target" json:"target"`
+	Tags     []string          `yaml:"tags,omitempty" json:"tags,omitempty"`
+	CGO      *bool             `yaml:"cgo,omitempty" json:"cgo,omitempty"`
+	Env      map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+	Ldflags  string            `yaml:"ldflags,omitempty" json:"ldflags,omitempty"`
+}
+
+// plain is Info without its custom encoding.
+type plain Info
+
+// Target is the build configuration of the arch entry naming platform, in
+// any of the forms platform.Split accepts; a plain entry has no settings.
+func (that *Info) Target(target string) Target {
+	if t, ok := that.Targets[target]; ok {
+		return t
+	}
+	for name, t := range that.Targets {
+		if platform.Canonical(name) == platform.Canonical(target) {
+			return t
+		}
+	}
+	return Target{Platform: target}
+}
+
+// configured tells a target with settings from a plain platform.
+func (that Target) configured() bool {
+	return len(that.Tags) > 0 || that.CGO != nil || len(that.Env) > 0 || that.Ldflags != ""
+}
+
+// setTarget records an arch entry, returning its platform.
+func (that *Info) setTarget(t Target) (string, error) {
+	if t.Platform == "" {
+		return "", fmt.Errorf("an arch entry has no target")
+	}
+	if t.configured() {
+		if that.Targets == nil {
+			that.Targets = map[string]Target{}
+		}
+		that.Targets[t.Platform] = t
+	}
+	return t.Platform, nil
+}
+
+// entries are the arch entries as written: the platform, or the target
+// when it has settings.
+func (that *Info) entries() []interface{} {
+	if that.Arch == nil {
+		return nil
+	}
+	res := make([]interface{}, 0, len(that.Arch))
+	for _, a := range that.Arch {
+		if t := that.Target(a); t.configured() {
+			t.Platform = a
+			res = append(res, t)
+		} else {
+			res = append(res, a)
+		}
+	}
+	return res
+}
+
+func (that *Info) UnmarshalYAML(node *yaml.Node) error {
+	var arch *yaml.Node
+	if node.Kind == yaml.MappingNode {
+		if i := indexOf(node, "arch"); i >= 0 {
+			arch = node.Content[i+1]
+			// the entries are decoded here, the rest as usual
+			stripped := *node
+			stripped.Content = append(append([]*yaml.Node(nil), node.Content[:i]...), node.Content[i+2:]...)
+			node = &stripped
+		}
+	}
+	if err := node.Decode((*plain)(that)); err != nil {
+		return err
+	}
+	that.Arch, that.Targets = nil, nil
+	if arch == nil || arch.Kind != yaml.SequenceNode {
+		if arch != nil && arch.Tag != "!!null" {
+			return fmt.Errorf("line %d: arch must be a list", arch.Line)
+		}
+		return nil
+	}
+	that.Arch = []string{}
+	for _, item := range arch.Content {
+		t := Target{}
+		var err error
+		if item.Kind == yaml.ScalarNode {
+			err = item.Decode(&t.Platform)
+		} else {
+			err = item.Decode(&t)
+		}
+		if err != nil {
+			return err
+		}
+		name, err := that.setTarget(t)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", item.Line, err)
+		}
+		that.Arch = append(that.Arch, name)
+	}
+	return nil
+}
+
+func (that Info) MarshalYAML() (interface{}, error) {
+	var node yaml.Node
+	if err := node.Encode((*plain)(&that)); err != nil {
+		return nil, err
+	}
+	if i := indexOf(&node, "arch"); i >= 0 && len(that.Targets) > 0 {
+		var arch yaml.Node
+		if err := arch.Encode(that.entries()); err != nil {
+			return nil, err
+		}
+		node.Content[i+1] = &arch
+	}
+	return &node, nil
+}
+
+func (that *Info) UnmarshalJSON(raw []byte) error {
+	aux := struct {
+		*plain
+		Arch []json.RawMessage `json:"arch"`
+	}{plain: (*plain)(that)}
+	if err := json.Unmarshal(raw, &aux); err != nil {
+		return err
+	}
+	that.Arch, that.Targets = nil, nil
+	if aux.Arch == nil {
+		return nil
+	}
+	that.Arch = []string{}
+	for _, item := range aux.Arch {
+		t := Target{}
+		if err := json.Unmarshal(item, &t.Platform); err != nil {
+			if err = json.Unmarshal(item, &t); err != nil {
+				return err
+			}
+		}
+		name, err := that.setTarget(t)
+		if err != nil {
+			return err
+		}
+		that.Arch = append(that.Arch, name)
+	}
+	return nil
+}
+
+func (that Info) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		*plain
+		Arch []interface{} `json:"arch"`
+	}{plain: (*plain)(&that), Arch: that.entries()})
+}
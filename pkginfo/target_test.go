@@ -0,0 +1,77 @@
+package pkginfo
+
+import (
+	"strings"
+	"testing"
+)
+
+const tTargets = `name: gopi
+version: 1.2.3
+arch:
+    # the edge devices
+    - target: linux/arm64
+      tags: [netgo, osusergo]
+      cgo: false
+      env:
+        GOARM64: v8.2
+      ldflags: -s -w
+    - darwin_arm64
+`
+
+func TestTarget_yaml(t *testing.T) {
+	info, err := Parse([]byte(tTargets))
+	if err != nil || strings.Join(info.Arch, " ") != "linux/arm64 darwin_arm64" {
+		t.Fatal(info, err)
+	}
+	arm := info.Target("linux_arm64")
+	if len(arm.Tags) != 2 || arm.CGO == nil || *arm.CGO || arm.Env["GOARM64"] != "v8.2" || arm.Ldflags != "-s -w" {
+		t.Fatal(arm)
+	}
+	if plain := info.Target("darwin_arm64"); plain.Platform != "darwin_arm64" || plain.configured() {
+		t.Fatal(plain)
+	}
+
+	raw, err := Marshal(info, "yaml")
+	if err != nil || !strings.Contains(string(raw), "    - target: linux/arm64\n      tags:") || !strings.Contains(string(raw), "    - darwin_arm64\n") {
+		t.Fatal(string(raw), err)
+	}
+	back, err := Parse(raw)
+	if err != nil || back.Target("linux/arm64").Ldflags != "-s -w" {
+		t.Fatal(err)
+	}
+}
+
+func TestTarget_json(t *testing.T) {
+	info, _ := Parse([]byte(tTargets))
+	raw, err := Marshal(info, "json")
+	if err != nil || !strings.Contains(string(raw), `"target": "linux/arm64"`) || !strings.Contains(string(raw), `"darwin_arm64"`) {
+		t.Fatal(string(raw), err)
+	}
+	back, err := Parse(raw)
+	if err != nil || len(back.Arch) != 2 || back.Target("linux/arm64").Env["GOARM64"] != "v8.2" {
+		t.Fatal(back, err)
+	}
+}
+
+func TestTarget_patch(t *testing.T) {
+	info, _ := Parse([]byte(tTargets))
+	info.Version = "1.3.0"
+	raw, err := Patch([]byte(tTargets), info)
+	if err != nil || !strings.Contains(string(raw), "# the edge devices") || !strings.Contains(string(raw), "cgo: false") {
+		t.Fatal(string(raw), err)
+	}
+}
+
+func TestTarget_invalid(t *testing.T) {
+	if _, err := Parse([]byte("arch:\n    - tags: [netgo]\n")); err == nil {
+		t.Fatal("expected a missing target error")
+	}
+	if _, err := Parse([]byte("arch: linux/amd64\n")); err == nil {
+		t.Fatal("expected a list error")
+	}
+	info, _ := Parse([]byte("arch:\n    - target: linux/amd64\n      tags: [\"a b\"]\n      env: {GOOS: linux}\n"))
+	d := Validate(info)
+	if len(d) < 2 || d[len(d)-2].Field != "arch[0].env" || d[len(d)-1].Field != "arch[0].tags" {
+		t.Fatal(d)
+	}
+}
@@ -0,0 +1,225 @@
+package pkginfo
+
+import (
+	"fmt"
+	"gov/platform"
+	"gov/validator"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ScpLike matches the scp-like user@host:path form of ssh remotes, capturing
+// the host and the path.
+var ScpLike = regexp.MustCompile(`^(?:[\w.-]+@)?([\w.-]+):(.+)$`)
+
+var isChannel = regexp.MustCompile(`^[0-9A-Za-z-]+$`)
+
+var isKeyword = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,34}$`)
+
+var isHook = regexp.MustCompile(`^(pre|post)-[a-z][a-z-]*$`)
+
+var isBuildTag = regexp.MustCompile(`^[\w.]+$`)
+
+var isEnvName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+const maxKeywords = 20
+
+var fieldOrder = []string{"name", "version", "channel", "description", "keywords", "tenant", "repo", "type", "license", "maintainers", "dependencies", "arch", "matrix", "readme", "hooks"}
+
+// Validate checks info and reports every problem at once, ordered as the
+// fields appear in pkg.info. Architectures are only checked against archList
+// when one is given.
+func Validate(info *Info, archList ...string) []Diagnostic {
+	var res []Diagnostic
+	add := func(field string, code string, format string, args ...interface{}) {
+		res = append(res, Diagnostic{Field: field, Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for field, value := range map[string]string{"name": info.Name, "version": info.Version, "tenant": info.Tenant} {
+		if strings.TrimSpace(value) == "" {
+			add(field, "required", "is required")
+		}
+	}
+	if info.Version != "" && validator.Semver(info.Version) != nil {
+		add("version", "semver", "%q is not a valid semver version", info.Version)
+	}
+	if info.Channel != "" && !isChannel.MatchString(info.Channel) {
+		add("channel", "format", "%q must be letters, digits and dashes", info.Channel)
+	}
+	seen := map[string]bool{}
+	for i, k := range info.Keywords {
+		switch {
+		case !isKeyword.MatchString(k):
+			add(fmt.Sprintf("keywords[%d]", i), "format", "%q must be lowercase letters, digits and dashes, at most 35 characters", k)
+		case seen[k]:
+			add(fmt.Sprintf("keywords[%d]", i), "duplicate", "%q is listed twice", k)
+		}
+		seen[k] = true
+	}
+	if len(info.Keywords) > maxKeywords {
+		add("keywords", "max", "at most %d keywords are allowed, got %d", maxKeywords, len(info.Keywords))
+	}
+	if info.Repo != "" && !validRepo(info.Repo) {
+		add("repo", "url", "%q is not a valid repository url", info.Repo)
+	}
+	if info.Type != "" && !contains(Types, info.Type) {
+		add("type", "enum", "%q is not one of %s", info.Type, strings.Join(Types, ", "))
+	}
+	if info.License != "" {
+		if err := ValidLicense(info.License); err != nil {
+			add("license", "spdx", "%s", err.Error())
+		}
+	}
+	for i, m := range info.Maintainers {
+		if strings.TrimSpace(m.Name) == "" {
+			add(fmt.Sprintf("maintainers[%d].name", i), "required", "is required")
+		}
+		if !validEmail(m.Email) {
+			add(fmt.Sprintf("maintainers[%d].email", i), "email", "%q is not a valid email address", m.Email)
+		}
+	}
+	for i, d := range info.Dependencies {
+		if strings.TrimSpace(d.Path) == "" {
+			add(fmt.Sprintf("dependencies[%d].path", i), "required", "is required")
+		}
+		if strings.TrimSpace(d.Version) == "" {
+			add(fmt.Sprintf("dependencies[%d].version", i), "required", "is required")
+		}
+	}
+	if len(archList) > 0 {
+		for i, a := range info.Arch {
+			if !platform.Contains(archList, a) {
+				add(fmt.Sprintf("arch[%d]", i), "arch", "unknown architecture %q", a)
+			}
+		}
+	}
+	if m := info.Matrix; m != nil {
+		if len(m.OS) > 0 && len(m.Arch) == 0 {
+			add("matrix.arch", "required", "is required with matrix.os")
+		}
+		if len(m.Arch) > 0 && len(m.OS) == 0 {
+			add("matrix.os", "required", "is required with matrix.arch")
+		}
+		for i, rule := range m.Exclude {
+			if goos, goarch, ok := strings.Cut(rule, "/"); !ok || goos == "" || goarch == "" {
+				add(fmt.Sprintf("matrix.exclude[%d]", i), "format", "%q must be os/arch, either possibly *", rule)
+			}
+		}
+		if len(archList) > 0 {
+			for _, target := range m.Targets() {
+				if !platform.Contains(archList, target) {
+					add("matrix", "arch", "unknown architecture %q", target)
+				}
+			}
+		}
+	}
+	for i, a := range info.Arch {
+		t := info.Target(a)
+		for _, tag := range t.Tags {
+			if !isBuildTag.MatchString(tag) {
+				add(fmt.Sprintf("arch[%d].tags", i), "format", "%q is not a build tag", tag)
+			}
+		}
+		for name := range t.Env {
+			switch {
+			case !isEnvName.MatchString(name):
+				add(fmt.Sprintf("arch[%d].env", i), "format", "%q is not an environment variable name", name)
+			case name == "GOOS" || name == "GOARCH" || name == "CGO_ENABLED":
+				add(fmt.Sprintf("arch[%d].env", i), "reserved", "%s is set by the target and cgo", name)
+			}
+		}
+	}
+	if info.Readme != nil {
+		seen = map[string]bool{}
+		for i, name := range info.Readme.Sections {
+			switch {
+			case !isKeyword.MatchString(name):
+				add(fmt.Sprintf("readme.sections[%d]", i), "format", "%q must be lowercase letters, digits and dashes", name)
+			case seen[name]:
+				add(fmt.Sprintf("readme.sections[%d]", i), "duplicate", "%q is listed twice", name)
+			}
+			seen[name] = true
+		}
+		for name, file := range info.Readme.Templates {
+			if strings.TrimSpace(file) == "" {
+				add(fmt.Sprintf("readme.templates[%s]", name), "required", "is required")
+			}
+		}
+	}
+
+	for name, cmd := range info.Hooks {
+		switch {
+		case !isHook.MatchString(name):
+			add(fmt.Sprintf("hooks[%s]", name), "format", "%q must be pre-<command> or post-<command>", name)
+		case strings.TrimSpace(cmd) == "":
+			add(fmt.Sprintf("hooks[%s]", name), "required", "is required")
+		}
+	}
+
+	SortDiagnostics(res)
+	return res
+}
+
+func validEmail(st string) bool {
+	a, err := mail.ParseAddress(st)
+	return err == nil && a.Address == st && a.Name == ""
+}
+
+// ValidRepo checks that st is a repository url: an http(s), ssh or git url
+// or the scp-like user@host:path of ssh remotes.
+func ValidRepo(st string) error {
+	if !validRepo(st) {
+		return fmt.Errorf("%q is not a valid repository url", st)
+	}
+	return nil
+}
+
+func validRepo(st string) bool {
+	if strings.ContainsAny(st, " \t\n") {
+		return false
+	}
+	if m := ScpLike.FindStringSubmatch(st); m != nil && !strings.Contains(st, "://") {
+		return strings.Contains(m[1], ".") && m[2] != ""
+	}
+	u, err := url.Parse(st)
+	if err != nil || u.Host == "" || strings.Trim(u.Path, "/") == "" {
+		return false
+	}
+	switch u.Scheme {
+	case "http", "https", "ssh", "git", "git+ssh":
+		return true
+	}
+	return false
+}
+
+func contains(s []string, str string) bool {
+	for _, v := range s {
+		if v == str {
+			return true
+		}
+	}
+	return false
+}
+
+// SortDiagnostics orders diagnostics as the fields appear in pkg.info.
+func SortDiagnostics(d []Diagnostic) {
+	rank := func(field string) int {
+		base, _, _ := strings.Cut(field, "[")
+		base, _, _ = strings.Cut(base, ".")
+		for i, f := range fieldOrder {
+			if f == base {
+				return i
+			}
+		}
+		return len(fieldOrder)
+	}
+	sort.SliceStable(d, func(i, j int) bool {
+		if ri, rj := rank(d[i].Field), rank(d[j].Field); ri != rj {
+			return ri < rj
+		}
+		return d[i].Field < d[j].Field
+	})
+}
@@ -0,0 +1,62 @@
+package platform
+
+import (
+	_ "embed"
+	"strings"
+)
+
+// snapshot is the output of `go tool dist list`, used when no go toolchain
+// is available to ask.
+//
+//go:embed dist.txt
+var snapshot []byte
+
+// Known returns the embedded GOOS/GOARCH snapshot.
+func Known() []string {
+	return Parse(snapshot)
+}
+
+// Parse reads the output of `go tool dist list`, one goos/goarch per line.
+func Parse(out []byte) []string {
+	var res []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); strings.Contains(line, "/") {
+			res = append(res, line)
+		}
+	}
+	return res
+}
+
+// Split turns an architecture entry into GOOS and GOARCH. Besides goos/goarch
+// the legacy goos_goarch form is accepted and a bare goos means amd64.
+func Split(target string) (string, string) {
+	goos, goarch, ok := strings.Cut(target, "/")
+	if !ok {
+		goos, goarch, ok = strings.Cut(target, "_")
+	}
+	if !ok || goarch == "" {
+		goarch = "amd64"
+	}
+	return goos, goarch
+}
+
+// Canonical is the goos/goarch form of an architecture entry.
+func Canonical(target string) string {
+	goos, goarch := Split(target)
+	return goos + "/" + goarch
+}
+
+// Contains reports whether target names one of the platforms of list, in any
+// of the forms Split accepts.
+func Contains(list []string, target string) bool {
+	if strings.TrimSpace(target) == "" {
+		return false
+	}
+	c := Canonical(target)
+	for _, p := range list {
+		if Canonical(p) == c {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package platform
+
+import "testing"
+
+func TestKnown(t *testing.T) {
+	known := Known()
+	for _, p := range []string{"linux/amd64", "linux/riscv64", "windows/arm64", "darwin/arm64"} {
+		if !Contains(known, p) {
+			t.Fatal(p)
+		}
+	}
+	if Contains(known, "plan9/mips") || Contains(known, "") {
+		t.Fail()
+	}
+}
+
+func TestSplit(t *testing.T) {
+	for target, want := range map[string]string{
+		"linux/riscv64": "linux riscv64",
+		"linux_arm64":   "linux arm64",
+		"windows":       "windows amd64",
+	} {
+		goos, goarch := Split(target)
+		if goos+" "+goarch != want {
+			t.Fatal(target, goos, goarch)
+		}
+	}
+}
+
+func TestContains_legacy(t *testing.T) {
+	if !Contains([]string{"linux_amd64", "windows"}, "windows/amd64") || !Contains([]string{"linux/amd64"}, "linux_amd64") {
+		t.Fail()
+	}
+}
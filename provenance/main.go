@@ -0,0 +1,46 @@
+package provenance
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	StatementType = "https://in-toto.io/Statement/v1"
+	PredicateType = "https://slsa.dev/provenance/v1"
+	// BuildType identifies gopi build and the meaning of its parameters.
+	BuildType = "https://github.com/mtag-io/gopi/buildtypes/build/v1"
+)
+
+// New is a provenance statement for the subjects, built by builder between
+// started and finished from the given materials.
+func New(builder string, subjects []Subject, params map[string]interface{}, materials []Resource, started time.Time, finished time.Time) *Statement {
+	return &Statement{
+		Type:          StatementType,
+		Subject:       subjects,
+		PredicateType: PredicateType,
+		Predicate: Predicate{
+			BuildDefinition: BuildDefinition{
+				BuildType:            BuildType,
+				ExternalParameters:   params,
+				ResolvedDependencies: materials,
+			},
+			RunDetails: RunDetails{
+				Builder: Builder{ID: builder},
+				Metadata: Metadata{
+					StartedOn:  started.UTC().Format(time.RFC3339),
+					FinishedOn: finished.UTC().Format(time.RFC3339),
+				},
+			},
+		},
+	}
+}
+
+// Marshal renders the statement as indented JSON.
+func (that *Statement) Marshal() ([]byte, error) {
+	raw, err := json.MarshalIndent(that, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(raw, '\n'), nil
+}
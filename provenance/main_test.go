@@ -0,0 +1,28 @@
+package provenance
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	st := New("https://ci.example.com/run/1", []Subject{{Name: "demo", Digest: map[string]string{"sha256": "ab"}}},
+		map[string]interface{}{"targets": []string{"linux/amd64"}}, nil, start, start.Add(time.Minute))
+	raw, err := st.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back map[string]interface{}
+	if err = json.Unmarshal(raw, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back["_type"] != StatementType || back["predicateType"] != PredicateType {
+		t.Fatal(string(raw))
+	}
+	meta := back["predicate"].(map[string]interface{})["runDetails"].(map[string]interface{})["metadata"].(map[string]interface{})
+	if meta["finishedOn"] != "2026-01-02T03:05:05Z" {
+		t.Fatal(meta)
+	}
+}
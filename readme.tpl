@@ -3,12 +3,39 @@
 <br/>
 </p>
 
-<h1 align="center" width="100%">{{ .Name }}</h1>
-<p align="center" width="100%">
-    <img  src="https://img.shields.io/static/v1?label=Version&message={{.Version }}&color=blue" alt="version"/>
-</p>
+<h1 align="center" width="100%">{{ upper .Name }}</h1>
+{{ if .Badges }}<p align="center" width="100%">
+{{ range .Badges }}    {{ if .Link }}<a href="{{ .Link }}">{{ end }}<img src="{{ .Image }}" alt="{{ .Label }}"/>{{ if .Link }}</a>{{ end }}
+{{ end }}</p>{{ end }}
+
+<h3 align="center" width="100%">{{ .Summary }}</h3>
+{{ if ne .Summary .Description }}
+{{ multiline .Description }}
+{{ end }}
+{{ if .QuickStart }}
+## Quick start
+
+{{ .QuickStart }}
+{{ end }}
+{{ if .Architecture }}
+## Architecture
 
-<h3 align="center" width="100%">{{ .Description }}</h3>
+{{ .Architecture }}
+{{ end }}
+{{ if .Dependencies }}
+## Dependencies
 
+| Module | Version |
+|--------|---------|
+{{ range .Dependencies }}| {{ .Path }} | {{ .Version }} |
+{{ end }}{{ end }}
+{{ if .Maintainers }}
+## Maintainers
 
+{{ range .Maintainers }}- [{{ .Name }}](mailto:{{ .Email }}){{ if .Role }} - {{ .Role }}{{ end }}
+{{ end }}{{ end }}
+{{ if .License }}
+## License
 
+{{ .License }}
+{{ end }}
@@ -0,0 +1,32 @@
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// New is a client of the registry at url, sending token as a bearer token
+// when one is given.
+func New(url string, token string) *Class {
+	return &Class{
+		url:    strings.TrimSuffix(url, "/"),
+		token:  token,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// ParseRef parses a tenant/name[@version] package identifier.
+func ParseRef(raw string) (Ref, error) {
+	var ref Ref
+	id, ver, _ := strings.Cut(strings.TrimSpace(raw), "@")
+	tenant, name, ok := strings.Cut(id, "/")
+	if !ok || tenant == "" || name == "" || strings.Contains(name, "/") {
+		return ref, fmt.Errorf("invalid package identifier %q, expected tenant/name[@version]", raw)
+	}
+	if strings.Contains(raw, "@") && ver == "" {
+		return ref, fmt.Errorf("invalid package identifier %q, empty version", raw)
+	}
+	return Ref{Tenant: tenant, Name: name, Version: ver}, nil
+}
@@ -0,0 +1,72 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gov/pkginfo"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// maxBody caps the size of a metadata response.
+const maxBody = 1 << 20
+
+func (that Ref) String() string {
+	if that.Version == "" {
+		return that.Tenant + "/" + that.Name
+	}
+	return that.Tenant + "/" + that.Name + "@" + that.Version
+}
+
+// Path is the registry endpoint of the package metadata:
+// /packages/{tenant}/{name}, with a trailing /{version} for a given release.
+func (that Ref) Path() string {
+	p := "/packages/" + url.PathEscape(that.Tenant) + "/" + url.PathEscape(that.Name)
+	if that.Version != "" {
+		p += "/" + url.PathEscape(that.Version)
+	}
+	return p
+}
+
+// Fetch retrieves the package info of ref, the latest release when no
+// version is given.
+func (that *Class) Fetch(ctx context.Context, ref Ref) (*pkginfo.Info, error) {
+	if that.url == "" {
+		return nil, errors.New("no registry configured, set registry in the gopi configuration")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, that.url+ref.Path(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if that.token != "" {
+		req.Header.Set("Authorization", "Bearer "+that.token)
+	}
+	res, err := that.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to reach the registry: %w", err)
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("package %s not found in %s", ref, that.url)
+	case res.StatusCode == http.StatusUnauthorized:
+		return nil, fmt.Errorf("registry %s refused the request for %s, set $GOPI_REGISTRY_TOKEN to its token", that.url, ref)
+	case res.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("registry responded %s for %s", res.Status, ref)
+	}
+	raw, err := io.ReadAll(io.LimitReader(res.Body, maxBody))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the registry response: %w", err)
+	}
+	info, err := pkginfo.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid package info for %s: %w", ref, err)
+	}
+	return info, nil
+}
@@ -0,0 +1,49 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseRef(t *testing.T) {
+	ref, err := ParseRef("mtag/gopi@1.2.0")
+	if err != nil || ref.Tenant != "mtag" || ref.Name != "gopi" || ref.Version != "1.2.0" {
+		t.Fail()
+	}
+	if ref.Path() != "/packages/mtag/gopi/1.2.0" || ref.String() != "mtag/gopi@1.2.0" {
+		t.Fail()
+	}
+	for _, raw := range []string{"gopi", "mtag/", "/gopi", "a/b/c", "mtag/gopi@"} {
+		if _, err = ParseRef(raw); err == nil {
+			t.Fatalf("%q accepted", raw)
+		}
+	}
+}
+
+func TestFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/packages/mtag/gopi" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"gopi","version":"1.2.0","tenant":"mtag"}`))
+	}))
+	defer srv.Close()
+
+	reg := New(srv.URL+"/", "")
+	info, err := reg.Fetch(context.Background(), Ref{Tenant: "mtag", Name: "gopi"})
+	if err != nil || info.Version != "1.2.0" {
+		t.Fatal(err)
+	}
+	if _, err = reg.Fetch(context.Background(), Ref{Tenant: "mtag", Name: "other"}); err == nil {
+		t.Fail()
+	}
+}
+
+func TestFetch_unconfigured(t *testing.T) {
+	if _, err := New("", "").Fetch(context.Background(), Ref{Tenant: "a", Name: "b"}); err == nil {
+		t.Fail()
+	}
+}
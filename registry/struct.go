@@ -0,0 +1,16 @@
+package registry
+
+import "net/http"
+
+type Class struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// Ref identifies a package in the registry: tenant/name[@version].
+type Ref struct {
+	Tenant  string
+	Name    string
+	Version string
+}
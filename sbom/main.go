@@ -0,0 +1,161 @@
+package sbom
+
+import (
+	"bytes"
+	"crypto/rand"
+	"debug/buildinfo"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Formats are the supported SBOM formats.
+var Formats = []string{"cyclonedx", "spdx"}
+
+// Marshal renders doc as CycloneDX 1.5 or SPDX 2.3 JSON.
+func Marshal(doc *Document, format string) ([]byte, error) {
+	var v interface{}
+	switch format {
+	case "cyclonedx":
+		v = cycloneDX(doc)
+	case "spdx":
+		v = spdx(doc)
+	default:
+		return nil, fmt.Errorf("unknown SBOM format %q, expected one of %s", format, strings.Join(Formats, ", "))
+	}
+	raw, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(raw, '\n'), nil
+}
+
+// ParseModules reads the output of `go list -m all`, skipping the main module
+// and following replacements.
+func ParseModules(out []byte) []Component {
+	var res []Component
+	for _, line := range strings.Split(string(out), "\n") {
+		// path version [=> replacement [version]]
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		c := Component{Path: fields[0], Version: fields[1]}
+		if len(fields) >= 5 && fields[2] == "=>" {
+			c.Path, c.Version = fields[3], fields[4]
+		}
+		res = append(res, c)
+	}
+	return res
+}
+
+// FromBinary reads the module information go embeds in a binary.
+func FromBinary(raw []byte) (string, string, []Component, error) {
+	info, err := buildinfo.Read(bytes.NewReader(raw))
+	if err != nil {
+		return "", "", nil, err
+	}
+	var res []Component
+	for _, d := range info.Deps {
+		if d.Replace != nil {
+			d = d.Replace
+		}
+		res = append(res, Component{Path: d.Path, Version: d.Version, Hash: d.Sum})
+	}
+	return info.Main.Path, info.Main.Version, res, nil
+}
+
+func (that *Document) module() string {
+	if that.Module != "" {
+		return that.Module
+	}
+	return that.Name
+}
+
+func purl(path string, version string) string {
+	p := "pkg:golang/" + path
+	if version != "" {
+		p += "@" + version
+	}
+	return p
+}
+
+func uuid() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func cycloneDX(doc *Document) cdxBOM {
+	kind := "library"
+	if doc.Type == "cli" || doc.Type == "service" {
+		kind = "application"
+	}
+	ref := purl(doc.module(), doc.Version)
+	main := cdxComponent{Type: kind, BOMRef: ref, Name: doc.Name, Version: doc.Version, PURL: ref}
+	if doc.License != "" {
+		main.Licenses = []cdxLicense{{Expression: doc.License}}
+	}
+	bom := cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + uuid(),
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: doc.Created.UTC().Format(time.RFC3339),
+			Tools:     cdxTools{Components: []cdxComponent{{Type: "application", Name: "gopi"}}},
+			Component: main,
+		},
+		Components: []cdxComponent{},
+	}
+	deps := cdxDependency{Ref: main.BOMRef, DependsOn: []string{}}
+	for _, c := range doc.Components {
+		ref := purl(c.Path, c.Version)
+		comp := cdxComponent{Type: "library", BOMRef: ref, Name: c.Path, Version: c.Version, PURL: ref}
+		if c.Hash != "" {
+			comp.Properties = []cdxProperty{{Name: "go:sum", Value: c.Hash}}
+		}
+		bom.Components = append(bom.Components, comp)
+		deps.DependsOn = append(deps.DependsOn, ref)
+	}
+	bom.Dependencies = []cdxDependency{deps}
+	return bom
+}
+
+func spdx(doc *Document) spdxDocument {
+	declared := doc.License
+	if declared == "" {
+		declared = "NOASSERTION"
+	}
+	location := doc.Repo
+	if location == "" {
+		location = "NOASSERTION"
+	}
+	res := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              doc.Name,
+		DocumentNamespace: fmt.Sprintf("https://spdx.org/spdxdocs/%s-%s-%s", doc.Name, doc.Version, uuid()),
+		CreationInfo: spdxCreationInfo{
+			Created:  doc.Created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: gopi"},
+		},
+	}
+	pkg := func(id string, name string, module string, version string, license string, location string) spdxPackage {
+		return spdxPackage{SPDXID: id, Name: name, VersionInfo: version, DownloadLocation: location,
+			LicenseConcluded: "NOASSERTION", LicenseDeclared: license, CopyrightText: "NOASSERTION",
+			ExternalRefs: []spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: purl(module, version)}}}
+	}
+	res.Packages = append(res.Packages, pkg("SPDXRef-Package-main", doc.Name, doc.module(), doc.Version, declared, location))
+	res.Relationships = append(res.Relationships, spdxRelationship{"SPDXRef-DOCUMENT", "DESCRIBES", "SPDXRef-Package-main"})
+	for i, c := range doc.Components {
+		id := fmt.Sprintf("SPDXRef-Package-%d", i)
+		res.Packages = append(res.Packages, pkg(id, c.Path, c.Path, c.Version, "NOASSERTION", "NOASSERTION"))
+		res.Relationships = append(res.Relationships, spdxRelationship{"SPDXRef-Package-main", "DEPENDS_ON", id})
+	}
+	return res
+}
@@ -0,0 +1,73 @@
+package sbom
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+func tDoc() *Document {
+	return &Document{Name: "demo", Module: "github.com/acme/demo", Version: "1.2.0", Type: "cli", License: "MIT",
+		Created:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Components: ParseModules([]byte("github.com/acme/demo\ngolang.org/x/mod v0.8.0\ngopkg.in/yaml.v3 v3.0.1 => gopkg.in/yaml.v3 v3.0.2\n"))}
+}
+
+func TestParseModules(t *testing.T) {
+	c := tDoc().Components
+	if len(c) != 2 || c[0].Path != "golang.org/x/mod" || c[1].Version != "v3.0.2" {
+		t.Fatal(c)
+	}
+}
+
+func TestMarshal_cyclonedx(t *testing.T) {
+	raw, err := Marshal(tDoc(), "cyclonedx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var bom cdxBOM
+	if err = json.Unmarshal(raw, &bom); err != nil {
+		t.Fatal(err)
+	}
+	if bom.SpecVersion != "1.5" || bom.Metadata.Component.PURL != "pkg:golang/github.com/acme/demo@1.2.0" ||
+		bom.Metadata.Component.Type != "application" || len(bom.Components) != 2 || len(bom.Dependencies[0].DependsOn) != 2 {
+		t.Fatal(string(raw))
+	}
+	if bom.Metadata.Timestamp != "2026-01-02T03:04:05Z" || bom.Components[1].PURL != "pkg:golang/gopkg.in/yaml.v3@v3.0.2" {
+		t.Fatal(string(raw))
+	}
+}
+
+func TestMarshal_spdx(t *testing.T) {
+	raw, err := Marshal(tDoc(), "spdx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc spdxDocument
+	if err = json.Unmarshal(raw, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.SPDXVersion != "SPDX-2.3" || len(doc.Packages) != 3 || doc.Packages[0].LicenseDeclared != "MIT" || len(doc.Relationships) != 3 {
+		t.Fatal(string(raw))
+	}
+	if _, err = Marshal(tDoc(), "swid"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestFromBinary(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	raw, err := os.ReadFile(exe)
+	if err != nil {
+		t.Skip(err)
+	}
+	if _, _, _, err = FromBinary(raw); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err = FromBinary([]byte("not a binary")); err == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"net"
+	"strings"
+)
+
+// New is a handler answering from backend, requiring the bearer token when
+// one is given.
+func New(backend Backend, token string) *Class {
+	return &Class{backend: backend, token: token}
+}
+
+// Loopback reports whether the listen address addr only accepts local
+// connections: localhost or a loopback ip, not every interface.
+func Loopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
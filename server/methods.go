@@ -0,0 +1,129 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"gov/inventory"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var errNotFound = errors.New("package not found")
+
+// catalogTTL is how long the catalog read from the backend is served before
+// it is read again.
+const catalogTTL = 5 * time.Second
+
+// ServeHTTP answers the read endpoints:
+//
+//	GET /packages?tenant=&name=&version=     the inventory entries, see inventory.Query
+//	GET /packages/{tenant}/{name}            the package info, as gopi fetch expects it
+//	GET /packages/{tenant}/{name}/{version}  the package info when at that version
+//	GET /packages/{tenant}/{name}/readme     the rendered README (markdown)
+func (that *Class) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		that.fail(w, http.StatusMethodNotAllowed, errors.New("only GET is supported"))
+		return
+	}
+	if !that.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="gopi"`)
+		that.fail(w, http.StatusUnauthorized, errors.New("a valid bearer token is required"))
+		return
+	}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] != "packages" || len(parts) == 2 || len(parts) > 4 {
+		that.fail(w, http.StatusNotFound, errors.New("unknown endpoint"))
+		return
+	}
+	inv, err := that.catalog()
+	if err != nil {
+		that.fail(w, http.StatusInternalServerError, err)
+		return
+	}
+	if len(parts) == 1 {
+		q := r.URL.Query()
+		found, err := inv.Query(inventory.Query{Tenant: q.Get("tenant"), Name: q.Get("name"), Constraint: q.Get("version")})
+		if err != nil {
+			that.fail(w, http.StatusBadRequest, err)
+			return
+		}
+		that.reply(w, found)
+		return
+	}
+
+	entry, ok := find(inv, parts[1], parts[2])
+	if !ok || (len(parts) == 4 && parts[3] != "readme" && parts[3] != entry.Version) {
+		that.fail(w, http.StatusNotFound, errNotFound)
+		return
+	}
+	if len(parts) == 4 && parts[3] == "readme" {
+		raw, err := that.backend.PackageReadme(r.Context(), entry)
+		if err != nil {
+			that.fail(w, http.StatusInternalServerError, err)
+			return
+		}
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		_, _ = w.Write(raw)
+		return
+	}
+	info, err := that.backend.PackageInfo(entry)
+	if err != nil {
+		that.fail(w, http.StatusInternalServerError, err)
+		return
+	}
+	that.reply(w, info)
+}
+
+func (that *Class) authorized(r *http.Request) bool {
+	if that.token == "" {
+		return true
+	}
+	h := r.Header.Get("Authorization")
+	if !strings.HasPrefix(h, "Bearer ") {
+		return false
+	}
+	got := strings.TrimPrefix(h, "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(got), []byte(that.token)) == 1
+}
+
+// catalog is the inventory catalog of the backend, read again once it is
+// older than catalogTTL. It is shared by the requests, which only read it.
+func (that *Class) catalog() (*inventory.Class, error) {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+	if that.inv != nil && time.Since(that.read) < catalogTTL {
+		return that.inv, nil
+	}
+	inv, err := that.backend.Inventory()
+	if err != nil {
+		return nil, err
+	}
+	that.inv, that.read = inv, time.Now()
+	return inv, nil
+}
+
+// find is the first catalog entry of tenant named name.
+func find(inv *inventory.Class, tenant string, name string) (inventory.Entry, bool) {
+	for _, e := range inv.Packages {
+		if strings.EqualFold(e.Tenant, tenant) && e.Name == name {
+			return e, true
+		}
+	}
+	return inventory.Entry{}, false
+}
+
+func (that *Class) reply(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func (that *Class) fail(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
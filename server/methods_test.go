@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"gov/inventory"
+	"gov/pkginfo"
+	"gov/registry"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeBackend struct{}
+
+func (fakeBackend) Inventory() (*inventory.Class, error) {
+	return &inventory.Class{Packages: []inventory.Entry{
+		{Name: "api", Version: "1.2.0", Tenant: "acme", Path: "/src/api"},
+		{Name: "tool", Version: "0.3.0", Tenant: "globex", Path: "/src/tool"},
+	}}, nil
+}
+
+func (fakeBackend) PackageInfo(e inventory.Entry) (*pkginfo.Info, error) {
+	if e.Name == "tool" {
+		return nil, errors.New("unreadable")
+	}
+	return &pkginfo.Info{Name: e.Name, Version: e.Version, Tenant: e.Tenant}, nil
+}
+
+func (fakeBackend) PackageReadme(_ context.Context, e inventory.Entry) ([]byte, error) {
+	return []byte("# " + strings.ToUpper(e.Name) + "\n"), nil
+}
+
+func get(t *testing.T, h http.Handler, target string, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServeHTTP(t *testing.T) {
+	h := New(fakeBackend{}, "")
+	var entries []inventory.Entry
+	rec := get(t, h, "/packages?tenant=acme", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil || len(entries) != 1 || entries[0].Name != "api" {
+		t.Fatal(rec.Body.String())
+	}
+	if rec = get(t, h, "/packages?version=>>1", ""); rec.Code != http.StatusBadRequest {
+		t.Fatal(rec.Code)
+	}
+	if rec = get(t, h, "/packages/acme/api/readme", ""); rec.Body.String() != "# API\n" || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/markdown") {
+		t.Fatal(rec.Body.String())
+	}
+	for target, code := range map[string]int{"/packages/acme/api/1.2.0": 200, "/packages/acme/api/1.0.0": 404, "/packages/acme/other": 404,
+		"/packages/globex/tool": 500, "/packages/acme": 404, "/other": 404} {
+		if rec = get(t, h, target, ""); rec.Code != code {
+			t.Fatal(target, rec.Code)
+		}
+	}
+	req := httptest.NewRequest(http.MethodPost, "/packages", nil)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatal(rec.Code)
+	}
+}
+
+func TestServeHTTP_token(t *testing.T) {
+	h := New(fakeBackend{}, "s3cret")
+	if rec := get(t, h, "/packages", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatal(rec.Code)
+	}
+	if rec := get(t, h, "/packages", "wrong"); rec.Code != http.StatusUnauthorized {
+		t.Fatal(rec.Code)
+	}
+	if rec := get(t, h, "/packages", "s3cret"); rec.Code != http.StatusOK {
+		t.Fatal(rec.Code)
+	}
+	// the bare token, without the Bearer scheme, is refused
+	req := httptest.NewRequest(http.MethodGet, "/packages", nil)
+	req.Header.Set("Authorization", "s3cret")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatal(rec.Code)
+	}
+}
+
+// gopi fetch can use gopi serve as its registry
+func TestServeHTTP_registry(t *testing.T) {
+	srv := httptest.NewServer(New(fakeBackend{}, ""))
+	defer srv.Close()
+	info, err := registry.New(srv.URL, "").Fetch(context.Background(), registry.Ref{Tenant: "acme", Name: "api", Version: "1.2.0"})
+	if err != nil || info.Version != "1.2.0" {
+		t.Fatal(info, err)
+	}
+
+	// and send it its token
+	secured := httptest.NewServer(New(fakeBackend{}, "s3cret"))
+	defer secured.Close()
+	ref := registry.Ref{Tenant: "acme", Name: "api"}
+	if _, err = registry.New(secured.URL, "").Fetch(context.Background(), ref); err == nil || !strings.Contains(err.Error(), "GOPI_REGISTRY_TOKEN") {
+		t.Fatal(err)
+	}
+	if info, err = registry.New(secured.URL, "s3cret").Fetch(context.Background(), ref); err != nil || info.Name != "api" {
+		t.Fatal(info, err)
+	}
+}
+
+type countingBackend struct {
+	fakeBackend
+	calls *int
+}
+
+func (that countingBackend) Inventory() (*inventory.Class, error) {
+	*that.calls++
+	return that.fakeBackend.Inventory()
+}
+
+func TestServeHTTP_catalog_cached(t *testing.T) {
+	calls := 0
+	h := New(countingBackend{calls: &calls}, "")
+	for i := 0; i < 3; i++ {
+		if rec := get(t, h, "/packages/acme/api", ""); rec.Code != http.StatusOK {
+			t.Fatal(rec.Code)
+		}
+	}
+	if calls != 1 {
+		t.Fatal(calls)
+	}
+	h.read = time.Time{}
+	get(t, h, "/packages", "")
+	if calls != 2 {
+		t.Fatal(calls)
+	}
+}
+
+func TestLoopback(t *testing.T) {
+	for addr, want := range map[string]bool{"127.0.0.1:8080": true, "localhost:80": true, "[::1]:8080": true,
+		":8080": false, "0.0.0.0:8080": false, "10.0.0.5:8080": false, "8080": false} {
+		if Loopback(addr) != want {
+			t.Fatal(addr)
+		}
+	}
+}
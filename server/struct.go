@@ -0,0 +1,26 @@
+package server
+
+import (
+	"context"
+	"gov/inventory"
+	"gov/pkginfo"
+	"sync"
+	"time"
+)
+
+// Backend is what the server answers from: the inventory catalog and the
+// package info and README of its packages.
+type Backend interface {
+	Inventory() (*inventory.Class, error)
+	PackageInfo(e inventory.Entry) (*pkginfo.Info, error)
+	PackageReadme(ctx context.Context, e inventory.Entry) ([]byte, error)
+}
+
+// Class is the HTTP handler of gopi serve.
+type Class struct {
+	backend Backend
+	token   string
+	mu      sync.Mutex
+	inv     *inventory.Class
+	read    time.Time
+}
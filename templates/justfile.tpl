@@ -0,0 +1,28 @@
+# {{ .Name }} justfile - generated by gopi from {{ .PkgInfoFile }}.
+# Regenerate with `gopi generate justfile` after changing the package info.
+
+set export
+
+NAME := "{{ .Name }}"
+VERSION := "{{ .Version }}"
+BINARY := "{{ .BinaryName }}"
+GOPI := env_var_or_default("GOPI", "gopi")
+
+build:
+    go build -o bin/$BINARY .
+{{ if .InstallPath }}
+install: build
+    install -m 0755 bin/$BINARY {{ .InstallPath }}
+{{ end }}
+test:
+    go test ./...
+
+readme:
+    $GOPI -readme
+
+validate:
+    $GOPI validate
+    go vet ./...
+
+release: validate test readme
+    git tag -a v$VERSION -m "$NAME v$VERSION"
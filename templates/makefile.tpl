@@ -0,0 +1,28 @@
+# {{ .Name }} Makefile - generated by gopi from {{ .PkgInfoFile }}.
+# Regenerate with `gopi generate makefile` after changing the package info.
+
+NAME    := {{ .Name }}
+VERSION := {{ .Version }}
+BINARY  := {{ .BinaryName }}
+GOPI    ?= gopi
+
+.PHONY: build install test release readme validate
+
+build:
+	go build -o bin/$(BINARY) .
+{{ if .InstallPath }}
+install: build
+	install -m 0755 bin/$(BINARY) {{ .InstallPath }}
+{{ end }}
+test:
+	go test ./...
+
+readme:
+	$(GOPI) -readme
+
+validate:
+	$(GOPI) validate
+	go vet ./...
+
+release: validate test readme
+	git tag -a v$(VERSION) -m "$(NAME) v$(VERSION)"
@@ -0,0 +1,11 @@
+{{ with .API }}
+## API
+
+{{ .Synopsis }}
+{{ range .Symbols }}
+### {{ .Name }}
+
+{{ codeblock "go" .Decl }}
+{{ if .Doc }}
+{{ .Doc }}
+{{ end }}{{ end }}{{ end }}
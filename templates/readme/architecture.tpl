@@ -0,0 +1,5 @@
+{{ if .Architecture }}
+## Architecture
+
+{{ .Architecture }}
+{{ end }}
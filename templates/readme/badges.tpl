@@ -0,0 +1,3 @@
+{{ if .Badges }}<p align="center" width="100%">
+{{ range .Badges }}    {{ if .Link }}<a href="{{ .Link }}">{{ end }}<img src="{{ .Image }}" alt="{{ .Label }}"/>{{ if .Link }}</a>{{ end }}
+{{ end }}</p>{{ end }}
@@ -0,0 +1,7 @@
+{{ if .Dependencies }}
+## Dependencies
+
+| Module | Version |
+|--------|---------|
+{{ range .Dependencies }}| {{ .Path }} | {{ .Version }} |
+{{ end }}{{ end }}
@@ -0,0 +1,5 @@
+
+<h3 align="center" width="100%">{{ .Summary }}</h3>
+{{ if ne .Summary .Description }}
+{{ multiline .Description }}
+{{ end }}
@@ -0,0 +1,6 @@
+<p align="center" width="100%">
+    <img  src="{{ .Icon }}" alt="logo">
+<br/>
+</p>
+
+<h1 align="center" width="100%">{{ upper .Name }}</h1>
@@ -0,0 +1,9 @@
+{{ if or .Downloads .InstallPath }}
+## Install
+{{ if .Downloads }}
+| Platform | Download |
+|----------|----------|
+{{ range .Downloads }}| {{ .OS }}/{{ .Arch }} | [{{ .Target }}]({{ .URL }}) |
+{{ end }}{{ end }}{{ if .InstallPath }}
+Copy the `{{ .BinaryName }}` binary to `{{ .InstallPath }}`.
+{{ end }}{{ end }}
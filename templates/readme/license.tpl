@@ -0,0 +1,5 @@
+{{ if .License }}
+## License
+
+{{ .License }}
+{{ end }}
@@ -0,0 +1,5 @@
+{{ if .Maintainers }}
+## Maintainers
+
+{{ range .Maintainers }}- [{{ .Name }}](mailto:{{ .Email }}){{ if .Role }} - {{ .Role }}{{ end }}
+{{ end }}{{ end }}
@@ -0,0 +1,4 @@
+
+## Contents
+
+{{ toc }}
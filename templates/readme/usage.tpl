@@ -0,0 +1,5 @@
+{{ if .QuickStart }}
+## Quick start
+
+{{ .QuickStart }}
+{{ end }}
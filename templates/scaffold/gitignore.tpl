@@ -0,0 +1,8 @@
+# {{ .Name }} - generated by gopi init -scaffold
+/bin/
+{{ with .BuildDir }}/{{ . }}/
+{{ end }}*.test
+*.out
+.gopi/
+.idea/
+.vscode/
@@ -0,0 +1,5 @@
+// Package {{ .Package }}{{ if .Description }} - {{ .Summary }}{{ else }} is the {{ .Name }} library.{{ end }}
+package {{ .Package }}
+
+// Version is the version of {{ .Name }}.
+const Version = "{{ .Version }}"
@@ -0,0 +1,13 @@
+// Command {{ .Name }}{{ if .Description }}: {{ .Summary }}{{ end }}
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	flag.Parse()
+	fmt.Fprintln(os.Stderr, "{{ .Name }} {{ .Version }}")
+}
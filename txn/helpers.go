@@ -0,0 +1,57 @@
+package txn
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const journalFile = "journal.json"
+
+func readJournal(dir string) ([]entry, error) {
+	raw, err := os.ReadFile(filepath.Join(dir, journalFile))
+	if err != nil {
+		return nil, err
+	}
+	var entries []entry
+	if err = json.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("corrupted journal %s: %w", dir, err)
+	}
+	return entries, nil
+}
+
+func rollback(dir string, entries []entry) error {
+	var errs []error
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if !e.Existed {
+			if err := os.Remove(e.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		raw, err := os.ReadFile(e.Backup)
+		if err == nil {
+			err = WriteFile(e.Path, raw, e.Mode)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to restore %s: %w", e.Path, err))
+		}
+	}
+	if len(errs) > 0 {
+		return joinErrors(errs)
+	}
+	return os.RemoveAll(dir)
+}
+
+func joinErrors(errs []error) error {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
@@ -0,0 +1,58 @@
+package txn
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// New starts a transaction whose rollback journal lives in journalDir. The
+// directory is created on Commit and removed once the commit completes.
+func New(journalDir string) *Class {
+	return &Class{
+		journalDir: journalDir,
+		index:      map[string]int{},
+	}
+}
+
+var processUmask = umask()
+
+// WriteFile writes data to a temp file next to pth and renames it into place,
+// so readers (and an interrupted run) never leave a partially written file.
+// A symlinked pth is followed and its target replaced. Like os.WriteFile the
+// umask applies to perm.
+func WriteFile(pth string, data []byte, perm os.FileMode) error {
+	if target, err := filepath.EvalSymlinks(pth); err == nil {
+		pth = target
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return ReplaceFile(pth, data, perm)
+}
+
+// ReplaceFile is WriteFile without following a symlinked pth: the link
+// itself is replaced.
+func ReplaceFile(pth string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(pth), "."+filepath.Base(pth)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), perm&^os.FileMode(processUmask))
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), pth)
+}
@@ -0,0 +1,67 @@
+package txn
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFile_replace(t *testing.T) {
+	dir := t.TempDir()
+	pth := filepath.Join(dir, "README.md")
+	_ = os.WriteFile(pth, []byte("old"), 0644)
+	if err := WriteFile(pth, []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if tRead(pth) != "new" || len(entries) != 1 {
+		t.Fail()
+	}
+}
+
+func TestWriteFile_symlink(t *testing.T) {
+	dir := t.TempDir()
+	target, link := filepath.Join(dir, "target.info"), filepath.Join(dir, "pkg.info")
+	_ = os.WriteFile(target, []byte("old"), 0644)
+	if err := os.Symlink(target, link); err != nil {
+		t.Skip(err)
+	}
+	if err := WriteFile(link, []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Lstat(link); err != nil || fi.Mode()&os.ModeSymlink == 0 || tRead(target) != "new" {
+		t.Fail()
+	}
+}
+
+func TestWriteFile_missing_dir(t *testing.T) {
+	if err := WriteFile(filepath.Join(t.TempDir(), "nope", "pkg.info"), []byte("x"), 0644); err == nil {
+		t.Fail()
+	}
+}
+
+func TestWriteFile_umask(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), "pkg.info")
+	if err := WriteFile(pth, []byte("x"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(pth)
+	if err != nil || fi.Mode().Perm() != 0666&^os.FileMode(processUmask) {
+		t.Fail()
+	}
+}
+
+func TestReplaceFile_symlink(t *testing.T) {
+	dir := t.TempDir()
+	target, link := filepath.Join(dir, "target.info"), filepath.Join(dir, "state.json")
+	_ = os.WriteFile(target, []byte("old"), 0644)
+	if err := os.Symlink(target, link); err != nil {
+		t.Skip(err)
+	}
+	if err := ReplaceFile(link, []byte("new"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Lstat(link); err != nil || fi.Mode()&os.ModeSymlink != 0 || tRead(target) != "old" || tRead(link) != "new" {
+		t.Fail()
+	}
+}
@@ -0,0 +1,141 @@
+package txn
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Stage records the new content of a file. Staging the same path again
+// replaces the earlier content. Safe for concurrent use.
+func (that *Class) Stage(path string, data []byte, perm os.FileMode) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	that.mu.Lock()
+	defer that.mu.Unlock()
+	c := change{path: abs, data: data, perm: perm}
+	if i, ok := that.index[abs]; ok {
+		that.changes[i] = c
+		return nil
+	}
+	that.index[abs] = len(that.changes)
+	that.changes = append(that.changes, c)
+	return nil
+}
+
+// Staged returns the content staged for path, if any.
+func (that *Class) Staged(path string) ([]byte, bool) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, false
+	}
+	that.mu.Lock()
+	defer that.mu.Unlock()
+	if i, ok := that.index[abs]; ok {
+		return that.changes[i].data, true
+	}
+	return nil, false
+}
+
+// Validate registers a check run over every staged file before anything is written.
+func (that *Class) Validate(fn func(path string, data []byte) error) {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+	that.validators = append(that.validators, fn)
+}
+
+func (that *Class) Len() int {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+	return len(that.changes)
+}
+
+// Commit validates the whole change set, then writes each file atomically.
+// The original contents are journaled first; if any write fails every file
+// already written is restored, leaving the tree as it was before the commit.
+func (that *Class) Commit() error {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+
+	var errs []error
+	for _, c := range that.changes {
+		for _, v := range that.validators {
+			if err := v(c.path, c.data); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", c.path, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("validation failed, nothing was written: %w", joinErrors(errs))
+	}
+
+	if _, err := os.Stat(filepath.Join(that.journalDir, journalFile)); err == nil {
+		return fmt.Errorf("a previous transaction was interrupted, recover it first (journal in %s)", that.journalDir)
+	}
+	entries, err := that.journal()
+	if err != nil {
+		_ = os.RemoveAll(that.journalDir)
+		return fmt.Errorf("unable to write the rollback journal: %w", err)
+	}
+
+	for _, c := range that.changes {
+		if err = WriteFile(c.path, c.data, c.perm); err != nil {
+			err = fmt.Errorf("unable to write %s: %w", c.path, err)
+			if rbErr := rollback(that.journalDir, entries); rbErr != nil {
+				return fmt.Errorf("%w; rollback failed, journal kept in %s: %s", err, that.journalDir, rbErr.Error())
+			}
+			return fmt.Errorf("%w; all changes were rolled back", err)
+		}
+	}
+	that.changes, that.index = nil, map[string]int{}
+	return os.RemoveAll(that.journalDir)
+}
+
+func (that *Class) journal() ([]entry, error) {
+	if err := os.MkdirAll(that.journalDir, 0700); err != nil {
+		return nil, err
+	}
+	entries := make([]entry, 0, len(that.changes))
+	for i, c := range that.changes {
+		e := entry{Path: c.path, Mode: c.perm}
+		info, err := os.Stat(c.path)
+		switch {
+		case err == nil:
+			raw, err := os.ReadFile(c.path)
+			if err != nil {
+				return nil, err
+			}
+			e.Existed, e.Mode = true, info.Mode().Perm()
+			e.Backup = filepath.Join(that.journalDir, fmt.Sprintf("%d.orig", i))
+			if err = os.WriteFile(e.Backup, raw, 0600); err != nil {
+				return nil, err
+			}
+		case !errors.Is(err, fs.ErrNotExist):
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	raw, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return entries, WriteFile(filepath.Join(that.journalDir, journalFile), raw, 0600)
+}
+
+// Recover rolls back a commit interrupted by a crash, using the journal left
+// in journalDir. It reports whether there was anything to recover.
+func Recover(journalDir string) (bool, error) {
+	entries, err := readJournal(journalDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, rollback(journalDir, entries)
+}
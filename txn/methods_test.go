@@ -0,0 +1,110 @@
+package txn
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func tFiles(t *testing.T) (string, string, string) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a", "pkg.info"), filepath.Join(dir, "b", "pkg.info")
+	for _, p := range []string{a, b} {
+		_ = os.MkdirAll(filepath.Dir(p), 0755)
+		_ = os.WriteFile(p, []byte("version: 1.0.0\n"), 0644)
+	}
+	return dir, a, b
+}
+
+func tRead(p string) string {
+	raw, _ := os.ReadFile(p)
+	return string(raw)
+}
+
+func TestCommit_ok(t *testing.T) {
+	dir, a, b := tFiles(t)
+	tx := New(filepath.Join(dir, ".gopi", "txn"))
+	var wg sync.WaitGroup
+	for _, p := range []string{a, b} {
+		wg.Add(1)
+		go func(p string) {
+			defer wg.Done()
+			_ = tx.Stage(p, []byte("version: 1.1.0\n"), 0644)
+		}(p)
+	}
+	wg.Wait()
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if tRead(a) != "version: 1.1.0\n" || tRead(b) != "version: 1.1.0\n" {
+		t.Fail()
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".gopi", "txn")); err == nil {
+		t.Fatal("journal not removed")
+	}
+}
+
+func TestCommit_validation_writes_nothing(t *testing.T) {
+	dir, a, b := tFiles(t)
+	tx := New(filepath.Join(dir, ".gopi", "txn"))
+	_ = tx.Stage(a, []byte("version: 1.1.0\n"), 0644)
+	_ = tx.Stage(b, []byte("version: broken\n"), 0644)
+	tx.Validate(func(_ string, data []byte) error {
+		if strings.Contains(string(data), "broken") {
+			return errors.New("invalid version")
+		}
+		return nil
+	})
+	if err := tx.Commit(); err == nil {
+		t.Fatal("expected a validation error")
+	}
+	if tRead(a) != "version: 1.0.0\n" {
+		t.Fail()
+	}
+}
+
+func TestCommit_rollback_on_failure(t *testing.T) {
+	dir, a, _ := tFiles(t)
+	created := filepath.Join(dir, "c", "pkg.info")
+	_ = os.MkdirAll(filepath.Dir(created), 0755)
+	tx := New(filepath.Join(dir, ".gopi", "txn"))
+	_ = tx.Stage(a, []byte("version: 1.1.0\n"), 0644)
+	_ = tx.Stage(created, []byte("version: 0.1.0\n"), 0644)
+	_ = tx.Stage(filepath.Join(dir, "missing", "pkg.info"), []byte("x"), 0644)
+
+	if err := tx.Commit(); err == nil || !strings.Contains(err.Error(), "rolled back") {
+		t.Fatalf("expected a rolled back failure, got %v", err)
+	}
+	if tRead(a) != "version: 1.0.0\n" {
+		t.Fail()
+	}
+	if _, err := os.Stat(created); err == nil {
+		t.Fail()
+	}
+}
+
+func TestRecover_interrupted(t *testing.T) {
+	dir, a, _ := tFiles(t)
+	journalDir := filepath.Join(dir, ".gopi", "txn")
+	tx := New(journalDir)
+	_ = tx.Stage(a, []byte("version: 2.0.0\n"), 0644)
+	if _, err := tx.journal(); err != nil {
+		t.Fatal(err)
+	}
+	// simulate a crash after the first write
+	_ = os.WriteFile(a, []byte("version: 2.0.0\n"), 0644)
+
+	if err := New(journalDir).Commit(); err == nil {
+		t.Fatal("commit must refuse to run over an interrupted transaction")
+	}
+	recovered, err := Recover(journalDir)
+	if err != nil || !recovered || tRead(a) != "version: 1.0.0\n" {
+		t.Fail()
+	}
+	if recovered, _ = Recover(journalDir); recovered {
+		t.Fail()
+	}
+}
@@ -0,0 +1,29 @@
+package txn
+
+import (
+	"os"
+	"sync"
+)
+
+type change struct {
+	path string
+	data []byte
+	perm os.FileMode
+}
+
+// entry is one journal record: where the original content of path was saved
+// (when it existed) so that an interrupted commit can be rolled back.
+type entry struct {
+	Path    string      `json:"path"`
+	Backup  string      `json:"backup,omitempty"`
+	Existed bool        `json:"existed"`
+	Mode    os.FileMode `json:"mode"`
+}
+
+type Class struct {
+	mu         sync.Mutex
+	journalDir string
+	changes    []change
+	index      map[string]int
+	validators []func(path string, data []byte) error
+}
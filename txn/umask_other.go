@@ -0,0 +1,7 @@
+//go:build !unix
+
+package txn
+
+func umask() uint32 {
+	return 0
+}
@@ -0,0 +1,12 @@
+//go:build unix
+
+package txn
+
+import "syscall"
+
+// umask reads the process umask; setting it is the only way to query it.
+func umask() uint32 {
+	m := syscall.Umask(0)
+	syscall.Umask(m)
+	return uint32(m)
+}
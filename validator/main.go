@@ -0,0 +1,147 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"gov/version"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// New is a registry holding the built-in validators: none, required, semver,
+// url and email.
+func New() *Class {
+	return &Class{funcs: map[string]Func{
+		"none":     None,
+		"required": Required,
+		"semver":   Semver,
+		"url":      URL,
+		"email":    Email,
+	}}
+}
+
+// None accepts anything.
+func None(string) error {
+	return nil
+}
+
+// Required rejects blank values.
+func Required(st string) error {
+	if strings.TrimSpace(st) == "" {
+		return errors.New("a value is required")
+	}
+	return nil
+}
+
+// Semver accepts semantic versions like 1.2.0 or 2.0.0-rc.1.
+func Semver(st string) error {
+	st = strings.TrimSpace(st)
+	if _, err := version.New(st); err != nil || strings.HasPrefix(st, "v") {
+		return fmt.Errorf("%q is not a valid semver version", st)
+	}
+	return nil
+}
+
+// URL accepts absolute http(s) urls.
+func URL(st string) error {
+	u, err := url.Parse(strings.TrimSpace(st))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q is not a valid http(s) url", strings.TrimSpace(st))
+	}
+	return nil
+}
+
+// Email accepts a bare email address.
+func Email(st string) error {
+	st = strings.TrimSpace(st)
+	if a, err := mail.ParseAddress(st); err != nil || a.Address != st || a.Name != "" {
+		return fmt.Errorf("%q is not a valid email address", st)
+	}
+	return nil
+}
+
+// Enum accepts one of values.
+func Enum(values ...string) Func {
+	return func(st string) error {
+		st = strings.TrimSpace(st)
+		for _, v := range values {
+			if v == st {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not one of %s", st, strings.Join(values, ", "))
+	}
+}
+
+// Regexp accepts the values matching pattern, message explaining the format.
+func Regexp(pattern string, message string) Func {
+	re := regexp.MustCompile(pattern)
+	return func(st string) error {
+		if !re.MatchString(strings.TrimSpace(st)) {
+			return fmt.Errorf("%q %s", strings.TrimSpace(st), message)
+		}
+		return nil
+	}
+}
+
+// Optional accepts blank values, and the values fn accepts.
+func Optional(fn Func) Func {
+	return func(st string) error {
+		if strings.TrimSpace(st) == "" {
+			return nil
+		}
+		return fn(st)
+	}
+}
+
+// Suggest is the candidate closest to st, ignoring case, when it is close
+// enough to be a typo: at most a third of its letters (and one at least) off.
+// It is empty when nothing is that close.
+func Suggest(st string, candidates []string) string {
+	st = strings.ToLower(strings.TrimSpace(st))
+	best, bestDist := "", -1
+	for _, c := range candidates {
+		d := distance(st, strings.ToLower(c))
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	limit := len([]rune(st)) / 3
+	if limit < 1 {
+		limit = 1
+	}
+	if bestDist < 0 || bestDist > limit {
+		return ""
+	}
+	return best
+}
+
+// distance is the Levenshtein distance between a and b.
+func distance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if prev[j]+1 < cur[j] {
+				cur[j] = prev[j] + 1
+			}
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,31 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Register adds the validator fn under name, replacing a validator of the
+// same name.
+func (that *Class) Register(name string, fn Func) {
+	that.funcs[name] = fn
+}
+
+// Get is the validator registered under name.
+func (that *Class) Get(name string) (Func, error) {
+	fn, ok := that.funcs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown validator %q", name)
+	}
+	return fn, nil
+}
+
+// Names are the registered validators, sorted.
+func (that *Class) Names() []string {
+	var res []string
+	for name := range that.funcs {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,58 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNew_builtins(t *testing.T) {
+	v := New()
+	if strings.Join(v.Names(), ",") != "email,none,required,semver,url" {
+		t.Fatal(v.Names())
+	}
+	semver, err := v.Get("semver")
+	if err != nil || semver("1.2.0") != nil || semver("1.2") == nil {
+		t.Fail()
+	}
+	if _, err = v.Get("tenant"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestRegister(t *testing.T) {
+	v := New()
+	v.Register("tenant", Enum("acme", "globex"))
+	tenant, err := v.Get("tenant")
+	if err != nil || tenant("acme") != nil {
+		t.Fatal(err)
+	}
+	if err = tenant("acne"); err == nil || err.Error() != `"acne" is not one of acme, globex` {
+		t.Fatal(err)
+	}
+}
+
+func TestBuiltins(t *testing.T) {
+	if Required(" ") == nil || URL("ftp://x.io") == nil || URL("https://x.io/a") != nil {
+		t.Fail()
+	}
+	if Email("Jane <jane@acme.io>") == nil || Email("jane@acme.io") != nil {
+		t.Fail()
+	}
+	slug := Regexp(`^[a-z]+$`, "must be lowercase letters")
+	if slug("abc") != nil || slug("Abc").Error() != `"Abc" must be lowercase letters` {
+		t.Fail()
+	}
+	if Optional(slug)("") != nil || Optional(slug)("1") == nil {
+		t.Fail()
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	list := []string{"acme", "globex", "initech"}
+	if Suggest("acne", list) != "acme" || Suggest("Globx", list) != "globex" || Suggest("umbrella", list) != "" {
+		t.Fail()
+	}
+	if Suggest("acme", nil) != "" {
+		t.Fail()
+	}
+}
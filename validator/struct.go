@@ -0,0 +1,9 @@
+package validator
+
+// Func checks a value, the error tells what is wrong with it.
+type Func func(st string) error
+
+// Class is a registry of named validators.
+type Class struct {
+	funcs map[string]Func
+}
@@ -0,0 +1,60 @@
+package version
+
+import "sync"
+
+// ConstraintCache parses every distinct range string once and hands out the
+// shared compiled Constraint afterwards. It is safe for concurrent use.
+type ConstraintCache struct {
+	mu      sync.RWMutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	c   *Constraint
+	err error
+}
+
+func NewConstraintCache() *ConstraintCache {
+	return &ConstraintCache{entries: map[string]cacheEntry{}}
+}
+
+// Get returns the compiled constraint for raw. Parse errors are cached as well
+// so a bad range string is not re-parsed on every lookup.
+func (that *ConstraintCache) Get(raw string) (*Constraint, error) {
+	that.mu.RLock()
+	e, ok := that.entries[raw]
+	that.mu.RUnlock()
+	if ok {
+		return e.c, e.err
+	}
+
+	c, err := NewConstraint(raw)
+	that.mu.Lock()
+	if e, ok = that.entries[raw]; !ok {
+		e = cacheEntry{c: c, err: err}
+		that.entries[raw] = e
+	}
+	that.mu.Unlock()
+	return e.c, e.err
+}
+
+// Check is a shorthand for Get followed by Constraint.Check.
+func (that *ConstraintCache) Check(raw string, v *Class) (bool, error) {
+	c, err := that.Get(raw)
+	if err != nil {
+		return false, err
+	}
+	return c.Check(v), nil
+}
+
+func (that *ConstraintCache) Len() int {
+	that.mu.RLock()
+	defer that.mu.RUnlock()
+	return len(that.entries)
+}
+
+func (that *ConstraintCache) Reset() {
+	that.mu.Lock()
+	that.entries = map[string]cacheEntry{}
+	that.mu.Unlock()
+}
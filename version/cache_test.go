@@ -0,0 +1,49 @@
+package version
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestConstraintCache_reuse(t *testing.T) {
+	cache := NewConstraintCache()
+	a, err := cache.Get(">=1.0.0, <2.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := cache.Get(">=1.0.0, <2.0.0")
+	if a != b || cache.Len() != 1 {
+		t.Fail()
+	}
+}
+
+func TestConstraintCache_error(t *testing.T) {
+	cache := NewConstraintCache()
+	if _, err := cache.Check("not a range", MustNew("1.0.0")); err == nil {
+		t.Fail()
+	}
+	if cache.Len() != 1 {
+		t.Fail()
+	}
+}
+
+func TestConstraintCache_concurrent(t *testing.T) {
+	cache := NewConstraintCache()
+	v := MustNew("1.5.0")
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, raw := range []string{"^1.0.0", ">=1.2.0", "<1.0.0"} {
+				if _, err := cache.Check(raw, v); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if cache.Len() != 3 {
+		t.Fail()
+	}
+}
@@ -0,0 +1,45 @@
+package version
+
+import "sort"
+
+// Collection is a list of versions sortable by semver precedence. Versions of
+// equal precedence are ordered by their build metadata to keep sorting stable.
+type Collection []*Class
+
+func (that Collection) Len() int {
+	return len(that)
+}
+
+// Less orders by Compare, so nil versions sort first.
+func (that Collection) Less(i, j int) bool {
+	if c := that[i].Compare(that[j]); c != 0 || that[i] == nil {
+		return c < 0
+	}
+	return that[i].Metadata < that[j].Metadata
+}
+
+func (that Collection) Swap(i, j int) {
+	that[i], that[j] = that[j], that[i]
+}
+
+func (that Collection) Sort() {
+	sort.Sort(that)
+}
+
+// Closest returns the nearest candidate above target (the smallest positive
+// distance), i.e. the least risky upgrade, or nil when nothing is newer.
+// Prerelease candidates are only considered when target is a prerelease itself.
+// A nil target sorts first, so every candidate is above it; nil candidates are
+// never picked.
+func Closest(target *Class, candidates Collection) *Class {
+	var best *Class
+	for _, c := range candidates {
+		if c == nil || (c.Prerelease != "" && (target == nil || target.Prerelease == "")) {
+			continue
+		}
+		if c.GreaterThan(target) && (best == nil || c.LessThan(best)) {
+			best = c
+		}
+	}
+	return best
+}
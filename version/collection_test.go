@@ -0,0 +1,57 @@
+package version
+
+import "testing"
+
+func tCollection(raw ...string) Collection {
+	var c Collection
+	for _, r := range raw {
+		c = append(c, MustNew(r))
+	}
+	return c
+}
+
+func TestCollection_sort(t *testing.T) {
+	c := tCollection("2.0.0", "1.0.0+b", "1.0.0-rc.1", "1.0.0+a")
+	c.Sort()
+	if tJoin(c) != "1.0.0-rc.1 1.0.0+a 1.0.0+b 2.0.0" {
+		t.Fail()
+	}
+}
+
+func TestClosest_minimal_upgrade(t *testing.T) {
+	c := tCollection("2.0.0", "1.4.0", "1.3.1", "1.3.0", "1.3.2-rc.1")
+	if v := Closest(MustNew("1.3.0"), c); v == nil || v.String() != "1.3.1" {
+		t.Fail()
+	}
+}
+
+func TestClosest_prerelease_target(t *testing.T) {
+	c := tCollection("1.3.2", "1.3.2-rc.2")
+	if v := Closest(MustNew("1.3.2-rc.1"), c); v == nil || v.String() != "1.3.2-rc.2" {
+		t.Fail()
+	}
+}
+
+func TestClosest_none(t *testing.T) {
+	if Closest(MustNew("3.0.0"), tCollection("1.0.0", "2.0.0")) != nil {
+		t.Fail()
+	}
+}
+
+func TestCollection_sort_nil(t *testing.T) {
+	c := Collection{MustNew("1.0.0"), nil, MustNew("0.1.0"), nil}
+	c.Sort()
+	if c[0] != nil || c[1] != nil || c[2].String() != "0.1.0" || c[3].String() != "1.0.0" {
+		t.Fatal(c)
+	}
+}
+
+func TestClosest_nil(t *testing.T) {
+	c := Collection{nil, MustNew("2.0.0"), MustNew("1.0.0"), MustNew("0.1.0-rc.1")}
+	if v := Closest(nil, c); v == nil || v.String() != "1.0.0" {
+		t.Fatal(v)
+	}
+	if v := Closest(MustNew("1.0.0"), c); v == nil || v.String() != "2.0.0" {
+		t.Fatal(v)
+	}
+}
@@ -0,0 +1,308 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var isOperator = regexp.MustCompile(`^(>=|<=|!=|==|=|>|<|~|\^)$`)
+
+var isTerm = regexp.MustCompile(`^(>=|<=|!=|==|=|>|<|~|\^)?(\S+)$`)
+
+var isHyphenRange = regexp.MustCompile(`(\S+)\s+-\s+(\S+)`)
+
+var isPartial = regexp.MustCompile(`^v?(\d+|[xX*])(?:\.(\d+|[xX*]))?(?:\.(\d+|[xX*]))?$`)
+
+var separators = regexp.MustCompile(`[\s,]+`)
+
+type term struct {
+	op string
+	v  *Class
+}
+
+// Constraint is a set of version comparisons. Terms separated by commas or
+// spaces must all match (AND), groups separated by || are alternatives (OR):
+// ">=1.2.0, <2.0.0", "^1.4.2 || ~2.1.0" or the hyphen range "1.2.3 - 1.5.0".
+// Ranges are normalized on parse (partial versions and hyphen ranges become
+// plain comparisons), so String returns the canonical form.
+// Once parsed a Constraint is immutable and can be shared between goroutines.
+type Constraint struct {
+	groups [][]term
+}
+
+func NewConstraint(raw string) (*Constraint, error) {
+	var c Constraint
+	if err := c.parse(raw); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func MustConstraint(raw string) *Constraint {
+	c, err := NewConstraint(raw)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func (that *Constraint) parse(raw string) error {
+	var groups [][]term
+	for _, alt := range strings.Split(raw, "||") {
+		terms, err := parseGroup(alt)
+		if err != nil {
+			return fmt.Errorf("invalid constraint %q: %s", raw, err.Error())
+		}
+		groups = append(groups, terms)
+	}
+	that.groups = groups
+	return nil
+}
+
+func parseGroup(raw string) ([]term, error) {
+	var terms []term
+	var err error
+
+	// hyphen ranges first, as their " - " would otherwise read as separate tokens
+	raw = isHyphenRange.ReplaceAllStringFunc(raw, func(st string) string {
+		m := isHyphenRange.FindStringSubmatch(st)
+		lo, hi, e := hyphenRange(m[1], m[2])
+		if e != nil {
+			err = e
+			return ""
+		}
+		terms = append(terms, lo, hi)
+		return " "
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var pending string
+	for _, tok := range separators.Split(strings.TrimSpace(raw), -1) {
+		if tok == "" {
+			continue
+		}
+		if isOperator.MatchString(tok) {
+			pending += tok
+			continue
+		}
+		m := isTerm.FindStringSubmatch(pending + tok)
+		pending = ""
+		if m == nil {
+			return nil, fmt.Errorf("malformed term %q", tok)
+		}
+		expanded, err := expand(m[1], m[2])
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, expanded...)
+	}
+	if pending != "" {
+		return nil, fmt.Errorf("operator %q without a version", pending)
+	}
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("empty range")
+	}
+	return terms, nil
+}
+
+// partial parses a full or partial version (1, 1.2, 1.x, *) returning the
+// version with missing parts zeroed and the number of parts given.
+func partial(raw string) (*Class, int, error) {
+	if v, err := New(raw); err == nil {
+		return v, 3, nil
+	}
+	m := isPartial.FindStringSubmatch(raw)
+	if m == nil {
+		return nil, 0, fmt.Errorf("invalid version %q", raw)
+	}
+	v := &Class{}
+	n := 0
+	for i, p := range []*uint64{&v.Major, &v.Minor, &v.Patch} {
+		if m[i+1] == "" || strings.ContainsAny(m[i+1], "xX*") {
+			break
+		}
+		*p, _ = strconv.ParseUint(m[i+1], 10, 64)
+		n++
+	}
+	return v, n, nil
+}
+
+// next returns the lowest version above everything a partial version of n parts covers.
+func next(v *Class, n int) *Class {
+	switch n {
+	case 1:
+		return &Class{Major: v.Major + 1, Prerelease: "0"}
+	case 2:
+		return &Class{Major: v.Major, Minor: v.Minor + 1, Prerelease: "0"}
+	}
+	return nil
+}
+
+func expand(op string, raw string) ([]term, error) {
+	v, n, err := partial(raw)
+	if err != nil {
+		return nil, err
+	}
+	if op == "" || op == "==" {
+		op = "="
+	}
+	if n == 3 {
+		return []term{{op, v}}, nil
+	}
+	if n == 0 {
+		switch op {
+		case "=", ">=", "<=", "~", "^":
+			return []term{{">=", &Class{}}}, nil
+		}
+		return nil, fmt.Errorf("%s%s matches nothing", op, raw)
+	}
+
+	lo, hi := v, next(v, n)
+	switch op {
+	case "=", "~":
+		return []term{{">=", lo}, {"<", hi}}, nil
+	case "^":
+		if n == 2 && v.Major > 0 {
+			hi = next(v, 1)
+		}
+		return []term{{">=", lo}, {"<", hi}}, nil
+	case "!=":
+		return nil, fmt.Errorf("!= needs a full version, got %q", raw)
+	case ">":
+		return []term{{">=", hi}}, nil
+	case ">=":
+		return []term{{">=", lo}}, nil
+	case "<":
+		return []term{{"<", &Class{Major: lo.Major, Minor: lo.Minor, Prerelease: "0"}}}, nil
+	case "<=":
+		return []term{{"<", hi}}, nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", op)
+}
+
+func hyphenRange(from string, to string) (term, term, error) {
+	lo, _, err := partial(from)
+	if err != nil {
+		return term{}, term{}, err
+	}
+	hi, n, err := partial(to)
+	if err != nil {
+		return term{}, term{}, err
+	}
+	if n == 3 {
+		return term{">=", lo}, term{"<=", hi}, nil
+	}
+	if n == 0 {
+		return term{">=", lo}, term{">=", lo}, nil
+	}
+	return term{">=", lo}, term{"<", next(hi, n)}, nil
+}
+
+// Check reports whether v satisfies every term of at least one group.
+// A nil version satisfies nothing.
+func (that *Constraint) Check(v *Class) bool {
+	if v == nil {
+		return false
+	}
+	for _, g := range that.groups {
+		ok := true
+		for _, t := range g {
+			if !t.check(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (that *Constraint) String() string {
+	groups := make([]string, len(that.groups))
+	for i, g := range that.groups {
+		parts := make([]string, len(g))
+		for j, t := range g {
+			parts[j] = t.op + t.v.String()
+		}
+		groups[i] = strings.Join(parts, ", ")
+	}
+	return strings.Join(groups, " || ")
+}
+
+func (that *Constraint) MarshalText() ([]byte, error) {
+	return []byte(that.String()), nil
+}
+
+func (that *Constraint) UnmarshalText(text []byte) error {
+	return that.parse(string(text))
+}
+
+func (that *Constraint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(that.String())
+}
+
+func (that *Constraint) UnmarshalJSON(data []byte) error {
+	var st string
+	if err := json.Unmarshal(data, &st); err != nil {
+		return fmt.Errorf("version constraint must be a string: %s", err.Error())
+	}
+	return that.parse(st)
+}
+
+func (that *Constraint) MarshalYAML() (interface{}, error) {
+	return that.String(), nil
+}
+
+func (that *Constraint) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: version constraint must be a string", value.Line)
+	}
+	if err := that.parse(value.Value); err != nil {
+		return fmt.Errorf("line %d: %s", value.Line, err.Error())
+	}
+	return nil
+}
+
+func (that term) check(v *Class) bool {
+	c := v.Compare(that.v)
+	switch that.op {
+	case "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case "~":
+		return c >= 0 && v.LessThan(&Class{Major: that.v.Major, Minor: that.v.Minor + 1, Prerelease: "0"})
+	case "^":
+		return c >= 0 && v.LessThan(caretCeiling(that.v))
+	}
+	return false
+}
+
+// caretCeiling is the first version excluded by ^v: the next release that
+// changes the left-most non-zero component.
+func caretCeiling(v *Class) *Class {
+	switch {
+	case v.Major > 0:
+		return &Class{Major: v.Major + 1, Prerelease: "0"}
+	case v.Minor > 0:
+		return &Class{Minor: v.Minor + 1, Prerelease: "0"}
+	}
+	return &Class{Patch: v.Patch + 1, Prerelease: "0"}
+}
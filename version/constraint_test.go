@@ -0,0 +1,139 @@
+package version
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCompare_prerelease(t *testing.T) {
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0"}
+	for i := 1; i < len(ordered); i++ {
+		if !MustNew(ordered[i-1]).LessThan(MustNew(ordered[i])) {
+			t.Errorf("%s should sort before %s", ordered[i-1], ordered[i])
+		}
+	}
+}
+
+func TestCompare_ignores_metadata(t *testing.T) {
+	if !MustNew("1.0.0+a").Equal(MustNew("1.0.0+b")) {
+		t.Fail()
+	}
+}
+
+func TestConstraint_range(t *testing.T) {
+	c := MustConstraint(">=1.2.0, <2.0.0")
+	if !c.Check(MustNew("1.5.3")) || c.Check(MustNew("2.0.0")) || c.Check(MustNew("1.1.9")) {
+		t.Fail()
+	}
+}
+
+func TestConstraint_caret_tilde(t *testing.T) {
+	if !MustConstraint("^1.4.2").Check(MustNew("1.9.0")) || MustConstraint("^1.4.2").Check(MustNew("2.0.0-rc.1")) {
+		t.Fail()
+	}
+	if !MustConstraint("^0.2.3").Check(MustNew("0.2.9")) || MustConstraint("^0.2.3").Check(MustNew("0.3.0")) {
+		t.Fail()
+	}
+	if !MustConstraint("~1.4.2").Check(MustNew("1.4.9")) || MustConstraint("~1.4.2").Check(MustNew("1.5.0")) {
+		t.Fail()
+	}
+}
+
+func TestConstraint_invalid(t *testing.T) {
+	if _, err := NewConstraint(">=1.2.3.4"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestConstraint_yaml(t *testing.T) {
+	var doc struct {
+		Requires *Constraint `yaml:"requires"`
+	}
+	if err := yaml.Unmarshal([]byte("requires: '>= 1.0.0, < 2.0.0'\n"), &doc); err != nil {
+		t.Fatal(err)
+	}
+	out, _ := yaml.Marshal(doc)
+	if string(out) != "requires: '>=1.0.0, <2.0.0'\n" {
+		t.Fatalf("unexpected yaml: %q", out)
+	}
+	if err := yaml.Unmarshal([]byte("requires: '>= 1.0.x.y'\n"), &doc); err == nil {
+		t.Fail()
+	}
+}
+
+func TestConstraint_json(t *testing.T) {
+	var doc struct {
+		Requires *Constraint `json:"requires"`
+	}
+	if err := json.Unmarshal([]byte(`{"requires": "^1.2.0"}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+	out, _ := json.Marshal(doc)
+	if string(out) != `{"requires":"^1.2.0"}` {
+		t.Fatalf("unexpected json: %s", out)
+	}
+	if err := json.Unmarshal([]byte(`{"requires": 12}`), &doc); err == nil {
+		t.Fail()
+	}
+}
+
+func TestConstraint_hyphen(t *testing.T) {
+	c := MustConstraint("1.2.3 - 1.5.0")
+	if c.String() != ">=1.2.3, <=1.5.0" {
+		t.Fatalf("unexpected canonical form %q", c.String())
+	}
+	if !c.Check(MustNew("1.5.0")) || c.Check(MustNew("1.5.1")) || c.Check(MustNew("1.2.2")) {
+		t.Fail()
+	}
+	if MustConstraint("1.2 - 2.3").String() != ">=1.2.0, <2.4.0-0" {
+		t.Fail()
+	}
+}
+
+func TestConstraint_space_and_or(t *testing.T) {
+	c := MustConstraint(">= 1.2.0 <1.4.0 || ^2.1.0")
+	if c.String() != ">=1.2.0, <1.4.0 || ^2.1.0" {
+		t.Fatalf("unexpected canonical form %q", c.String())
+	}
+	for raw, want := range map[string]bool{"1.3.0": true, "1.4.0": false, "2.5.0": true, "3.0.0": false} {
+		if c.Check(MustNew(raw)) != want {
+			t.Errorf("%s: expected %v", raw, want)
+		}
+	}
+}
+
+func TestConstraint_partial(t *testing.T) {
+	cases := map[string]string{
+		"1.x":   ">=1.0.0, <2.0.0-0",
+		"~1.2":  ">=1.2.0, <1.3.0-0",
+		"^1.2":  ">=1.2.0, <2.0.0-0",
+		"^0.0":  ">=0.0.0, <0.1.0-0",
+		">1.2":  ">=1.3.0-0",
+		"<=1":   "<2.0.0-0",
+		"*":     ">=0.0.0",
+		"1.2.3": "=1.2.3",
+	}
+	for raw, want := range cases {
+		c, err := NewConstraint(raw)
+		if err != nil || c.String() != want {
+			t.Errorf("%s: got %v (%v), want %s", raw, c, err, want)
+		}
+	}
+}
+
+func TestConstraint_malformed(t *testing.T) {
+	for _, raw := range []string{"", ">=", "1.2.3 ||", "!=1.2", "1.2.3 - foo"} {
+		if _, err := NewConstraint(raw); err == nil {
+			t.Errorf("%q should not parse", raw)
+		}
+	}
+}
+
+func TestConstraint_check_nil(t *testing.T) {
+	if MustConstraint("<1.0.0").Check(nil) {
+		t.Fail()
+	}
+}
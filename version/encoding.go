@@ -0,0 +1,89 @@
+package version
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// object is the structured JSON form of a version.
+type object struct {
+	Major      *uint64 `json:"major"`
+	Minor      uint64  `json:"minor"`
+	Patch      uint64  `json:"patch"`
+	Prerelease string  `json:"prerelease,omitempty"`
+	Metadata   string  `json:"metadata,omitempty"`
+}
+
+// Lenient is a Class that also accepts bare JSON numbers (1, 1.2), as sent
+// by some upstream APIs. Missing components are zero: 1.2 reads as 1.2.0.
+type Lenient struct {
+	Class
+}
+
+func (that Class) MarshalJSON() ([]byte, error) {
+	return json.Marshal(that.String())
+}
+
+// UnmarshalJSON accepts the string form "1.2.3" (a leading v is allowed) or
+// the structured form {"major": 1, "minor": 2, "patch": 3}. A JSON null
+// leaves the version unchanged.
+func (that *Class) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case string(data) == "null":
+		return nil
+	case len(data) > 0 && data[0] == '"':
+		var st string
+		if err := json.Unmarshal(data, &st); err != nil {
+			return err
+		}
+		v, err := New(st)
+		if err != nil {
+			return err
+		}
+		*that = *v
+		return nil
+	case len(data) > 0 && data[0] == '{':
+		var o object
+		if err := json.Unmarshal(data, &o); err != nil {
+			return fmt.Errorf("invalid version object: %s", err.Error())
+		}
+		if o.Major == nil {
+			return fmt.Errorf("invalid version object %s: major is required", data)
+		}
+		v := Class{Major: *o.Major, Minor: o.Minor, Patch: o.Patch}
+		for _, ids := range []string{o.Prerelease, o.Metadata} {
+			if err := validIdentifiers(splitIdentifiers(ids)); err != nil {
+				return fmt.Errorf("invalid version object %s: %s", data, err.Error())
+			}
+		}
+		v.Prerelease, v.Metadata = o.Prerelease, o.Metadata
+		*that = v
+		return nil
+	}
+	return fmt.Errorf("version must be a string like \"1.2.3\" or an object, got %s", data)
+}
+
+func (that *Lenient) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || (data[0] != '-' && (data[0] < '0' || data[0] > '9')) {
+		return that.Class.UnmarshalJSON(data)
+	}
+	parts := strings.Split(string(data), ".")
+	if len(parts) > 2 {
+		return fmt.Errorf("invalid numeric version %s", data)
+	}
+	nums := make([]uint64, 2)
+	for i, p := range parts {
+		n, err := strconv.ParseUint(p, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid numeric version %s, expected major or major.minor", data)
+		}
+		nums[i] = n
+	}
+	that.Class = Class{Major: nums[0], Minor: nums[1]}
+	return nil
+}
@@ -0,0 +1,63 @@
+package version
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestClass_JSON_string(t *testing.T) {
+	var v Class
+	if err := json.Unmarshal([]byte(`"v1.2.3-rc.1+sha.abc"`), &v); err != nil || v.String() != "1.2.3-rc.1+sha.abc" {
+		t.Fail()
+	}
+	raw, err := json.Marshal(struct{ V Class }{v})
+	if err != nil || string(raw) != `{"V":"1.2.3-rc.1+sha.abc"}` {
+		t.Fatal(string(raw))
+	}
+}
+
+func TestClass_JSON_object(t *testing.T) {
+	var v Class
+	if err := json.Unmarshal([]byte(`{"major": 1, "minor": 4, "prerelease": "beta"}`), &v); err != nil || v.String() != "1.4.0-beta" {
+		t.Fail()
+	}
+	for _, raw := range []string{`{"minor": 4}`, `{"major": 1, "prerelease": "b_1"}`, `{"major": -1}`} {
+		if json.Unmarshal([]byte(raw), &v) == nil {
+			t.Fatalf("%s accepted", raw)
+		}
+	}
+}
+
+func TestClass_JSON_number_rejected(t *testing.T) {
+	var v Class
+	if err := json.Unmarshal([]byte(`1.2`), &v); err == nil {
+		t.Fail()
+	}
+}
+
+func TestClass_JSON_null(t *testing.T) {
+	var payload struct {
+		Version Class `json:"version"`
+		Loose   Lenient
+	}
+	payload.Version = Class{Major: 1, Minor: 2}
+	if err := json.Unmarshal([]byte(`{"version": null, "Loose": null}`), &payload); err != nil || payload.Version.String() != "1.2.0" {
+		t.Fatal(err)
+	}
+}
+
+func TestLenient_JSON_number(t *testing.T) {
+	var payload struct {
+		Version Lenient `json:"version"`
+	}
+	for raw, want := range map[string]string{`1`: "1.0.0", `1.2`: "1.2.0", `"1.2.3"`: "1.2.3"} {
+		if err := json.Unmarshal([]byte(`{"version": `+raw+`}`), &payload); err != nil || payload.Version.String() != want {
+			t.Fatalf("%s: %v %s", raw, err, payload.Version.String())
+		}
+	}
+	for _, raw := range []string{`-1`, `1.2e3`, `true`} {
+		if json.Unmarshal([]byte(`{"version": `+raw+`}`), &payload) == nil {
+			t.Fatalf("%s accepted", raw)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var isSemver = regexp.MustCompile("^(0|[1-9]\\d*)\\.(0|[1-9]\\d*)\\.(0|[1-9]\\d*)(?:-((?:0|[1-9]\\d*|\\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\\.(?:0|[1-9]\\d*|\\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\\+([0-9a-zA-Z-]+(?:\\.[0-9a-zA-Z-]+)*))?$")
+
+var isIdentifier = regexp.MustCompile("^[0-9a-zA-Z-]+$")
+
+func splitIdentifiers(st string) []string {
+	if st == "" {
+		return nil
+	}
+	return strings.Split(st, ".")
+}
+
+func validIdentifiers(ids []string) error {
+	for _, id := range ids {
+		if !isIdentifier.MatchString(id) {
+			return fmt.Errorf("invalid identifier %q: only [0-9A-Za-z-] allowed", id)
+		}
+	}
+	return nil
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	ia, ib := splitIdentifiers(a), splitIdentifiers(b)
+	for i := 0; i < len(ia) && i < len(ib); i++ {
+		if c := compareIdentifier(ia[i], ib[i]); c != 0 {
+			return c
+		}
+	}
+	return compareUint(uint64(len(ia)), uint64(len(ib)))
+}
+
+func compareIdentifier(a, b string) int {
+	na, errA := strconv.ParseUint(a, 10, 64)
+	nb, errB := strconv.ParseUint(b, 10, 64)
+	switch {
+	case errA == nil && errB == nil:
+		return compareUint(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func symbol(c int) string {
+	switch {
+	case c < 0:
+		return "<"
+	case c > 0:
+		return ">"
+	}
+	return "="
+}
@@ -0,0 +1,37 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrNil is returned by CompareE when either side of the comparison is nil.
+var ErrNil = errors.New("cannot compare a nil version")
+
+func New(raw string) (*Class, error) {
+	st := strings.TrimPrefix(strings.TrimSpace(raw), "v")
+	m := isSemver.FindStringSubmatch(st)
+	if m == nil {
+		return nil, fmt.Errorf("invalid semver version: %q", raw)
+	}
+
+	this := Class{Prerelease: m[4], Metadata: m[5]}
+	var err error
+	for i, p := range []*uint64{&this.Major, &this.Minor, &this.Patch} {
+		*p, err = strconv.ParseUint(m[i+1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid semver version: %q", raw)
+		}
+	}
+	return &this, nil
+}
+
+func MustNew(raw string) *Class {
+	v, err := New(raw)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
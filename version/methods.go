@@ -0,0 +1,209 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func (that *Class) String() string {
+	st := fmt.Sprintf("%d.%d.%d", that.Major, that.Minor, that.Patch)
+	if that.Prerelease != "" {
+		st += "-" + that.Prerelease
+	}
+	if that.Metadata != "" {
+		st += "+" + that.Metadata
+	}
+	return st
+}
+
+// MetadataIdentifiers returns the dot separated build metadata identifiers,
+// e.g. [sha abc123 build 7] for 1.0.0+sha.abc123.build.7
+func (that *Class) MetadataIdentifiers() []string {
+	return splitIdentifiers(that.Metadata)
+}
+
+// SetMetadata replaces the build metadata with the given identifiers.
+// Calling it without identifiers clears the metadata.
+func (that *Class) SetMetadata(ids ...string) error {
+	if err := validIdentifiers(ids); err != nil {
+		return err
+	}
+	that.Metadata = strings.Join(ids, ".")
+	return nil
+}
+
+// IncMajor bumps the major version, zeroing minor and patch. Prerelease and
+// metadata are cleared unless KeepPrerelease / KeepMetadata are given.
+func (that *Class) IncMajor(opts ...IncOption) {
+	that.Major++
+	that.Minor = 0
+	that.Patch = 0
+	that.clear(opts)
+}
+
+func (that *Class) IncMinor(opts ...IncOption) {
+	that.Minor++
+	that.Patch = 0
+	that.clear(opts)
+}
+
+func (that *Class) IncPatch(opts ...IncOption) {
+	that.Patch++
+	that.clear(opts)
+}
+
+func (that *Class) clear(opts []IncOption) {
+	var keep IncOption
+	for _, o := range opts {
+		keep |= o
+	}
+	if keep&KeepPrerelease == 0 {
+		that.Prerelease = ""
+	}
+	if keep&KeepMetadata == 0 {
+		that.Metadata = ""
+	}
+}
+
+// Compare returns -1, 0 or 1 following the semver precedence rules.
+// Build metadata does not take part in the comparison. A nil version sorts
+// before any other and equals another nil.
+func (that *Class) Compare(other *Class) int {
+	switch {
+	case that == nil && other == nil:
+		return 0
+	case that == nil:
+		return -1
+	case other == nil:
+		return 1
+	}
+	if c := compareUint(that.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := compareUint(that.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := compareUint(that.Patch, other.Patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(that.Prerelease, other.Prerelease)
+}
+
+// CompareE is Compare for callers that treat a missing version as an error:
+// it returns ErrNil instead of ordering nil versions.
+func (that *Class) CompareE(other *Class) (int, error) {
+	if that == nil || other == nil {
+		return 0, ErrNil
+	}
+	return that.Compare(other), nil
+}
+
+// Explain compares like Compare and also returns the human readable reason
+// of the outcome, e.g. "minor 3 < 4" or "prerelease rc.1 < release".
+func (that *Class) Explain(other *Class) (int, string) {
+	switch {
+	case that == nil && other == nil:
+		return 0, "both versions are missing"
+	case that == nil:
+		return -1, "a missing version sorts first"
+	case other == nil:
+		return 1, "a missing version sorts first"
+	}
+	for _, p := range []struct {
+		name string
+		a, b uint64
+	}{{"major", that.Major, other.Major}, {"minor", that.Minor, other.Minor}, {"patch", that.Patch, other.Patch}} {
+		if c := compareUint(p.a, p.b); c != 0 {
+			return c, fmt.Sprintf("%s %d %s %d", p.name, p.a, symbol(c), p.b)
+		}
+	}
+
+	c := comparePrerelease(that.Prerelease, other.Prerelease)
+	a, b := that.Prerelease, other.Prerelease
+	switch {
+	case c == 0 && that.Metadata != other.Metadata:
+		return 0, "equal, build metadata is ignored"
+	case c == 0:
+		return 0, "equal"
+	case a == "":
+		return c, fmt.Sprintf("release > prerelease %s", b)
+	case b == "":
+		return c, fmt.Sprintf("prerelease %s < release", a)
+	}
+	ia, ib := splitIdentifiers(a), splitIdentifiers(b)
+	for i := 0; i < len(ia) && i < len(ib); i++ {
+		ci := compareIdentifier(ia[i], ib[i])
+		if ci == 0 {
+			continue
+		}
+		_, errA := strconv.ParseUint(ia[i], 10, 64)
+		_, errB := strconv.ParseUint(ib[i], 10, 64)
+		how := "compared in ASCII order"
+		switch {
+		case errA == nil && errB == nil:
+			how = "compared numerically"
+		case errA == nil || errB == nil:
+			how = "numeric identifiers sort before alphanumeric ones"
+		}
+		return ci, fmt.Sprintf("prerelease %s %s %s: identifier %d %s %s %s, %s", a, symbol(ci), b, i+1, ia[i], symbol(ci), ib[i], how)
+	}
+	return c, fmt.Sprintf("prerelease %s %s %s: fewer identifiers sort first", a, symbol(c), b)
+}
+
+func (that *Class) LessThan(other *Class) bool {
+	return that.Compare(other) < 0
+}
+
+func (that *Class) GreaterThan(other *Class) bool {
+	return that.Compare(other) > 0
+}
+
+func (that *Class) Equal(other *Class) bool {
+	return that.Compare(other) == 0
+}
+
+// Follows reports whether that is prev or a release that may come right
+// after it: a later version of the same major.minor.patch (1.3.0-rc.1 ->
+// 1.3.0) or the next patch, minor or major, prereleases of those included.
+func (that *Class) Follows(prev *Class) bool {
+	if that == nil || prev == nil {
+		return false
+	}
+	c := that.Compare(prev)
+	if c <= 0 {
+		return c == 0
+	}
+	next := []Class{
+		{Major: prev.Major, Minor: prev.Minor, Patch: prev.Patch},
+		{Major: prev.Major, Minor: prev.Minor, Patch: prev.Patch + 1},
+		{Major: prev.Major, Minor: prev.Minor + 1},
+		{Major: prev.Major + 1},
+	}
+	for _, n := range next {
+		if that.Major == n.Major && that.Minor == n.Minor && that.Patch == n.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+// IncPrerelease increments the trailing numeric identifier of the prerelease
+// (rc.1 -> rc.2), appending .1 when the prerelease has none (beta -> beta.1).
+// Build metadata is cleared unless KeepMetadata is given.
+func (that *Class) IncPrerelease(opts ...IncOption) error {
+	if that.Prerelease == "" {
+		return fmt.Errorf("version %s has no prerelease to increment", that.String())
+	}
+
+	ids := splitIdentifiers(that.Prerelease)
+	last := ids[len(ids)-1]
+	if n, err := strconv.ParseUint(last, 10, 64); err == nil {
+		ids[len(ids)-1] = strconv.FormatUint(n+1, 10)
+	} else {
+		ids = append(ids, "1")
+	}
+	that.Prerelease = strings.Join(ids, ".")
+	that.clear(append(opts, KeepPrerelease))
+	return nil
+}
@@ -0,0 +1,163 @@
+package version
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNew_ok(t *testing.T) {
+	v, err := New("1.2.3-rc.1+sha.abc")
+	if err != nil {
+		t.Fail()
+	}
+	if v.Major != 1 || v.Minor != 2 || v.Patch != 3 || v.Prerelease != "rc.1" || v.Metadata != "sha.abc" {
+		t.Fail()
+	}
+	if v.String() != "1.2.3-rc.1+sha.abc" {
+		t.Fail()
+	}
+}
+
+func TestNew_invalid(t *testing.T) {
+	if _, err := New("1.2"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestMetadataIdentifiers(t *testing.T) {
+	v := MustNew("1.0.0+sha.abc123.build.7")
+	if strings.Join(v.MetadataIdentifiers(), "|") != "sha|abc123|build|7" {
+		t.Fail()
+	}
+}
+
+func TestMetadataIdentifiers_empty(t *testing.T) {
+	if len(MustNew("1.0.0").MetadataIdentifiers()) != 0 {
+		t.Fail()
+	}
+}
+
+func TestSetMetadata_ok(t *testing.T) {
+	v := MustNew("1.0.0")
+	if err := v.SetMetadata("sha", "abc", "build", "3"); err != nil {
+		t.Fail()
+	}
+	if v.String() != "1.0.0+sha.abc.build.3" {
+		t.Fail()
+	}
+}
+
+func TestSetMetadata_invalid(t *testing.T) {
+	v := MustNew("1.0.0+keep")
+	if err := v.SetMetadata("sha", "a.b"); err == nil {
+		t.Fail()
+	}
+	if v.Metadata != "keep" {
+		t.Fail()
+	}
+}
+
+func TestIncPatch_clears(t *testing.T) {
+	v := MustNew("1.3.0-rc.1+sha.abc")
+	v.IncPatch()
+	if v.String() != "1.3.1" {
+		t.Fail()
+	}
+}
+
+func TestIncPatch_keep_all(t *testing.T) {
+	v := MustNew("1.3.0-rc.1+sha.abc")
+	v.IncPatch(KeepPrerelease, KeepMetadata)
+	if v.String() != "1.3.1-rc.1+sha.abc" {
+		t.Fail()
+	}
+}
+
+func TestIncMinor_keep_metadata(t *testing.T) {
+	v := MustNew("1.3.4-rc.1+sha.abc")
+	v.IncMinor(KeepMetadata)
+	if v.String() != "1.4.0+sha.abc" {
+		t.Fail()
+	}
+}
+
+func TestIncMajor_keep_prerelease(t *testing.T) {
+	v := MustNew("1.3.4-beta+sha.abc")
+	v.IncMajor(KeepPrerelease)
+	if v.String() != "2.0.0-beta" {
+		t.Fail()
+	}
+}
+
+func TestIncPrerelease_numeric(t *testing.T) {
+	v := MustNew("2.0.0-rc.1+sha.abc")
+	if err := v.IncPrerelease(); err != nil || v.String() != "2.0.0-rc.2" {
+		t.Fail()
+	}
+}
+
+func TestIncPrerelease_append(t *testing.T) {
+	v := MustNew("2.0.0-beta+sha.abc")
+	if err := v.IncPrerelease(KeepMetadata); err != nil || v.String() != "2.0.0-beta.1+sha.abc" {
+		t.Fail()
+	}
+}
+
+func TestIncPrerelease_release(t *testing.T) {
+	if err := MustNew("2.0.0").IncPrerelease(); err == nil {
+		t.Fail()
+	}
+}
+
+func TestCompare_nil(t *testing.T) {
+	var none *Class
+	v := MustNew("0.0.0")
+	if none.Compare(nil) != 0 || none.Compare(v) != -1 || v.Compare(nil) != 1 {
+		t.Fail()
+	}
+	if !none.LessThan(v) || !v.GreaterThan(none) || v.Equal(none) {
+		t.Fail()
+	}
+}
+
+func TestCompareE_nil(t *testing.T) {
+	var none *Class
+	if _, err := none.CompareE(MustNew("1.0.0")); err != ErrNil {
+		t.Fail()
+	}
+	if c, err := MustNew("1.0.0").CompareE(MustNew("1.0.1")); err != nil || c != -1 {
+		t.Fail()
+	}
+}
+
+func TestExplain(t *testing.T) {
+	for _, tc := range []struct{ a, b, want string }{
+		{"1.3.0", "1.4.0", "minor 3 < 4"},
+		{"2.0.0", "1.9.9", "major 2 > 1"},
+		{"1.0.0-rc.1", "1.0.0", "prerelease rc.1 < release"},
+		{"1.0.0-rc.2", "1.0.0-rc.10", "prerelease rc.2 < rc.10: identifier 2 2 < 10, compared numerically"},
+		{"1.0.0-beta", "1.0.0-alpha", "prerelease beta > alpha: identifier 1 beta > alpha, compared in ASCII order"},
+		{"1.0.0-rc", "1.0.0-rc.1", "prerelease rc < rc.1: fewer identifiers sort first"},
+		{"1.0.0+a", "1.0.0+b", "equal, build metadata is ignored"},
+	} {
+		c, why := MustNew(tc.a).Explain(MustNew(tc.b))
+		if why != tc.want || c != MustNew(tc.a).Compare(MustNew(tc.b)) {
+			t.Fatalf("%s vs %s: %d %q", tc.a, tc.b, c, why)
+		}
+	}
+}
+
+func TestFollows(t *testing.T) {
+	prev := MustNew("1.2.3")
+	for raw, want := range map[string]bool{
+		"1.2.3": true, "1.2.4": true, "1.3.0": true, "2.0.0": true, "1.3.0-rc.1": true,
+		"1.2.5": false, "1.4.0": false, "3.0.0": false, "1.2.2": false, "1.3.1": false,
+	} {
+		if MustNew(raw).Follows(prev) != want {
+			t.Fatal(raw)
+		}
+	}
+	if !MustNew("1.3.0").Follows(MustNew("1.3.0-rc.2")) || MustNew("1.3.0-rc.1").Follows(MustNew("1.3.0-rc.2")) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,107 @@
+package version
+
+// MetadataPolicy decides whether build metadata makes versions distinct
+// members of a Set.
+type MetadataPolicy int
+
+const (
+	// MetadataStrip drops build metadata, so 1.0.0+a and 1.0.0+b are the same member.
+	MetadataStrip MetadataPolicy = iota
+	// MetadataDistinct keeps build metadata as part of the identity.
+	MetadataDistinct
+)
+
+type Set struct {
+	policy MetadataPolicy
+	items  map[string]*Class
+}
+
+func NewSet(policy MetadataPolicy, versions ...*Class) *Set {
+	this := &Set{policy: policy, items: map[string]*Class{}}
+	this.Add(versions...)
+	return this
+}
+
+// normalize is the member standing for v, nil for a nil version which is
+// never a member.
+func (that *Set) normalize(v *Class) *Class {
+	if v == nil {
+		return nil
+	}
+	n := *v
+	if that.policy == MetadataStrip {
+		n.Metadata = ""
+	}
+	return &n
+}
+
+// Add adds the versions to the set, nil ones are skipped.
+func (that *Set) Add(versions ...*Class) {
+	for _, v := range versions {
+		if n := that.normalize(v); n != nil {
+			that.items[n.String()] = n
+		}
+	}
+}
+
+func (that *Set) Remove(v *Class) {
+	if n := that.normalize(v); n != nil {
+		delete(that.items, n.String())
+	}
+}
+
+func (that *Set) Contains(v *Class) bool {
+	n := that.normalize(v)
+	if n == nil {
+		return false
+	}
+	_, ok := that.items[n.String()]
+	return ok
+}
+
+func (that *Set) Len() int {
+	return len(that.items)
+}
+
+// Union returns a new set, using the receiver's policy, holding the members of both sets.
+func (that *Set) Union(other *Set) *Set {
+	res := NewSet(that.policy)
+	for _, v := range that.items {
+		res.Add(v)
+	}
+	for _, v := range other.items {
+		res.Add(v)
+	}
+	return res
+}
+
+// Difference returns a new set with the members of the receiver missing from other.
+func (that *Set) Difference(other *Set) *Set {
+	res := NewSet(that.policy)
+	for _, v := range that.items {
+		if !other.Contains(v) {
+			res.Add(v)
+		}
+	}
+	return res
+}
+
+// Sorted returns the members in ascending precedence.
+func (that *Set) Sorted() Collection {
+	res := make(Collection, 0, len(that.items))
+	for _, v := range that.items {
+		c := *v
+		res = append(res, &c)
+	}
+	res.Sort()
+	return res
+}
+
+// Each calls fn for every member in ascending order until fn returns false.
+func (that *Set) Each(fn func(v *Class) bool) {
+	for _, v := range that.Sorted() {
+		if !fn(v) {
+			return
+		}
+	}
+}
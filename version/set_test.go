@@ -0,0 +1,72 @@
+package version
+
+import (
+	"strings"
+	"testing"
+)
+
+func tSet(policy MetadataPolicy, raw ...string) *Set {
+	s := NewSet(policy)
+	for _, r := range raw {
+		s.Add(MustNew(r))
+	}
+	return s
+}
+
+func tJoin(c Collection) string {
+	var parts []string
+	for _, v := range c {
+		parts = append(parts, v.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestSet_dedup_strip(t *testing.T) {
+	s := tSet(MetadataStrip, "1.0.0+a", "1.0.0+b", "0.9.0")
+	if s.Len() != 2 || !s.Contains(MustNew("1.0.0+zzz")) {
+		t.Fail()
+	}
+	if tJoin(s.Sorted()) != "0.9.0 1.0.0" {
+		t.Fail()
+	}
+}
+
+func TestSet_dedup_distinct(t *testing.T) {
+	s := tSet(MetadataDistinct, "1.0.0+b", "1.0.0+a", "1.0.0+a")
+	if s.Len() != 2 || s.Contains(MustNew("1.0.0")) {
+		t.Fail()
+	}
+	if tJoin(s.Sorted()) != "1.0.0+a 1.0.0+b" {
+		t.Fail()
+	}
+}
+
+func TestSet_union_difference(t *testing.T) {
+	a := tSet(MetadataStrip, "1.0.0", "1.1.0", "2.0.0-rc.1")
+	b := tSet(MetadataStrip, "1.1.0", "2.0.0")
+	if tJoin(a.Union(b).Sorted()) != "1.0.0 1.1.0 2.0.0-rc.1 2.0.0" {
+		t.Fail()
+	}
+	if tJoin(a.Difference(b).Sorted()) != "1.0.0 2.0.0-rc.1" {
+		t.Fail()
+	}
+}
+
+func TestSet_each_stops(t *testing.T) {
+	var seen []string
+	tSet(MetadataStrip, "3.0.0", "1.0.0", "2.0.0").Each(func(v *Class) bool {
+		seen = append(seen, v.String())
+		return len(seen) < 2
+	})
+	if strings.Join(seen, " ") != "1.0.0 2.0.0" {
+		t.Fail()
+	}
+}
+
+func TestSet_nil(t *testing.T) {
+	s := NewSet(MetadataStrip, MustNew("1.0.0"), nil)
+	s.Remove(nil)
+	if s.Len() != 1 || s.Contains(nil) || tJoin(s.Sorted()) != "1.0.0" {
+		t.Fail()
+	}
+}
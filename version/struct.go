@@ -0,0 +1,17 @@
+package version
+
+type Class struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	Prerelease string
+	Metadata   string
+}
+
+// IncOption tunes what the Inc* methods retain from the current version.
+type IncOption int
+
+const (
+	KeepPrerelease IncOption = 1 << iota
+	KeepMetadata
+)
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gov/config"
+	"gov/lib"
+	"gov/pkginfo"
+	"gov/txn"
+	"gov/workspace"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// runAll runs cmd for every package of the workspace under root. Writes are
+// staged in one transaction committed only when every package succeeded, so
+// an aggregate bump either updates all packages or none.
+func runAll(ctx context.Context, cfg *config.Class, cmd command, root string, args []string) (int, error) {
+	journal := filepath.Join(root, ".gopi", "txn")
+	recovered, err := txn.Recover(journal)
+	if err != nil {
+		return 0, err
+	}
+	if recovered {
+		fmt.Fprintln(os.Stderr, "An interrupted workspace run was rolled back.")
+	}
+
+	dirs, err := workspace.Discover(root, cfg.PkgInfoFile)
+	if err != nil {
+		return 0, err
+	}
+	if len(dirs) == 0 {
+		return 0, fmt.Errorf("no %s files found under %s", cfg.PkgInfoFile, root)
+	}
+
+	tx := txn.New(journal)
+	tx.Validate(validManifest(cfg))
+	loaded, failed := 0, 0
+	for _, dir := range dirs {
+		rel, _ := filepath.Rel(root, dir)
+		fmt.Fprintf(os.Stderr, "== %s\n", rel)
+		gopi := lib.New(cfg, lib.WithTransaction(tx))
+		if err = cmd(ctx, gopi, dir, args); err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "%s: %s\n", rel, err.Error())
+		}
+		loaded += gopi.Loaded()
+	}
+	if failed > 0 {
+		return loaded, fmt.Errorf("%d of %d packages failed, no file was written", failed, len(dirs))
+	}
+	if err = tx.Commit(); err != nil {
+		return loaded, err
+	}
+	// drop the .gopi directory again when the journal was its only content
+	_ = os.Remove(filepath.Dir(journal))
+	return loaded, nil
+}
+
+// validManifest rejects a staged package info that does not parse or is not
+// valid, so that a workspace run never commits a broken one.
+func validManifest(cfg *config.Class) func(pth string, data []byte) error {
+	return func(pth string, data []byte) error {
+		if filepath.Base(pth) != cfg.PkgInfoFile {
+			return nil
+		}
+		info, err := pkginfo.Parse(data)
+		if err != nil {
+			return err
+		}
+		var problems []string
+		for _, d := range pkginfo.Validate(info, cfg.ArchList...) {
+			problems = append(problems, d.String())
+		}
+		if len(problems) > 0 {
+			return errors.New(strings.Join(problems, "; "))
+		}
+		return nil
+	}
+}
+
+// releaseState records a workspace release as it goes, for an interrupted
+// one to resume: its arguments, the released packages (relative to the
+// workspace) and their module versions.
+type releaseState struct {
+	Args     []string          `json:"args"`
+	Done     []string          `json:"done"`
+	Released map[string]string `json:"released"`
+}
+
+// releaseAll releases every package of the workspace under root with cmd,
+// each one after the packages it depends on, their new versions required by
+// its dependencies. It stops at the first failure, a later run with the same
+// arguments resuming after the released packages. A dry run records nothing.
+func releaseAll(ctx context.Context, cfg *config.Class, cmd command, root string, args []string) (int, error) {
+	dirs, err := workspace.Discover(root, cfg.PkgInfoFile)
+	if err != nil {
+		return 0, err
+	}
+	if len(dirs) == 0 {
+		return 0, fmt.Errorf("no %s files found under %s", cfg.PkgInfoFile, root)
+	}
+	modules := map[string]string{}
+	deps := map[string][]string{}
+	for _, dir := range dirs {
+		gopi := lib.New(cfg)
+		if mod := gopi.Module(dir); mod != "" {
+			modules[mod] = dir
+		}
+		if err = gopi.GetPackage(dir); err != nil {
+			return 0, err
+		}
+		for _, d := range gopi.Dependencies {
+			deps[dir] = append(deps[dir], d.Path)
+		}
+	}
+	for dir, paths := range deps {
+		var local []string
+		for _, p := range paths {
+			if d, ok := modules[p]; ok && d != dir {
+				local = append(local, d)
+			}
+		}
+		deps[dir] = local
+	}
+	order, err := workspace.Sort(dirs, deps)
+	if err != nil {
+		return 0, err
+	}
+
+	dryRun := false
+	for _, a := range args {
+		dryRun = dryRun || a == "-dry-run" || a == "--dry-run"
+	}
+	statePath := filepath.Join(root, ".gopi", "release.json")
+	state := releaseState{Args: args, Released: map[string]string{}}
+	if raw, err := os.ReadFile(statePath); err == nil && !dryRun {
+		var saved releaseState
+		if err = json.Unmarshal(raw, &saved); err != nil {
+			return 0, fmt.Errorf("unable to read %s: %w", statePath, err)
+		}
+		if strings.Join(saved.Args, " ") != strings.Join(args, " ") {
+			return 0, fmt.Errorf("an interrupted release %q is pending in %s, run it again to resume or remove the file",
+				strings.Join(saved.Args, " "), statePath)
+		}
+		state = saved
+		fmt.Fprintf(os.Stderr, "Resuming the release, %d package(s) already released.\n", len(state.Done))
+	}
+
+	done := map[string]bool{}
+	for _, rel := range state.Done {
+		done[rel] = true
+	}
+	loaded := 0
+	for _, dir := range order {
+		rel, _ := filepath.Rel(root, dir)
+		if done[rel] {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "== %s\n", rel)
+		gopi := lib.New(cfg, lib.WithReleased(state.Released))
+		err = cmd(ctx, gopi, dir, args)
+		loaded += gopi.Loaded()
+		if err != nil {
+			if dryRun {
+				return loaded, fmt.Errorf("%s: %w", rel, err)
+			}
+			return loaded, fmt.Errorf("%s: %w; once fixed, run the release again to resume it (state in %s)", rel, err, statePath)
+		}
+		if mod := gopi.Module(dir); mod != "" {
+			state.Released[mod] = "v" + gopi.Version
+		}
+		state.Done = append(state.Done, rel)
+		if !dryRun {
+			if err = saveReleaseState(statePath, state); err != nil {
+				return loaded, err
+			}
+		}
+	}
+	if !dryRun {
+		removeReleaseState(statePath)
+	}
+	return loaded, nil
+}
+
+// ignoreAll is the .gitignore of the state directory.
+const ignoreAll = "*\n"
+
+// saveReleaseState writes state to pth, in a directory git ignores so that
+// the working tree stays clean for the next release.
+func saveReleaseState(pth string, state releaseState) error {
+	raw, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(pth)
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	ignore := filepath.Join(dir, ".gitignore")
+	if _, err = os.Stat(ignore); errors.Is(err, fs.ErrNotExist) {
+		if err = os.WriteFile(ignore, []byte(ignoreAll), 0644); err != nil {
+			return err
+		}
+	}
+	if err = txn.WriteFile(pth, append(raw, '\n'), 0644); err != nil {
+		return fmt.Errorf("unable to write %s: %w", pth, err)
+	}
+	return nil
+}
+
+// removeReleaseState drops the state of a finished release, with the
+// .gitignore written for it and the directory when nothing else is left.
+func removeReleaseState(pth string) {
+	_ = os.Remove(pth)
+	ignore := filepath.Join(filepath.Dir(pth), ".gitignore")
+	if raw, err := os.ReadFile(ignore); err == nil && string(raw) == ignoreAll {
+		_ = os.Remove(ignore)
+	}
+	_ = os.Remove(filepath.Dir(pth))
+}
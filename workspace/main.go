@@ -0,0 +1,85 @@
+package workspace
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var skipDirs = map[string]bool{"vendor": true, "node_modules": true, "testdata": true}
+
+// Discover returns the directories under root holding a package info file
+// (name or its .json variant), sorted. Hidden directories, vendor,
+// node_modules and testdata are not searched.
+func Discover(root string, name string) ([]string, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	found := map[string]bool{}
+	err = filepath.WalkDir(root, func(pth string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if pth != root && (strings.HasPrefix(d.Name(), ".") || skipDirs[d.Name()]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == name || d.Name() == name+".json" {
+			found[filepath.Dir(pth)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	dirs := make([]string, 0, len(found))
+	for dir := range found {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// Sort orders dirs so that every package comes after the packages it depends
+// on, deps listing them by directory, keeping the order of dirs otherwise. It
+// fails on a dependency cycle.
+func Sort(dirs []string, deps map[string][]string) ([]string, error) {
+	const visiting, done = 1, 2
+	state := map[string]int{}
+	var res, stack []string
+	var visit func(dir string) error
+	visit = func(dir string) error {
+		switch state[dir] {
+		case done:
+			return nil
+		case visiting:
+			i := len(stack) - 1
+			for stack[i] != dir {
+				i--
+			}
+			return fmt.Errorf("dependency cycle: %s -> %s", strings.Join(stack[i:], " -> "), dir)
+		}
+		state[dir] = visiting
+		stack = append(stack, dir)
+		for _, d := range deps[dir] {
+			if err := visit(d); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[dir] = done
+		res = append(res, dir)
+		return nil
+	}
+	for _, dir := range dirs {
+		if err := visit(dir); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,42 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	for _, p := range []string{"pkg.info", "services/api/pkg.info", "libs/util/pkg.info.json",
+		"vendor/x/pkg.info", ".git/pkg.info", "services/api/README.md"} {
+		_ = os.MkdirAll(filepath.Join(root, filepath.Dir(p)), 0755)
+		_ = os.WriteFile(filepath.Join(root, p), []byte("name: x\n"), 0644)
+	}
+	dirs, err := Discover(root, "pkg.info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{root, filepath.Join(root, "libs/util"), filepath.Join(root, "services/api")}
+	if len(dirs) != len(want) {
+		t.Fatal(dirs)
+	}
+	for i := range want {
+		if dirs[i] != want[i] {
+			t.Fatal(dirs)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	deps := map[string][]string{"api": {"util", "log"}, "log": {"util"}, "cli": {"api"}}
+	order, err := Sort([]string{"api", "cli", "log", "util", "web"}, deps)
+	if err != nil || strings.Join(order, " ") != "util log api cli web" {
+		t.Fatal(order, err)
+	}
+	deps["util"] = []string{"api"}
+	if _, err = Sort([]string{"api", "util"}, deps); err == nil || err.Error() != "dependency cycle: api -> util -> api" {
+		t.Fatal(err)
+	}
+}
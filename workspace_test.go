@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"gov/config"
+	"gov/lib"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func tConfig(t *testing.T) *config.Class {
+	cfg, err := config.New(rawConfig, rawTpl, templates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.AutoAccept, cfg.AssumeYes = true, true
+	return cfg
+}
+
+// tWorkspace writes a package per directory, its module example.com/<dir>
+// requiring the modules of requires.
+func tWorkspace(t *testing.T, requires map[string][]string) string {
+	root := t.TempDir()
+	for dir, deps := range requires {
+		info := "name: " + dir + "\nversion: 0.1.0\ntenant: acme\n"
+		mod := "module example.com/" + dir + "\n\ngo 1.19\n"
+		if len(deps) > 0 {
+			info += "dependencies:\n"
+		}
+		for _, d := range deps {
+			info += "    - path: example.com/" + d + "\n      version: v0.0.1\n"
+			mod += "\nrequire example.com/" + d + " v0.0.1\n"
+		}
+		_ = os.MkdirAll(filepath.Join(root, dir), 0755)
+		_ = os.WriteFile(filepath.Join(root, dir, "pkg.info"), []byte(info), 0644)
+		_ = os.WriteFile(filepath.Join(root, dir, "go.mod"), []byte(mod), 0644)
+	}
+	return root
+}
+
+// tRecord is a release command recording the packages it is run for, it
+// fails for the package fail.
+func tRecord(root string, released *[]string, fail string) command {
+	return func(ctx context.Context, gopi *lib.Class, dir string, args []string) error {
+		rel, _ := filepath.Rel(root, dir)
+		if rel == fail {
+			return errors.New("release failed")
+		}
+		*released = append(*released, rel)
+		return gopi.GetPackage(dir)
+	}
+}
+
+func TestReleaseAll_order(t *testing.T) {
+	root := tWorkspace(t, map[string][]string{"api": {"util"}, "util": nil, "web": {"api", "util"}})
+	var released []string
+	_, err := releaseAll(context.Background(), tConfig(t), tRecord(root, &released, ""), root, []string{"minor"})
+	if err != nil || strings.Join(released, " ") != "util api web" {
+		t.Fatal(released, err)
+	}
+	if _, err = os.Stat(filepath.Join(root, ".gopi")); err == nil {
+		t.Fatal("the release state was left behind")
+	}
+}
+
+func TestReleaseAll_cycle(t *testing.T) {
+	root := tWorkspace(t, map[string][]string{"api": {"util"}, "util": {"api"}})
+	var released []string
+	_, err := releaseAll(context.Background(), tConfig(t), tRecord(root, &released, ""), root, []string{"minor"})
+	if err == nil || !strings.Contains(err.Error(), "dependency cycle") || len(released) > 0 {
+		t.Fatal(released, err)
+	}
+}
+
+func TestReleaseAll_resume(t *testing.T) {
+	root := tWorkspace(t, map[string][]string{"api": {"util"}, "util": nil, "web": {"api"}})
+	cfg := tConfig(t)
+	var released []string
+	if _, err := releaseAll(context.Background(), cfg, tRecord(root, &released, "api"), root, []string{"minor"}); err == nil {
+		t.Fatal("the failed release succeeded")
+	}
+	raw, err := os.ReadFile(filepath.Join(root, ".gopi", "release.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state releaseState
+	if err = json.Unmarshal(raw, &state); err != nil || strings.Join(state.Done, " ") != "util" ||
+		state.Released["example.com/util"] != "v0.1.0" {
+		t.Fatal(string(raw))
+	}
+
+	if _, err = releaseAll(context.Background(), cfg, tRecord(root, &released, ""), root, []string{"patch"}); err == nil {
+		t.Fatal("a release with other arguments ran over the pending one")
+	}
+	released = nil
+	if _, err = releaseAll(context.Background(), cfg, tRecord(root, &released, ""), root, []string{"minor"}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(released, " ") != "api web" {
+		t.Fatal(released)
+	}
+	if _, err = os.Stat(filepath.Join(root, ".gopi")); err == nil {
+		t.Fatal("the release state was left behind")
+	}
+}
+
+func TestValidManifest(t *testing.T) {
+	valid := validManifest(tConfig(t))
+	if err := valid("/x/pkg.info", []byte("name: x\nversion: 1.0.0\ntenant: acme\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := valid("/x/README.md", []byte("version: [")); err != nil {
+		t.Fatal(err)
+	}
+	for _, raw := range []string{"version: [", "name: x\nversion: 1.0.0\ntenant: acme\nkeywords: [Not Valid]\n"} {
+		if valid("/x/pkg.info", []byte(raw)) == nil {
+			t.Fatalf("%q accepted", raw)
+		}
+	}
+}
+
+func TestRunAll_invalid_manifest(t *testing.T) {
+	root := tWorkspace(t, map[string][]string{"api": nil, "util": nil})
+	bump := func(ctx context.Context, gopi *lib.Class, dir string, args []string) error {
+		if err := gopi.GetPackage(dir); err != nil {
+			return err
+		}
+		gopi.Version = "0.2.0"
+		if filepath.Base(dir) == "util" {
+			gopi.Keywords = []string{"Not Valid"}
+		}
+		return gopi.CreatePkg(dir)
+	}
+	if _, err := runAll(context.Background(), tConfig(t), bump, root, nil); err == nil || !strings.Contains(err.Error(), "keywords[0]") {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{"api", "util"} {
+		raw, _ := os.ReadFile(filepath.Join(root, dir, "pkg.info"))
+		if !strings.Contains(string(raw), "version: 0.1.0") {
+			t.Fatal(string(raw))
+		}
+	}
+}